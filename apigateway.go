@@ -0,0 +1,146 @@
+package revisor
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// APIGatewayRequest is an API Gateway proxy event of Lambda function, either
+// of payload format 1.0 or 2.0, which is decoded from JSON event as it is
+type APIGatewayRequest struct {
+	// Version is 2.0 for events of payload format 2.0
+	Version string `json:"version"`
+
+	HTTPMethod                      string              `json:"httpMethod"`
+	Path                            string              `json:"path"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+
+	RawPath        string   `json:"rawPath"`
+	RawQueryString string   `json:"rawQueryString"`
+	Cookies        []string `json:"cookies"`
+	RequestContext struct {
+		HTTP struct {
+			Method string `json:"method"`
+			Path   string `json:"path"`
+		} `json:"http"`
+	} `json:"requestContext"`
+
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body"`
+	IsBase64Encoded bool              `json:"isBase64Encoded"`
+}
+
+// APIGatewayResponse is a response of Lambda function to API Gateway proxy
+// event, either of payload format 1.0 or 2.0
+type APIGatewayResponse struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	Cookies           []string            `json:"cookies"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+// ValidateAPIGatewayRequest verifies request of API Gateway proxy event with
+// verify function, e.g. returned from NewRequestVerifier, so that Lambda
+// functions check requests without constructing http.Request
+func ValidateAPIGatewayRequest(verify func(*http.Request) error, event *APIGatewayRequest) error {
+	if event == nil {
+		return ErrNilInput
+	}
+	req, err := event.request()
+	if err != nil {
+		return err
+	}
+	return verify(req)
+}
+
+// ValidateAPIGatewayExchange verifies API Gateway proxy event and response
+// of Lambda function to it with verify function, e.g. returned from NewVerifier
+func ValidateAPIGatewayExchange(verify func(*http.Response, *http.Request) error, event *APIGatewayRequest, response *APIGatewayResponse) error {
+	if event == nil || response == nil {
+		return ErrNilInput
+	}
+	req, err := event.request()
+	if err != nil {
+		return err
+	}
+	res, err := response.response(req)
+	if err != nil {
+		return err
+	}
+	return verify(res, req)
+}
+
+// request builds request of the event
+func (e *APIGatewayRequest) request() (*http.Request, error) {
+	body, err := apiGatewayBody(e.Body, e.IsBase64Encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request body")
+	}
+	header := make(http.Header)
+	var method, path, query string
+	if e.Version == "2.0" {
+		method, path, query = e.RequestContext.HTTP.Method, e.RawPath, e.RawQueryString
+		if path == "" {
+			path = e.RequestContext.HTTP.Path
+		}
+		if len(e.Cookies) != 0 {
+			header.Set("Cookie", strings.Join(e.Cookies, "; "))
+		}
+	} else {
+		method, path = e.HTTPMethod, e.Path
+		values := url.Values(e.MultiValueQueryStringParameters)
+		if len(values) == 0 {
+			values = url.Values{}
+			for k, v := range e.QueryStringParameters {
+				values.Set(k, v)
+			}
+		}
+		query = values.Encode()
+	}
+	addAPIGatewayHeaders(header, e.Headers, e.MultiValueHeaders)
+	rawURL := path
+	if query != "" {
+		rawURL += "?" + query
+	}
+	return newCapturedRequest(RequestMeta{Method: method, URL: rawURL, Header: header}, body)
+}
+
+// response builds response of Lambda function to the request
+func (r *APIGatewayResponse) response(req *http.Request) (*http.Response, error) {
+	body, err := apiGatewayBody(r.Body, r.IsBase64Encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode response body")
+	}
+	header := make(http.Header)
+	addAPIGatewayHeaders(header, r.Headers, r.MultiValueHeaders)
+	for _, cookie := range r.Cookies {
+		header.Add("Set-Cookie", cookie)
+	}
+	return newCapturedResponse(req, ResponseMeta{StatusCode: r.StatusCode, Header: header}, body), nil
+}
+
+// addAPIGatewayHeaders adds headers of an event, multi-value headers take
+// precedence over single-value headers of the same name
+func addAPIGatewayHeaders(header http.Header, single map[string]string, multi map[string][]string) {
+	for k, v := range single {
+		header.Set(k, v)
+	}
+	for k, values := range multi {
+		header[http.CanonicalHeaderKey(k)] = values
+	}
+}
+
+func apiGatewayBody(body string, base64Encoded bool) ([]byte, error) {
+	if base64Encoded {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
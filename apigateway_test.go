@@ -0,0 +1,130 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAPIGatewayRequest(t *testing.T) {
+	verify, err := NewRequestVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		event string
+		err   string
+	}{
+		{
+			"payload 1.0",
+			`{"httpMethod":"GET","path":"/v2/user/login","queryStringParameters":{"username":"u","password":"p"}}`,
+			"",
+		},
+		{
+			"payload 1.0 with multi-value query",
+			`{"httpMethod":"GET","path":"/v2/user/login",
+			  "multiValueQueryStringParameters":{"username":["u"],"password":["p"]}}`,
+			"",
+		},
+		{
+			"payload 1.0 without body",
+			`{"httpMethod":"PUT","path":"/v2/user/testuser"}`,
+			"body is empty",
+		},
+		{
+			"payload 2.0",
+			`{"version":"2.0","rawPath":"/v2/user/login","rawQueryString":"username=u&password=p",
+			  "requestContext":{"http":{"method":"GET","path":"/v2/user/login"}}}`,
+			"",
+		},
+		{
+			"payload 2.0 with base64 encoded body",
+			`{"version":"2.0","rawPath":"/v2/user/testuser","headers":{"content-type":"application/json"},
+			  "requestContext":{"http":{"method":"PUT","path":"/v2/user/testuser"}},
+			  "body":"eyJpZCI6MX0=","isBase64Encoded":true}`,
+			"",
+		},
+		{
+			"payload 2.0 without body",
+			`{"version":"2.0","rawPath":"/v2/user/testuser",
+			  "requestContext":{"http":{"method":"PUT","path":"/v2/user/testuser"}}}`,
+			"body is empty",
+		},
+		{
+			"invalid base64 body",
+			`{"httpMethod":"GET","path":"/v2/user/login","body":"!","isBase64Encoded":true}`,
+			"failed to decode request body",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event := &APIGatewayRequest{}
+			require.NoError(t, json.Unmarshal([]byte(test.event), event))
+			err := ValidateAPIGatewayRequest(verify, event)
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAPIGatewayExchange(t *testing.T) {
+	verify, err := NewVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	event := &APIGatewayRequest{HTTPMethod: "GET", Path: "/v2/user/testuser"}
+
+	tests := []struct {
+		name     string
+		response APIGatewayResponse
+		err      string
+	}{
+		{
+			"valid response",
+			APIGatewayResponse{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"Content-Type": "application/json"},
+				Body:       `{"id":1}`,
+			},
+			"",
+		},
+		{
+			"base64 encoded response",
+			APIGatewayResponse{
+				StatusCode:        http.StatusOK,
+				MultiValueHeaders: map[string][]string{"content-type": {"application/json"}},
+				Body:              "eyJpZCI6MX0=",
+				IsBase64Encoded:   true,
+			},
+			"",
+		},
+		{
+			"invalid response",
+			APIGatewayResponse{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"Content-Type": "application/json"},
+				Body:       `{"username":"testuser"}`,
+			},
+			"id in body is required",
+		},
+		{
+			"invalid base64 body",
+			APIGatewayResponse{StatusCode: http.StatusOK, Body: "!", IsBase64Encoded: true},
+			"failed to decode response body",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAPIGatewayExchange(verify, event, &test.response)
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
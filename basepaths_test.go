@@ -0,0 +1,44 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBasePaths(t *testing.T) {
+	verifyRequest, err := NewRequestVerifier(testdata+sampleV2YAML,
+		WithBasePaths("/api", "/api/v2/", "https://{region}.example.com/{version}/pets"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		path  string
+		valid bool
+	}{
+		{"first base path", "/api/store/inventory", true},
+		{"second base path", "/api/v2/store/inventory", true},
+		{"server URL with variables", "/v3/pets/store/inventory", true},
+		{"document base path is replaced", "/v2/store/inventory", false},
+		{"unknown base path", "/other/store/inventory", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyRequest(httptest.NewRequest("GET", tt.path, nil))
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, "no path template matches current request", err)
+			}
+		})
+	}
+}
+
+func TestServerPath(t *testing.T) {
+	assert.Equal(t, "/api", serverPath("/api/"))
+	assert.Equal(t, "/api/{version}", serverPath("https://{region}.example.com/api/{version}"))
+	assert.Equal(t, "", serverPath("https://example.com"))
+	assert.Equal(t, "", serverPath("/"))
+}
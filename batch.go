@@ -0,0 +1,72 @@
+package revisor
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"sync"
+)
+
+// Exchange is a recorded request and the response made to it,
+// Response is nil if only the request is verified
+type Exchange struct {
+	Request  *http.Request
+	Response *http.Response
+}
+
+// VerifyBatch verifies exchanges, e.g. HAR entries or cassette interactions,
+// concurrently with workers goroutines, which default to the number of CPUs.
+// Errors of exchanges which failed verification are returned ordered by index.
+// Once ctx is done, remaining exchanges are not verified and ctx error is returned
+// along with errors found so far.
+func (v *Verifier) VerifyBatch(ctx context.Context, exchanges []Exchange, workers int) ([]*InteractionError, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	errs := make([]error, len(exchanges))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				e := exchanges[i]
+				if e.Response == nil {
+					errs[i] = v.VerifyRequest(e.Request)
+				} else {
+					errs[i] = v.Verify(e.Response, e.Request)
+				}
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for i := range exchanges {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	var failed []*InteractionError
+	for i, verifyErr := range errs {
+		if verifyErr != nil {
+			failure := &InteractionError{Index: i, Err: verifyErr}
+			if req := exchanges[i].Request; req != nil {
+				failure.Method = req.Method
+				failure.URL = req.URL.String()
+			}
+			failed = append(failed, failure)
+		}
+	}
+	return failed, err
+}
@@ -0,0 +1,52 @@
+package revisor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_VerifyBatch(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	exchange := func(url, body string) Exchange {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		rec.WriteHeader(http.StatusOK)
+		_, _ = rec.WriteString(body)
+		return Exchange{Request: httptest.NewRequest("GET", url, nil), Response: rec.Result()}
+	}
+	var exchanges []Exchange
+	for i := 0; i < 50; i++ {
+		exchanges = append(exchanges, exchange("/v2/pet/1", `{"name": "doggie", "photoUrls": []}`))
+	}
+	exchanges[7] = exchange("/v2/pet/1", `{"name": 1, "photoUrls": []}`)
+	exchanges[31] = Exchange{Request: httptest.NewRequest("GET", "/v2/unknown", nil)}
+
+	failed, err := v.VerifyBatch(context.Background(), exchanges, 4)
+	require.NoError(t, err)
+	require.Len(t, failed, 2)
+	assert.Equal(t, 7, failed[0].Index)
+	assert.Contains(t, failed[0].Error(), "interaction 7 GET /v2/pet/1: response validation failed")
+	assert.Equal(t, 31, failed[1].Index)
+	assert.Contains(t, failed[1].Error(), "undocumented_path")
+
+	t.Run("default workers", func(t *testing.T) {
+		failed, err := v.VerifyBatch(context.Background(), exchanges[:10], 0)
+		require.NoError(t, err)
+		assert.Len(t, failed, 1)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		failed, err := v.VerifyBatch(ctx, exchanges, 4)
+		assert.Equal(t, context.Canceled, err)
+		assert.Empty(t, failed)
+	})
+}
@@ -0,0 +1,80 @@
+package revisor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// NewBinder returns a function that verifies the request and populates dst with
+// its parameters converted to types declared in OpenAPI definition. Path, query
+// and header parameters as well as the decoded body are assigned to dst fields
+// by parameter name, the same way encoding/json assigns object properties, e.g.
+//
+//	type UpdateUserParams struct {
+//		Username string `json:"username"`
+//		Body     User   `json:"body"`
+//	}
+func NewBinder(definitionPath string, options ...option) (func(req *http.Request, dst interface{}) error, error) {
+	a, err := newInitializedVerifier(definitionPath, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create binder function")
+	}
+	return a.bind, nil
+}
+
+// numberDecodingKey marks requests which JSON bodies are decoded with numbers
+// kept as json.Number
+const numberDecodingKey = contextKey("revisor.numbers")
+
+func (a *apiVerifier) bind(req *http.Request, dst interface{}) error {
+	if req == nil {
+		return ErrNilInput
+	}
+	// numbers of the body are decoded as json.Number, so that integers
+	// above 2^53 are verified and bound without loss of precision
+	pinned := a.pinSatisfiedTemplate(req.WithContext(context.WithValue(req.Context(), numberDecodingKey, true)))
+	defer restoreBody(req, pinned)
+	req = pinned
+	decoded, err := a.verifyMeasuredRequest(req)
+	if err != nil {
+		return err
+	}
+	pathDef, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	_, vars, _ := a.mapper.mapRequest(req)
+
+	bound := make(map[string]interface{})
+	for _, param := range operationParams(pathDef, operation) {
+		if param.In == "body" {
+			bound[param.Name] = decoded
+			continue
+		}
+		values, ok := parameterValues(&param, req, vars)
+		if !ok {
+			if param.Default != nil {
+				bound[param.Name] = param.Default
+			}
+			continue
+		}
+		value, err := convertParameter(&param, values)
+		if err != nil {
+			return errors.Wrap(err, "failed to convert "+param.In+" parameter "+param.Name)
+		}
+		bound[param.Name] = value
+	}
+
+	raw, err := json.Marshal(bound)
+	if err != nil {
+		return errors.Wrap(err, "failed to bind request")
+	}
+	err = json.Unmarshal(raw, dst)
+	if err != nil {
+		return errors.Wrap(err, "failed to bind request")
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinder(t *testing.T) {
+	bind, err := NewBinder(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	t.Run("binds path parameter and body", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", bytes.NewReader([]byte(`{"id":1,"username":"test-user"}`)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		var dst struct {
+			Username string   `json:"username"`
+			Body     TestUser `json:"body"`
+		}
+		require.NoError(t, bind(req, &dst))
+		assert.Equal(t, "testuser", dst.Username)
+		assert.Equal(t, TestUser{ID: 1, Username: "test-user"}, dst.Body)
+	})
+
+	t.Run("binds large integers without loss of precision", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", bytes.NewReader([]byte(`{"id":9007199254740993}`)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		var dst struct {
+			Body TestUser `json:"body"`
+		}
+		require.NoError(t, bind(req, &dst))
+		assert.Equal(t, int64(9007199254740993), dst.Body.ID)
+	})
+
+	t.Run("binds typed parameters", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", "/v2/pet/42", nil)
+		require.NoError(t, err)
+		req.Header.Set("api_key", "secret")
+
+		var dst struct {
+			PetID  int64  `json:"petId"`
+			APIKey string `json:"api_key"`
+		}
+		require.NoError(t, bind(req, &dst))
+		assert.Equal(t, int64(42), dst.PetID)
+		assert.Equal(t, "secret", dst.APIKey)
+	})
+
+	t.Run("fails to convert parameter", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", "/v2/pet/cat", nil)
+		require.NoError(t, err)
+
+		var dst struct{}
+		assert.Regexp(t, `failed to convert path parameter petId: "cat" is not an integer`, bind(req, &dst))
+	})
+
+	t.Run("request is not valid", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", nil)
+		require.NoError(t, err)
+
+		var dst struct{}
+		assert.Regexp(t, "body is empty", bind(req, &dst))
+	})
+
+	t.Run("verification is measured", func(t *testing.T) {
+		sink := &recordingSink{}
+		bind, err := NewBinder(testdata+sampleV2YAML, WithMetrics(sink))
+		require.NoError(t, err)
+		req, err := http.NewRequest("DELETE", "/v2/pet/42", nil)
+		require.NoError(t, err)
+
+		var dst struct{}
+		require.NoError(t, bind(req, &dst))
+		assert.Equal(t, []string{"verifications kind=request operation=deletePet"}, sink.counters)
+	})
+}
+
+func TestConvertParameter(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      string
+		format   string
+		items    string
+		values   []string
+		expected interface{}
+	}{
+		{"integer", "integer", "", "", []string{"1"}, int64(1)},
+		{"number", "number", "", "", []string{"1.5"}, 1.5},
+		{"boolean", "boolean", "", "", []string{"true"}, true},
+		{"string", "string", "", "", []string{"value"}, "value"},
+		{"csv array", "array", "", "integer", []string{"1,2"}, []interface{}{int64(1), int64(2)}},
+		{"pipes array", "array", "pipes", "string", []string{"a|b"}, []interface{}{"a", "b"}},
+		{"multi array", "array", "multi", "boolean", []string{"true", "false"}, []interface{}{true, false}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			param := spec.QueryParam("param").Typed(test.typ, "")
+			param.CollectionFormat = test.format
+			if test.items != "" {
+				param.Items = spec.NewItems().Typed(test.items, "")
+			}
+			converted, err := convertParameter(param, test.values)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, converted)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package revisor
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// WithValidationBudget limits time spent on decoding and validating a single
+// request or response. Once budget is exceeded, validation is abandoned and
+// reported as Violation with CodeBudgetExceeded code, so that slow validation
+// of large bodies doesn't block the request path. Bodies are read before
+// the budget starts.
+func WithValidationBudget(budget time.Duration) option {
+	return func(a *apiVerifier) {
+		a.opts.validationBudget = budget
+	}
+}
+
+// budgetKey is the context key of the context which is cancelled once
+// verification of the request is abandoned
+type budgetKey struct{}
+
+// withinBudget runs verification of the request, or the response if it is
+// not nil, and abandons it once validation budget is exceeded
+func (a *apiVerifier) withinBudget(req *http.Request, res *http.Response, verify func(*http.Request, *http.Response) error) (err error) {
+	defer recoverInternalError(&err)
+	budget := a.opts.validationBudget
+	if budget <= 0 {
+		return verify(req, res)
+	}
+	// abandoned verification stops at the next check of the budget, the
+	// context isn't derived from the request one, so that verification
+	// isn't abandoned once the client goes away
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	budgeted := context.WithValue(req.Context(), budgetKey{}, ctx)
+	// verification gets copies with buffered bodies, so that abandoned
+	// verification doesn't read them concurrently with the caller
+	if res != nil {
+		body, err := readResponseBody(res)
+		if err != nil {
+			return err
+		}
+		copied := *res
+		copied.Body = ioutil.NopCloser(bytes.NewReader(body))
+		res = &copied
+		req = req.WithContext(budgeted)
+	} else {
+		body, err := readRequestBody(req)
+		if err != nil {
+			return err
+		}
+		req = req.WithContext(budgeted)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	expired := a.opts.clock.After(budget)
+	done := make(chan error, 1)
+	go func() {
+		done <- verify(req, res)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-expired:
+		return newViolation(CodeBudgetExceeded, "skipped: budget of "+budget.String()+" exceeded")
+	}
+}
+
+// checkBudget returns error once verification of the request is abandoned,
+// so that it doesn't run further steps after its result is discarded
+func checkBudget(req *http.Request) error {
+	ctx, ok := req.Context().Value(budgetKey{}).(context.Context)
+	if ok && ctx.Err() != nil {
+		return newViolation(CodeBudgetExceeded, "abandoned: validation budget exceeded")
+	}
+	return nil
+}
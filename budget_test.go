@@ -0,0 +1,103 @@
+package revisor
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithValidationBudget(t *testing.T) {
+	tags := make([]string, 20000)
+	for i := range tags {
+		tags[i] = `{"id": 1, "name": "tag"}`
+	}
+	largePet := `{"name": "doggie", "photoUrls": [], "tags": [` + strings.Join(tags, ",") + `]}`
+	smallPet := `{"name": "doggie", "photoUrls": []}`
+
+	tests := []struct {
+		name     string
+		budget   time.Duration
+		body     string
+		wantCode string
+	}{
+		{name: "no budget", body: largePet},
+		{name: "within budget", budget: time.Minute, body: smallPet},
+		{name: "budget exceeded", budget: time.Nanosecond, body: largePet, wantCode: CodeBudgetExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verify, err := NewRequestVerifier(testdata+sampleV2YAML, WithValidationBudget(tt.budget))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "/v2/pet", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			err = verify(req)
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+			} else {
+				violation, ok := errors.Cause(err).(*Violation)
+				require.True(t, ok, "%v", err)
+				assert.Equal(t, tt.wantCode, violation.Code)
+				assert.Contains(t, violation.Message, "skipped: budget of 1ns exceeded")
+			}
+
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(body), "body is restored")
+		})
+	}
+}
+
+func TestWithValidationBudget_Abandoned(t *testing.T) {
+	clock := NewManualClock(clockEpoch)
+	started, release := make(chan struct{}), make(chan struct{})
+	validated := make(chan struct{}, 1)
+	verify, err := NewRequestVerifier(testdata+sampleV2YAML,
+		WithClock(clock),
+		WithValidationBudget(time.Second),
+		WithStages(
+			Stage{
+				Name: "slow",
+				Decoded: func(ctx *StageContext, body interface{}) (interface{}, error) {
+					close(started)
+					<-release
+					return body, nil
+				},
+			},
+			Stage{
+				Name: "next",
+				Decoded: func(ctx *StageContext, body interface{}) (interface{}, error) {
+					validated <- struct{}{}
+					return body, nil
+				},
+			},
+		),
+	)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/v2/pet", strings.NewReader(`{"name": "doggie", "photoUrls": []}`))
+	req.Header.Set("Content-Type", "application/json")
+	result := make(chan error, 1)
+	go func() {
+		result <- verify(req)
+	}()
+	<-started
+	clock.Advance(time.Second)
+	err = <-result
+	violation, ok := errors.Cause(err).(*Violation)
+	require.True(t, ok, "%v", err)
+	assert.Equal(t, CodeBudgetExceeded, violation.Code)
+
+	close(release)
+	select {
+	case <-validated:
+		t.Error("abandoned verification isn't stopped")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
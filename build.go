@@ -0,0 +1,146 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BuildRequest constructs a request of the operation identified by operationID.
+// params maps parameter names to values, which are substituted into the path
+// template, encoded into query, headers or form according to OpenAPI definition.
+// body is encoded as JSON, unless it is nil. Content-Type is set to the first
+// JSON media type consumed by the operation.
+func (v *Verifier) BuildRequest(operationID string, params map[string]interface{}, body interface{}) (*http.Request, error) {
+	a := v.verifier()
+	path, method, operation, parameters := a.operationByID(operationID)
+	if operation == nil {
+		return nil, errors.New("operation is not defined: " + operationID)
+	}
+	swagger := a.doc.Spec()
+
+	query := url.Values{}
+	header := make(http.Header)
+	form := url.Values{}
+	seen := make(map[string]bool)
+	for _, param := range parameters {
+		// operation parameters override path item ones
+		if seen[param.In+param.Name] || param.In == "body" {
+			continue
+		}
+		seen[param.In+param.Name] = true
+		value, ok := params[param.Name]
+		if !ok {
+			if param.Required {
+				return nil, errors.New("required " + param.In + " parameter is missing: " + param.Name)
+			}
+			continue
+		}
+		values := formatParameter(value)
+		switch param.In {
+		case "path":
+			path = strings.Replace(path, "{"+param.Name+"}", url.PathEscape(joinCollection(values, param.CollectionFormat)), -1)
+		case "query":
+			if param.CollectionFormat == "multi" {
+				query[param.Name] = values
+			} else {
+				query.Set(param.Name, joinCollection(values, param.CollectionFormat))
+			}
+		case "header":
+			header.Set(param.Name, joinCollection(values, param.CollectionFormat))
+		case "formData":
+			if param.CollectionFormat == "multi" {
+				form[param.Name] = values
+			} else {
+				form.Set(param.Name, joinCollection(values, param.CollectionFormat))
+			}
+		}
+	}
+
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+	var reader io.Reader
+	switch {
+	case body != nil:
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode body")
+		}
+		reader = bytes.NewReader(b)
+		header.Set("Content-Type", preferredMediaTypeOr(consumes, "application/json"))
+	case len(form) != 0 && contains(consumes, "multipart/form-data"):
+		buf := &bytes.Buffer{}
+		w := multipart.NewWriter(buf)
+		for _, name := range sortedKeys(formFields(form)) {
+			for _, value := range form[name] {
+				if err := w.WriteField(name, value); err != nil {
+					return nil, errors.Wrap(err, "failed to encode form")
+				}
+			}
+		}
+		if err := w.Close(); err != nil {
+			return nil, errors.Wrap(err, "failed to encode form")
+		}
+		reader = buf
+		header.Set("Content-Type", w.FormDataContentType())
+	case len(form) != 0:
+		reader = strings.NewReader(form.Encode())
+		header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	u := &url.URL{Path: strings.TrimSuffix(swagger.BasePath, "/") + path, RawQuery: query.Encode()}
+	if swagger.Host != "" {
+		u.Host = swagger.Host
+		u.Scheme = "http"
+		if len(swagger.Schemes) != 0 {
+			u.Scheme = swagger.Schemes[0]
+		}
+	}
+	req, err := http.NewRequest(method, u.String(), reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return req, nil
+}
+
+// formatParameter formats parameter value, slices are formatted item by item
+func formatParameter(value interface{}) []string {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []string{fmt.Sprint(value)}
+	}
+	values := make([]string, v.Len())
+	for i := range values {
+		values[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return values
+}
+
+// preferredMediaTypeOr returns preferred media type of the list or fallback if it is empty
+func preferredMediaTypeOr(mediaTypes []string, fallback string) string {
+	if len(mediaTypes) == 0 {
+		return fallback
+	}
+	return preferredMediaType(mediaTypes)
+}
+
+func formFields(form url.Values) map[string]bool {
+	fields := make(map[string]bool, len(form))
+	for name := range form {
+		fields[name] = true
+	}
+	return fields
+}
@@ -0,0 +1,88 @@
+package revisor
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_BuildRequest(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		operationID string
+		params      map[string]interface{}
+		body        interface{}
+		method      string
+		url         string
+		header      map[string]string
+		wantBody    string
+		wantErr     string
+	}{
+		{
+			name:        "path and header parameters",
+			operationID: "deletePet",
+			params:      map[string]interface{}{"petId": 42, "api_key": "secret"},
+			method:      "DELETE",
+			url:         "http://petstore.swagger.io/v2/pet/42",
+			header:      map[string]string{"Api_key": "secret"},
+		},
+		{
+			name:        "multi query parameter",
+			operationID: "findPetsByStatus",
+			params:      map[string]interface{}{"status": []string{"available", "sold"}},
+			method:      "GET",
+			url:         "http://petstore.swagger.io/v2/pet/findByStatus?status=available&status=sold",
+		},
+		{
+			name:        "json body",
+			operationID: "addPet",
+			body:        map[string]interface{}{"name": "doggie", "photoUrls": []string{}},
+			method:      "POST",
+			url:         "http://petstore.swagger.io/v2/pet",
+			header:      map[string]string{"Content-Type": "application/json"},
+			wantBody:    `{"name":"doggie","photoUrls":[]}`,
+		},
+		{
+			name:        "url encoded form",
+			operationID: "updatePetWithForm",
+			params:      map[string]interface{}{"petId": 1, "name": "rex", "status": "sold"},
+			method:      "POST",
+			url:         "http://petstore.swagger.io/v2/pet/1",
+			header:      map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			wantBody:    "name=rex&status=sold",
+		},
+		{name: "unknown operation", operationID: "unknown", wantErr: "operation is not defined: unknown"},
+		{name: "missing required parameter", operationID: "deletePet", wantErr: "required path parameter is missing: petId"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := v.BuildRequest(tt.operationID, tt.params, tt.body)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.method, req.Method)
+			assert.Equal(t, tt.url, req.URL.String())
+			for name, value := range tt.header {
+				assert.Equal(t, value, req.Header.Get(name))
+			}
+			if req.Body != nil {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantBody, string(body))
+			}
+		})
+	}
+
+	t.Run("built request passes verification", func(t *testing.T) {
+		req, err := v.BuildRequest("addPet", nil, map[string]interface{}{"name": "doggie", "photoUrls": []string{"a"}})
+		require.NoError(t, err)
+		assert.NoError(t, v.VerifyRequest(req))
+	})
+}
@@ -0,0 +1,30 @@
+package revisor
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Bundle writes OpenAPI definition loaded from definitionPath to w as a single
+// self-contained JSON document. All references, including relative references
+// to other files, are resolved the same way they are for verification, so the
+// bundle is exactly the document requests and responses are verified against.
+func Bundle(definitionPath string, w io.Writer) error {
+	a, err := newAPIVerifier(definitionPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to bundle definition")
+	}
+	return errors.Wrap(writeJSON(w, a.doc.Spec()), "failed to write bundle")
+}
+
+// writeJSON writes indented JSON encoding of v to w
+func writeJSON(w io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode document")
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,36 @@
+package revisor
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, Bundle(testdata+"bundle/api.yaml", buf))
+	assert.NotContains(t, buf.String(), "$ref")
+
+	dir, err := ioutil.TempDir("", "revisor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	bundlePath := filepath.Join(dir, "bundle.json")
+	require.NoError(t, ioutil.WriteFile(bundlePath, buf.Bytes(), 0644))
+
+	verify, err := NewVerifier(bundlePath)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	_, err = rec.WriteString(`{"tag":"dog"}`)
+	require.NoError(t, err)
+	assert.Regexp(t, "name in body is required", verify(rec.Result(), httptest.NewRequest("GET", "/v1/pets/1", nil)))
+
+	assert.Regexp(t, "failed to bundle definition", Bundle("./non-existing-file.yaml", &bytes.Buffer{}))
+}
@@ -0,0 +1,155 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// cachePolicyExt is an operation extension which declares caching of
+// successful responses of the operation, e.g.
+//
+//	x-cache-policy:
+//	  visibility: public
+//	  maxAge: 3600
+//	  mustRevalidate: true
+//
+// Visibility is either public or private, maxAge is the greatest max-age
+// allowed, and noStore forbids storing responses at all.
+const cachePolicyExt = "x-cache-policy"
+
+type cachePolicy struct {
+	Visibility     string `json:"visibility"`
+	MaxAge         *int64 `json:"maxAge"`
+	NoStore        bool   `json:"noStore"`
+	MustRevalidate bool   `json:"mustRevalidate"`
+}
+
+// CheckCachePolicy enables checks of caching headers of successful responses
+// of operations declared with x-cache-policy extension: Cache-Control header
+// must be valid and have directives of the policy, and Expires header, if
+// present, must be a valid HTTP date. Broken policies are reported as
+// Violation with CodeCachePolicy code.
+func CheckCachePolicy(a *apiVerifier) {
+	a.opts.checkCachePolicy = true
+}
+
+func operationCachePolicy(operation *spec.Operation) (*cachePolicy, bool, error) {
+	ext, ok := operation.Extensions[cachePolicyExt]
+	if !ok {
+		return nil, false, nil
+	}
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read "+cachePolicyExt)
+	}
+	var p cachePolicy
+	err = json.Unmarshal(raw, &p)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to parse "+cachePolicyExt)
+	}
+	if p.Visibility != "" && p.Visibility != "public" && p.Visibility != "private" {
+		return nil, false, errors.New("failed to parse " + cachePolicyExt + ": unknown visibility " + p.Visibility)
+	}
+	return &p, true, nil
+}
+
+// verifyCachePolicy checks caching headers of successful response
+func (a *apiVerifier) verifyCachePolicy(req *http.Request, res *http.Response) error {
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	p, ok, err := operationCachePolicy(operation)
+	if err != nil || !ok {
+		return err
+	}
+	if expires := res.Header.Get("Expires"); expires != "" {
+		if _, err := http.ParseTime(expires); err != nil {
+			return newViolation(CodeCachePolicy, "Expires is not a valid HTTP date: "+strconv.Quote(expires))
+		}
+	}
+	header := strings.Join(res.Header[http.CanonicalHeaderKey("Cache-Control")], ", ")
+	if header == "" {
+		return newViolation(CodeCachePolicy, "Cache-Control header is required")
+	}
+	directives, err := parseCacheControl(header)
+	if err != nil {
+		return err
+	}
+	var required []string
+	if p.NoStore {
+		required = append(required, "no-store")
+	}
+	if p.Visibility != "" {
+		required = append(required, p.Visibility)
+	}
+	if p.MustRevalidate {
+		required = append(required, "must-revalidate")
+	}
+	for _, directive := range required {
+		if !hasDirective(directives, directive) {
+			return newViolation(CodeCachePolicy, "Cache-Control must have "+directive+" directive")
+		}
+	}
+	if p.Visibility == "public" && hasDirective(directives, "private") ||
+		p.Visibility == "private" && hasDirective(directives, "public") {
+		return newViolation(CodeCachePolicy, "Cache-Control must be "+p.Visibility)
+	}
+	if p.MaxAge == nil {
+		return nil
+	}
+	if !hasDirective(directives, "max-age") {
+		return newViolation(CodeCachePolicy, "Cache-Control must have max-age directive")
+	}
+	for _, name := range []string{"max-age", "s-maxage"} {
+		value, ok := directives[name]
+		if !ok {
+			continue
+		}
+		age, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || age < 0 {
+			return newViolation(CodeCachePolicy, name+" must be a non-negative integer")
+		}
+		if age > *p.MaxAge {
+			return newViolation(CodeCachePolicy, name+" must not be greater than "+strconv.FormatInt(*p.MaxAge, 10))
+		}
+	}
+	return nil
+}
+
+func hasDirective(directives map[string]string, name string) bool {
+	_, ok := directives[name]
+	return ok
+}
+
+// parseCacheControl parses comma-separated directives of Cache-Control
+// header into a map of lowercase names to values
+func parseCacheControl(header string) (map[string]string, error) {
+	directives := make(map[string]string)
+	s := header
+	for {
+		name, value, rest, err := parseHeaderParam(strings.TrimLeft(s, " \t"))
+		if err != nil {
+			return nil, newViolation(CodeCachePolicy, "Cache-Control is not valid: "+strconv.Quote(header))
+		}
+		if _, ok := directives[name]; ok {
+			return nil, newViolation(CodeCachePolicy, "Cache-Control has repeated "+name+" directive")
+		}
+		directives[name] = value
+		if rest == "" {
+			return directives, nil
+		}
+		if rest[0] != ',' {
+			return nil, newViolation(CodeCachePolicy, "Cache-Control is not valid: "+strconv.Quote(header))
+		}
+		s = rest[1:]
+	}
+}
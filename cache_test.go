@@ -0,0 +1,154 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCachePolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		status       int
+		cacheControl []string
+		expires      string
+		wantErr      string
+	}{
+		{
+			name:         "public",
+			path:         "/v1/products",
+			cacheControl: []string{"public, max-age=300"},
+			expires:      "Thu, 01 Jan 2037 00:00:00 GMT",
+		},
+		{
+			name:         "several headers",
+			path:         "/v1/products",
+			cacheControl: []string{"Public", `max-age="60", s-maxage=120`},
+		},
+		{
+			name:    "missing header",
+			path:    "/v1/products",
+			wantErr: "Cache-Control header is required",
+		},
+		{
+			name:         "missing visibility",
+			path:         "/v1/products",
+			cacheControl: []string{"max-age=300"},
+			wantErr:      "Cache-Control must have public directive",
+		},
+		{
+			name:         "conflicting visibility",
+			path:         "/v1/products",
+			cacheControl: []string{"public, private, max-age=300"},
+			wantErr:      "Cache-Control must be public",
+		},
+		{
+			name:         "missing max-age",
+			path:         "/v1/products",
+			cacheControl: []string{"public"},
+			wantErr:      "Cache-Control must have max-age directive",
+		},
+		{
+			name:         "max-age over policy",
+			path:         "/v1/products",
+			cacheControl: []string{"public, max-age=301"},
+			wantErr:      "max-age must not be greater than 300",
+		},
+		{
+			name:         "s-maxage over policy",
+			path:         "/v1/products",
+			cacheControl: []string{"public, max-age=60, s-maxage=3600"},
+			wantErr:      "s-maxage must not be greater than 300",
+		},
+		{
+			name:         "invalid max-age",
+			path:         "/v1/products",
+			cacheControl: []string{"public, max-age=soon"},
+			wantErr:      "max-age must be a non-negative integer",
+		},
+		{
+			name:         "invalid header",
+			path:         "/v1/products",
+			cacheControl: []string{"public max-age=300"},
+			wantErr:      `Cache-Control is not valid: "public max-age=300"`,
+		},
+		{
+			name:         "repeated directive",
+			path:         "/v1/products",
+			cacheControl: []string{"public, max-age=300", "max-age=60"},
+			wantErr:      "Cache-Control has repeated max-age directive",
+		},
+		{
+			name:         "invalid Expires",
+			path:         "/v1/products",
+			cacheControl: []string{"public, max-age=300"},
+			expires:      "tomorrow",
+			wantErr:      `Expires is not a valid HTTP date: "tomorrow"`,
+		},
+		{
+			name:         "no-store",
+			path:         "/v1/cart",
+			cacheControl: []string{"private, no-store, must-revalidate"},
+		},
+		{
+			name:         "missing no-store",
+			path:         "/v1/cart",
+			cacheControl: []string{"private, must-revalidate"},
+			wantErr:      "Cache-Control must have no-store directive",
+		},
+		{
+			name:   "failed response",
+			path:   "/v1/cart",
+			status: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"cache.yaml", CheckCachePolicy)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			for _, value := range tt.cacheControl {
+				rec.Header().Add("Cache-Control", value)
+			}
+			if tt.expires != "" {
+				rec.Header().Set("Expires", tt.expires)
+			}
+			status := tt.status
+			if status == 0 {
+				status = 200
+			}
+			rec.WriteHeader(status)
+			err = v.verifier().verifyCachePolicy(httptest.NewRequest("GET", tt.path, nil), rec.Result())
+			wantCode := ""
+			if tt.wantErr != "" {
+				wantCode = CodeCachePolicy
+			}
+			assertVerifyErr(t, err, tt.wantErr, wantCode)
+		})
+	}
+}
+
+func TestCheckCachePolicy_VerifyResponse(t *testing.T) {
+	v, err := New(testdata+"cache.yaml", CheckCachePolicy)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.Header().Set("Cache-Control", "no-cache")
+	rec.WriteString("[]")
+	err = v.VerifyResponse(rec.Result(), httptest.NewRequest("GET", "/v1/products", nil))
+	assertVerifyErr(t, err, "Cache-Control must have public directive", CodeCachePolicy)
+}
+
+func TestCheckCachePolicy_Warning(t *testing.T) {
+	var warnings []string
+	_, err := New(testdata+"cache.yaml", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "GET /products: x-cache-policy is not enforced")
+}
@@ -0,0 +1,118 @@
+package revisor
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TryAllTemplates makes verifier check requests against every path template
+// matching the request, instead of committing to the first matched one, when
+// templates overlap. Request is valid if it satisfies any of candidate operations,
+// the first satisfied one is used to verify the response.
+func TryAllTemplates(a *apiVerifier) {
+	a.opts.tryAllTemplates = true
+}
+
+// SatisfiedTemplates returns path templates matching the request which
+// operations the request satisfies, in the order templates are tried,
+// nil request satisfies none
+func (v *Verifier) SatisfiedTemplates(req *http.Request) []string {
+	var tmpls []string
+	for _, m := range v.verifier().satisfiedTemplates(req) {
+		tmpls = append(tmpls, m.tmpl)
+	}
+	return tmpls
+}
+
+// satisfiedTemplates verifies the request against every candidate template
+func (a *apiVerifier) satisfiedTemplates(req *http.Request) []templateMatch {
+	if req == nil {
+		return nil
+	}
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil
+	}
+	// candidates are probed against the contract within validation budget,
+	// but probes aren't verifications reported in metrics, stages, semantic
+	// validators or deprecation warnings, only the verification of the pinned
+	// template is. The body is decoded once for all candidates.
+	probe := *a
+	probe.opts.tryAllTemplates = false
+	probe.opts.metrics = nil
+	probe.opts.reportCurl = false
+	probe.opts.warnDeprecated = false
+	probe.opts.stages = nil
+	probe.opts.semanticValidators = nil
+	probeReq := req.WithContext(context.WithValue(req.Context(), decodedBodiesKey, decodedBodies{}))
+
+	var satisfied []templateMatch
+	for _, m := range a.mapper.candidates(req) {
+		probeReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if _, err := probe.verifyMeasuredRequest(pinTemplate(probeReq, m)); err == nil {
+			satisfied = append(satisfied, m)
+		}
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return satisfied
+}
+
+const decodedBodiesKey = contextKey("revisor.decoded")
+
+// decodedBodies caches bodies of a probed request by content type
+type decodedBodies map[string]decodedBody
+
+type decodedBody struct {
+	decoded interface{}
+	err     error
+}
+
+// decodeRequestBody decodes the body of the request, probes of candidate
+// templates reuse the body decoded by the first probe
+func decodeRequestBody(req *http.Request, contentType string, body []byte) (interface{}, error) {
+	decode := decodeBody
+	if numbers, _ := req.Context().Value(numberDecodingKey).(bool); numbers && strings.Contains(contentType, "json") {
+		decode = func(_ string, body []byte) (interface{}, error) {
+			decoded, err := jsonNumberDecoder(body)
+			return decoded, errors.Wrap(err, "failed to decode")
+		}
+	}
+	cache, ok := req.Context().Value(decodedBodiesKey).(decodedBodies)
+	if !ok {
+		return decode(contentType, body)
+	}
+	if d, ok := cache[contentType]; ok {
+		return d.decoded, d.err
+	}
+	decoded, err := decode(contentType, body)
+	cache[contentType] = decodedBody{decoded: decoded, err: err}
+	return decoded, err
+}
+
+// pinSatisfiedTemplate binds the request to the template it is verified
+// against, so that the template is looked up once per verification. It is
+// the first candidate template the request satisfies if TryAllTemplates
+// option is set, and the best matching template otherwise.
+func (a *apiVerifier) pinSatisfiedTemplate(req *http.Request) *http.Request {
+	if _, pinned := req.Context().Value(pinnedTemplateKey).(templateMatch); pinned {
+		return req
+	}
+	if a.opts.tryAllTemplates {
+		if satisfied := a.satisfiedTemplates(req); len(satisfied) != 0 {
+			return pinTemplate(req, satisfied[0])
+		}
+	}
+	tmpl, vars, ok := a.mapper.mapRequest(req)
+	return pinTemplate(req, templateMatch{tmpl: tmpl, vars: vars, unmatched: !ok})
+}
+
+// restoreBody shares the body of the pinned copy of the request with
+// the request, as reading the copy replaces its body with a buffered one
+func restoreBody(req, pinned *http.Request) {
+	req.Body = pinned.Body
+}
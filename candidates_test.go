@@ -0,0 +1,163 @@
+package revisor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const overlappingTemplates = `
+swagger: "2.0"
+info: {title: overlapping, version: 1.0.0}
+consumes: [application/json]
+produces: [application/json]
+paths:
+  /pets/{id}:
+    put:
+      operationId: updatePet
+      parameters:
+        - {name: id, in: path, required: true, type: string}
+        - name: body
+          in: body
+          required: true
+          schema: {type: object, required: [name], properties: {name: {type: string}}}
+      responses:
+        "200": {description: ok, schema: {type: object, required: [id], properties: {id: {type: string}}}}
+  /pets/mine:
+    put:
+      operationId: updateMyPet
+      parameters:
+        - name: body
+          in: body
+          required: true
+          schema: {type: object, required: [nickname], properties: {nickname: {type: string}}}
+      responses:
+        "200": {description: ok, schema: {type: object, required: [nickname], properties: {nickname: {type: string}}}}
+`
+
+func TestTryAllTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "revisor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "overlapping.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(overlappingTemplates), 0644))
+
+	v, err := New(path, TryAllTemplates)
+	require.NoError(t, err)
+
+	request := func(body string) *http.Request {
+		req := httptest.NewRequest("PUT", "/pets/mine", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		satisfied []string
+		response  string
+	}{
+		{"templated operation", `{"name":"rex"}`, []string{"/pets/{id}"}, `{"id":"mine"}`},
+		{"literal operation", `{"nickname":"rex"}`, []string{"/pets/mine"}, `{"nickname":"rex"}`},
+		{"both operations", `{"name":"rex","nickname":"rex"}`, []string{"/pets/mine", "/pets/{id}"}, ""},
+		{"no operation", `{}`, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.satisfied, v.SatisfiedTemplates(request(tt.body)))
+			if len(tt.satisfied) == 0 {
+				assert.Error(t, v.VerifyRequest(request(tt.body)))
+				return
+			}
+			assert.NoError(t, v.VerifyRequest(request(tt.body)))
+			if len(tt.satisfied) != 1 {
+				return
+			}
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			_, err := rec.WriteString(tt.response)
+			require.NoError(t, err)
+			assert.NoError(t, v.Verify(rec.Result(), request(tt.body)))
+		})
+	}
+}
+
+func TestTryAllTemplates_Deprecated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "revisor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "overlapping.yaml")
+	deprecated := strings.Replace(overlappingTemplates, "operationId: updatePet", "operationId: updatePet\n      deprecated: true", 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(deprecated), 0644))
+
+	var logged []string
+	logf := func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+	sink := &recordingSink{}
+	v, err := New(path, TryAllTemplates, WarnDeprecated, WithLogger(logf), WithMetrics(sink))
+	require.NoError(t, err)
+	logged = nil
+
+	req := httptest.NewRequest("PUT", "/pets/mine", strings.NewReader(`{"name":"rex"}`))
+	req.Header.Set("Content-Type", "application/json")
+	require.NoError(t, v.VerifyRequest(req))
+
+	assert.Equal(t, []string{"revisor: warning: deprecated operation updatePet is invoked"}, logged)
+	assert.Equal(t, []string{
+		"verifications kind=request operation=updatePet",
+		"deprecated_calls kind=request operation=updatePet",
+	}, sink.counters)
+}
+
+func TestTryAllTemplates_Probes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "revisor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "overlapping.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(overlappingTemplates), 0644))
+
+	var reported, validated int
+	stage := Stage{Name: "count", Report: func(ctx *StageContext, err error) error {
+		reported++
+		return err
+	}}
+	semantic := func(decoded interface{}, params Params) error {
+		validated++
+		return nil
+	}
+	v, err := New(path, TryAllTemplates, WithStages(stage),
+		WithSemanticValidator("updatePet", semantic), WithSemanticValidator("updateMyPet", semantic))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("PUT", "/pets/mine", strings.NewReader(`{"name":"rex","nickname":"rex"}`))
+	req.Header.Set("Content-Type", "application/json")
+	require.NoError(t, v.VerifyRequest(req))
+	assert.Equal(t, 1, reported, "stages report the pinned verification only")
+	assert.Equal(t, 1, validated, "semantic validators run for the pinned verification only")
+}
+
+func TestDecodeRequestBody(t *testing.T) {
+	req := httptest.NewRequest("PUT", "/pets/mine", nil)
+	probeReq := req.WithContext(context.WithValue(req.Context(), decodedBodiesKey, decodedBodies{}))
+
+	decoded, err := decodeRequestBody(probeReq, "application/json", []byte(`{"name":"rex"}`))
+	require.NoError(t, err)
+	decoded.(map[string]interface{})["probed"] = true
+	decoded, err = decodeRequestBody(probeReq, "application/json", []byte(`{"name":"rex"}`))
+	require.NoError(t, err)
+	assert.Equal(t, true, decoded.(map[string]interface{})["probed"], "body is decoded once")
+
+	decoded, err = decodeRequestBody(req, "application/json", []byte(`{"name":"rex"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "rex"}, decoded)
+}
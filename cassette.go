@@ -0,0 +1,108 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+)
+
+// cassette is a go-vcr compatible YAML fixture of recorded HTTP interactions
+type cassette struct {
+	Version      int `json:"version"`
+	Interactions []struct {
+		Request struct {
+			Body    string              `json:"body"`
+			Headers map[string][]string `json:"headers"`
+			URL     string              `json:"url"`
+			Method  string              `json:"method"`
+		} `json:"request"`
+		Response struct {
+			Body    string              `json:"body"`
+			Headers map[string][]string `json:"headers"`
+			Status  string              `json:"status"`
+			Code    int                 `json:"code"`
+		} `json:"response"`
+	} `json:"interactions"`
+}
+
+// InteractionError is returned for recorded interaction which failed verification
+type InteractionError struct {
+	// Index of the interaction in the fixture
+	Index  int
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *InteractionError) Error() string {
+	return "interaction " + strconv.Itoa(e.Index) + " " + e.Method + " " + e.URL + ": " + e.Err.Error()
+}
+
+// ReplayCassette loads go-vcr (or similar YAML cassette) fixture and replays
+// every recorded interaction through verify function, e.g. returned from
+// NewVerifier. Errors of interactions which failed verification are returned,
+// second return parameter is an error of loading the fixture.
+func ReplayCassette(cassettePath string, verify func(*http.Response, *http.Request) error) ([]*InteractionError, error) {
+	reqs, ress, err := loadCassette(cassettePath)
+	if err != nil {
+		return nil, err
+	}
+	var failed []*InteractionError
+	for i := range reqs {
+		err = verify(ress[i], reqs[i])
+		if err != nil {
+			failed = append(failed, &InteractionError{
+				Index:  i,
+				Method: reqs[i].Method,
+				URL:    reqs[i].URL.String(),
+				Err:    err,
+			})
+		}
+	}
+	return failed, nil
+}
+
+// loadCassette returns recorded requests and corresponding responses
+func loadCassette(cassettePath string) ([]*http.Request, []*http.Response, error) {
+	raw, err := swag.LoadFromFileOrHTTP(cassettePath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to load cassette")
+	}
+	yamlDoc, err := swag.BytesToYAMLDoc(raw)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse cassette")
+	}
+	rawJSON, err := swag.YAMLToJSON(yamlDoc)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to convert cassette to json")
+	}
+	var c cassette
+	err = json.Unmarshal(rawJSON, &c)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode cassette")
+	}
+
+	reqs := make([]*http.Request, 0, len(c.Interactions))
+	ress := make([]*http.Response, 0, len(c.Interactions))
+	for i, interaction := range c.Interactions {
+		req, err := newCapturedRequest(RequestMeta{
+			Method: interaction.Request.Method,
+			URL:    interaction.Request.URL,
+			Header: interaction.Request.Headers,
+		}, []byte(interaction.Request.Body))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to load interaction "+strconv.Itoa(i))
+		}
+		res := newCapturedResponse(req, ResponseMeta{
+			StatusCode: interaction.Response.Code,
+			Header:     interaction.Response.Headers,
+		}, []byte(interaction.Response.Body))
+		res.Status = interaction.Response.Status
+		reqs = append(reqs, req)
+		ress = append(ress, res)
+	}
+	return reqs, ress, nil
+}
@@ -0,0 +1,35 @@
+package revisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayCassette(t *testing.T) {
+	verifier, err := NewVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	failed, err := ReplayCassette(testdata+"cassette.yaml", verifier)
+	require.NoError(t, err)
+	require.Len(t, failed, 2)
+
+	assert.Equal(t, 1, failed[0].Index)
+	assert.Equal(t, "PUT", failed[0].Method)
+	assert.Equal(t, "http://petstore.swagger.io/v2/user/testuser", failed[0].URL)
+	assert.Regexp(t, "validation failed", failed[0])
+
+	assert.Equal(t, 2, failed[1].Index)
+	assert.Regexp(t, `(?s)interaction 2 GET .*: response validation failed.*id in body is required`, failed[1])
+
+	t.Run("cassette not found", func(t *testing.T) {
+		_, err := ReplayCassette("./non-existing-cassette.yaml", verifier)
+		assert.Regexp(t, "failed to load cassette", err)
+	})
+
+	t.Run("invalid cassette", func(t *testing.T) {
+		_, err := ReplayCassette(testdata+"invalid.yaml", verifier)
+		assert.Regexp(t, "failed to parse cassette", err)
+	})
+}
@@ -0,0 +1,122 @@
+package revisor
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock is a source of time of subsystems which measure or wait for it: rate
+// limiting, recording, metrics, validation budget and webhook retries. Tests
+// can replace it with ManualClock to make them deterministic.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	// After returns a channel receiving the current time once d elapses
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is a Clock backed by the system time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// ManualClock is a Clock which time moves only when it is advanced,
+// sleeping advances it immediately
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []manualTimer
+}
+
+// manualTimer is a channel waiting for ManualClock to reach the deadline
+type manualTimer struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+// NewManualClock returns ManualClock set to now
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns current time of the clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the clock by d without blocking
+func (c *ManualClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// After returns a channel receiving the time of the clock once it is
+// advanced by d
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := manualTimer{deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- c.now
+		return timer.c
+	}
+	c.timers = append(c.timers, timer)
+	return timer.c
+}
+
+// Advance moves the clock forward by d and fires timers which deadline
+// is reached
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- c.now
+	}
+	c.timers = pending
+}
+
+// WithClock sets a source of time used to measure verification for metrics,
+// to enforce validation budget and to measure handling time against SLO
+func WithClock(clock Clock) option {
+	return func(a *apiVerifier) {
+		a.opts.clock = clock
+	}
+}
+
+// WithRandomSource sets a source of random numbers used to sample responses
+// verified with ValidatedResponseWriter, see WithSampleRate
+func WithRandomSource(src rand.Source) option {
+	return func(a *apiVerifier) {
+		a.opts.random = lockedFloat64(src)
+	}
+}
+
+// lockedFloat64 returns a function drawing float64 numbers from src, which
+// is safe for concurrent use, as rand.Rand is not, unlike the global source
+func lockedFloat64(src rand.Source) func() float64 {
+	random := rand.New(src)
+	mu := sync.Mutex{}
+	return func() float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return random.Float64()
+	}
+}
@@ -0,0 +1,106 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var clockEpoch = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestManualClock(t *testing.T) {
+	clock := NewManualClock(clockEpoch)
+	assert.Equal(t, clockEpoch, clock.Now())
+	clock.Advance(time.Minute)
+	clock.Sleep(time.Second)
+	assert.Equal(t, clockEpoch.Add(time.Minute+time.Second), clock.Now())
+}
+
+func TestNewMemoryRateLimitStoreWithClock(t *testing.T) {
+	clock := NewManualClock(clockEpoch)
+	store := NewMemoryRateLimitStoreWithClock(clock)
+
+	for i := int64(1); i <= 2; i++ {
+		count, err := store.Incr("key", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+	clock.Advance(time.Minute)
+	count, err := store.Incr("key", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestHARRecorder_Deterministic(t *testing.T) {
+	record := func() []harEntry {
+		recorder := NewHARRecorder(func(*http.Response, *http.Request) error { return nil }, 0.5, 0)
+		recorder.SetClock(NewManualClock(clockEpoch))
+		recorder.SetRandomSource(rand.NewSource(1))
+		for i := 0; i < 20; i++ {
+			rec := httptest.NewRecorder()
+			_ = recorder.Verify(rec.Result(), httptest.NewRequest("GET", "/v2/pet/1", nil))
+		}
+		return recorder.har.Log.Entries
+	}
+
+	entries := record()
+	require.NotEmpty(t, entries)
+	assert.True(t, len(entries) < 20, "valid exchanges are sampled")
+	assert.Equal(t, clockEpoch.Format(time.RFC3339Nano), entries[0].StartedDateTime)
+	assert.Equal(t, entries, record())
+}
+
+func TestWebhookNotifier_SetClock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	clock := NewManualClock(clockEpoch)
+	n := NewWebhookNotifier(ts.URL, failingVerify, false)
+	n.SetClock(clock)
+	_ = n.Verify(nil, httptest.NewRequest("GET", "/pet", nil))
+	assert.Error(t, n.Flush())
+	assert.NoError(t, n.Flush(), "retry is backed off")
+	clock.Advance(webhookBackoff)
+	assert.Error(t, n.Flush(), "backoff is measured with the clock")
+}
+
+func TestWithClock(t *testing.T) {
+	sink := &timingSink{}
+	verify, err := NewRequestVerifier(testdata+sampleV2YAML, WithMetrics(sink), WithClock(NewManualClock(clockEpoch)))
+	require.NoError(t, err)
+	_ = verify(httptest.NewRequest("GET", "/v2/pet/1", nil))
+	assert.Equal(t, []time.Duration{0}, sink.durations)
+}
+
+type timingSink struct {
+	durations []time.Duration
+}
+
+func (s *timingSink) IncrCounter(string, map[string]string, int64) {}
+
+func (s *timingSink) Timing(_ string, _ map[string]string, d time.Duration) {
+	s.durations = append(s.durations, d)
+}
+
+func TestNewGeneratorFromSource(t *testing.T) {
+	schema := &spec.Schema{}
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "string", "pattern": "^[a-z]{5}$"}`), schema))
+
+	var first, second bytes.Buffer
+	g1, g2 := NewGeneratorFromSource(rand.NewSource(9)), NewGenerator(9)
+	for i := 0; i < 5; i++ {
+		first.WriteString(g1.Value(schema).(string))
+		second.WriteString(g2.Value(schema).(string))
+	}
+	assert.Equal(t, first.String(), second.String())
+}
@@ -0,0 +1,292 @@
+package revisor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// maxCompatibilityDepth limits how deep nested schemas are compared,
+// so that recursive definitions are not followed forever
+const maxCompatibilityDepth = 32
+
+// Incompatibility describes an operation constraint of the other definition
+// which may reject requests satisfying the verifier definition
+type Incompatibility struct {
+	Method  string
+	Path    string
+	Message string
+}
+
+func (i Incompatibility) String() string {
+	return i.Method + " " + i.Path + ": " + i.Message
+}
+
+// CompatibleWith checks if every request valid under the verifier definition
+// is also valid under the definition of other, e.g. if requests of a consumer
+// are accepted by a provider. It returns incompatibilities found, which is
+// empty if definitions are compatible. Body properties not declared by the
+// verifier definition are assumed to be absent from requests.
+func (v *Verifier) CompatibleWith(other *Verifier) []Incompatibility {
+	consumer, provider := v.verifier().doc.Spec(), other.verifier().doc.Spec()
+
+	providerPaths := make(map[string]string)
+	for path := range provider.Paths.Paths {
+		providerPaths[normalizePathTemplate(provider.BasePath, path)] = path
+	}
+
+	var incompatibilities []Incompatibility
+	for _, path := range sortedPaths(consumer) {
+		pathItem := consumer.Paths.Paths[path]
+		providerPath, found := providerPaths[normalizePathTemplate(consumer.BasePath, path)]
+		providerItem := provider.Paths.Paths[providerPath]
+		for _, method := range httpMethods {
+			operation := pathOperation(method, &pathItem)
+			if operation == nil {
+				continue
+			}
+			var providerOperation *spec.Operation
+			if found {
+				providerOperation = pathOperation(method, &providerItem)
+			}
+			if providerOperation == nil {
+				incompatibilities = append(incompatibilities, Incompatibility{
+					Method: method, Path: path, Message: "operation is not defined",
+				})
+				continue
+			}
+			for _, message := range compareOperations(
+				consumer, path, operationParams(&pathItem, operation), operation,
+				provider, providerPath, operationParams(&providerItem, providerOperation), providerOperation,
+			) {
+				incompatibilities = append(incompatibilities, Incompatibility{Method: method, Path: path, Message: message})
+			}
+		}
+	}
+	return incompatibilities
+}
+
+// normalizePathTemplate returns full path template with variable names removed
+func normalizePathTemplate(basePath, path string) string {
+	return pathVariable.ReplaceAllString(strings.TrimSuffix(strings.TrimSuffix(basePath, "/")+path, "/"), "{}")
+}
+
+func compareOperations(
+	consumer *spec.Swagger, path string, params []spec.Parameter, operation *spec.Operation,
+	provider *spec.Swagger, providerPath string, providerParams []spec.Parameter, providerOperation *spec.Operation,
+) []string {
+	var messages []string
+
+	consumes, providerConsumes := operation.Consumes, providerOperation.Consumes
+	if len(consumes) == 0 {
+		consumes = consumer.Consumes
+	}
+	if len(providerConsumes) == 0 {
+		providerConsumes = provider.Consumes
+	}
+	if len(providerConsumes) != 0 {
+		for _, mediaType := range consumes {
+			if !contains(providerConsumes, mediaType) {
+				messages = append(messages, "content type "+mediaType+" is not accepted")
+			}
+		}
+	}
+
+	// path variables are matched by position, since their names may differ
+	pathNames := make(map[string]string)
+	consumerVars, providerVars := pathVariable.FindAllString(path, -1), pathVariable.FindAllString(providerPath, -1)
+	for i := range consumerVars {
+		if i < len(providerVars) {
+			pathNames[strings.Trim(providerVars[i], "{}")] = strings.Trim(consumerVars[i], "{}")
+		}
+	}
+	find := func(in, name string) *spec.Parameter {
+		if in == "path" {
+			name = pathNames[name]
+		}
+		for i := range params {
+			if params[i].In == in && params[i].Name == name {
+				return &params[i]
+			}
+		}
+		return nil
+	}
+
+	for i := range providerParams {
+		providerParam := &providerParams[i]
+		param := find(providerParam.In, providerParam.Name)
+		if param == nil {
+			if providerParam.Required {
+				messages = append(messages, providerParam.In+" parameter "+providerParam.Name+" is required")
+			}
+			continue
+		}
+		if providerParam.Required && !param.Required {
+			messages = append(messages, providerParam.In+" parameter "+providerParam.Name+" is required")
+		}
+		if providerParam.In == "body" {
+			if param.Schema != nil && providerParam.Schema != nil {
+				messages = append(messages, compareSchemas("body", param.Schema, providerParam.Schema, 0)...)
+			}
+			continue
+		}
+		messages = append(messages, compareSimpleSchemas(providerParam.In+" parameter "+providerParam.Name,
+			&param.SimpleSchema, &param.CommonValidations, &providerParam.SimpleSchema, &providerParam.CommonValidations)...)
+	}
+	return messages
+}
+
+func compareSimpleSchemas(location string, a *spec.SimpleSchema, av *spec.CommonValidations, b *spec.SimpleSchema, bv *spec.CommonValidations) []string {
+	var messages []string
+	if b.Type != "" && a.Type != b.Type {
+		return []string{fmt.Sprintf("%s must be of type %s, not %s", location, b.Type, a.Type)}
+	}
+	if b.Format != "" && a.Format != b.Format {
+		messages = append(messages, fmt.Sprintf("%s must have format %s", location, b.Format))
+	}
+	messages = append(messages, compareValidations(location, av, bv)...)
+	if a.Items != nil && b.Items != nil {
+		messages = append(messages, compareSimpleSchemas(location+" items",
+			&a.Items.SimpleSchema, &a.Items.CommonValidations, &b.Items.SimpleSchema, &b.Items.CommonValidations)...)
+	}
+	return messages
+}
+
+func compareSchemas(location string, a, b *spec.Schema, depth int) []string {
+	if depth > maxCompatibilityDepth {
+		return nil
+	}
+	if len(b.Type) != 0 {
+		for _, t := range a.Type {
+			if !b.Type.Contains(t) {
+				return []string{fmt.Sprintf("%s must be of type %s, not %s", location, strings.Join(b.Type, ","), t)}
+			}
+		}
+		if len(a.Type) == 0 {
+			return []string{fmt.Sprintf("%s must be of type %s", location, strings.Join(b.Type, ","))}
+		}
+	}
+	var messages []string
+	if b.Format != "" && a.Format != b.Format {
+		messages = append(messages, fmt.Sprintf("%s must have format %s", location, b.Format))
+	}
+	messages = append(messages, compareValidations(location, schemaValidations(a), schemaValidations(b))...)
+
+	aProps, _, _ := declaredProperties(a)
+	bProps, bAdditional, bDescribed := declaredProperties(b)
+	required := make(map[string]bool)
+	for _, name := range requiredProperties(a) {
+		required[name] = true
+	}
+	for _, name := range requiredProperties(b) {
+		if !required[name] {
+			messages = append(messages, location+" property "+name+" is required")
+		}
+	}
+	for _, name := range sortedSchemaNames(aProps) {
+		propLocation := location + "." + name
+		bProp, ok := bProps[name]
+		if !ok {
+			bProp = bAdditional
+		}
+		if bProp == nil {
+			if bDescribed && b.AdditionalProperties != nil && !b.AdditionalProperties.Allows {
+				messages = append(messages, propLocation+" is not allowed")
+			}
+			continue
+		}
+		messages = append(messages, compareSchemas(propLocation, aProps[name], bProp, depth+1)...)
+	}
+	if a.Items != nil && a.Items.Schema != nil && b.Items != nil && b.Items.Schema != nil {
+		messages = append(messages, compareSchemas(location+" items", a.Items.Schema, b.Items.Schema, depth+1)...)
+	}
+	return messages
+}
+
+// compareValidations reports constraints of b which are not implied by constraints of a
+func compareValidations(location string, a, b *spec.CommonValidations) []string {
+	var messages []string
+	if len(b.Enum) != 0 {
+		if len(a.Enum) == 0 {
+			messages = append(messages, location+" is restricted to enum values")
+		}
+		for _, value := range a.Enum {
+			if !containsValue(b.Enum, value) {
+				messages = append(messages, fmt.Sprintf("%s value %v is not allowed", location, value))
+			}
+		}
+	}
+	if b.Maximum != nil && (a.Maximum == nil || *a.Maximum > *b.Maximum || (*a.Maximum == *b.Maximum && b.ExclusiveMaximum && !a.ExclusiveMaximum)) {
+		messages = append(messages, fmt.Sprintf("%s maximum is %v", location, *b.Maximum))
+	}
+	if b.Minimum != nil && (a.Minimum == nil || *a.Minimum < *b.Minimum || (*a.Minimum == *b.Minimum && b.ExclusiveMinimum && !a.ExclusiveMinimum)) {
+		messages = append(messages, fmt.Sprintf("%s minimum is %v", location, *b.Minimum))
+	}
+	if b.MaxLength != nil && (a.MaxLength == nil || *a.MaxLength > *b.MaxLength) {
+		messages = append(messages, fmt.Sprintf("%s maximum length is %d", location, *b.MaxLength))
+	}
+	if b.MinLength != nil && (a.MinLength == nil || *a.MinLength < *b.MinLength) {
+		messages = append(messages, fmt.Sprintf("%s minimum length is %d", location, *b.MinLength))
+	}
+	if b.MaxItems != nil && (a.MaxItems == nil || *a.MaxItems > *b.MaxItems) {
+		messages = append(messages, fmt.Sprintf("%s maximum number of items is %d", location, *b.MaxItems))
+	}
+	if b.MinItems != nil && (a.MinItems == nil || *a.MinItems < *b.MinItems) {
+		messages = append(messages, fmt.Sprintf("%s minimum number of items is %d", location, *b.MinItems))
+	}
+	if b.UniqueItems && !a.UniqueItems {
+		messages = append(messages, location+" items must be unique")
+	}
+	if b.Pattern != "" && a.Pattern != b.Pattern {
+		messages = append(messages, location+" must match pattern "+b.Pattern)
+	}
+	if b.MultipleOf != nil && (a.MultipleOf == nil || *a.MultipleOf != *b.MultipleOf) {
+		messages = append(messages, fmt.Sprintf("%s must be a multiple of %v", location, *b.MultipleOf))
+	}
+	return messages
+}
+
+func schemaValidations(s *spec.Schema) *spec.CommonValidations {
+	return &spec.CommonValidations{
+		Maximum:          s.Maximum,
+		ExclusiveMaximum: s.ExclusiveMaximum,
+		Minimum:          s.Minimum,
+		ExclusiveMinimum: s.ExclusiveMinimum,
+		MaxLength:        s.MaxLength,
+		MinLength:        s.MinLength,
+		Pattern:          s.Pattern,
+		MaxItems:         s.MaxItems,
+		MinItems:         s.MinItems,
+		UniqueItems:      s.UniqueItems,
+		MultipleOf:       s.MultipleOf,
+		Enum:             s.Enum,
+	}
+}
+
+func sortedSchemaNames(schemas map[string]*spec.Schema) []string {
+	names := make(map[string]bool, len(schemas))
+	for name := range schemas {
+		names[name] = true
+	}
+	return sortedKeys(names)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
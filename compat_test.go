@@ -0,0 +1,98 @@
+package revisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const compatProvider = `
+swagger: "2.0"
+info: {title: provider, version: 1.0.0}
+basePath: /api
+consumes: [application/json]
+paths:
+  /pets/{id}:
+    put:
+      parameters:
+        - {name: id, in: path, required: true, type: integer}
+        - {name: dryRun, in: query, required: true, type: boolean}
+        - name: body
+          in: body
+          required: true
+          schema:
+            type: object
+            required: [name]
+            additionalProperties: false
+            properties:
+              name: {type: string, maxLength: 10}
+              kind: {type: string, enum: [cat, dog]}
+      responses:
+        "200": {description: ok}
+`
+
+const compatConsumer = `
+swagger: "2.0"
+info: {title: consumer, version: 1.0.0}
+basePath: /api/
+consumes: [application/json, application/xml]
+paths:
+  /pets/{petId}:
+    put:
+      parameters:
+        - {name: petId, in: path, required: true, type: string}
+        - name: body
+          in: body
+          schema:
+            type: object
+            properties:
+              name: {type: string}
+              kind: {type: string, enum: [cat, bird]}
+              age: {type: integer}
+      responses:
+        "200": {description: ok}
+  /owners:
+    get:
+      responses:
+        "200": {description: ok}
+`
+
+func TestVerifier_CompatibleWith(t *testing.T) {
+	dir, err := ioutil.TempDir("", "revisor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	load := func(name, content string) *Verifier {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		v, err := New(path)
+		require.NoError(t, err)
+		return v
+	}
+	provider := load("provider.yaml", compatProvider)
+	consumer := load("consumer.yaml", compatConsumer)
+
+	var messages []string
+	for _, i := range consumer.CompatibleWith(provider) {
+		messages = append(messages, i.String())
+	}
+	assert.Equal(t, []string{
+		"GET /owners: operation is not defined",
+		"PUT /pets/{petId}: content type application/xml is not accepted",
+		"PUT /pets/{petId}: path parameter id must be of type integer, not string",
+		"PUT /pets/{petId}: query parameter dryRun is required",
+		"PUT /pets/{petId}: body parameter body is required",
+		"PUT /pets/{petId}: body property name is required",
+		"PUT /pets/{petId}: body.age is not allowed",
+		"PUT /pets/{petId}: body.kind value bird is not allowed",
+		"PUT /pets/{petId}: body.name maximum length is 10",
+	}, messages)
+
+	sample, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	assert.Empty(t, sample.CompatibleWith(sample))
+}
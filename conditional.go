@@ -0,0 +1,122 @@
+package revisor
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// supportsETagExt is an operation extension which declares that the operation
+// supports conditional requests with entity tags, e.g.
+//
+//	x-supports-etag: true
+const supportsETagExt = "x-supports-etag"
+
+// CheckConditionalRequests enables checks of conditional request semantics
+// of operations declared with x-supports-etag extension: If-None-Match header
+// of requests must be a list of entity tags, 200 responses to GET and HEAD
+// requests must have ETag header, which doesn't match If-None-Match of the
+// request, and 304 responses
+// are accepted without body even if they are not documented, but only for
+// conditional requests. Broken semantics is reported as Violation with
+// CodeConditionalRequest code.
+func CheckConditionalRequests(a *apiVerifier) {
+	a.opts.checkConditional = true
+}
+
+// supportsETag checks if the operation is declared with x-supports-etag extension
+func supportsETag(operation *spec.Operation) bool {
+	supported, _ := operation.Extensions[supportsETagExt].(bool)
+	return supported
+}
+
+// verifyConditionalRequest checks If-None-Match header of the request
+func (a *apiVerifier) verifyConditionalRequest(req *http.Request) error {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !supportsETag(operation) {
+		return err
+	}
+	ifNoneMatch := req.Header.Get("If-None-Match")
+	if ifNoneMatch == "" || ifNoneMatch == "*" {
+		return nil
+	}
+	if _, ok := parseETags(ifNoneMatch); !ok {
+		return newViolation(CodeConditionalRequest, "If-None-Match is not a list of entity tags: "+ifNoneMatch)
+	}
+	return nil
+}
+
+// verifyConditionalResponse checks ETag header of the response to the request,
+// handled return parameter reports if response is 304 Not Modified, which body
+// is not validated against schema
+func (a *apiVerifier) verifyConditionalResponse(req *http.Request, res *http.Response) (handled bool, err error) {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !supportsETag(operation) {
+		return false, nil
+	}
+	ifNoneMatch := req.Header.Get("If-None-Match")
+	etag := res.Header.Get("ETag")
+	switch res.StatusCode {
+	case http.StatusNotModified:
+		if ifNoneMatch == "" && req.Header.Get("If-Modified-Since") == "" {
+			return true, newViolation(CodeConditionalRequest, "304 Not Modified is sent for unconditional request")
+		}
+		body, err := readResponseBody(res)
+		if err != nil {
+			return true, err
+		}
+		if len(body) != 0 {
+			return true, newViolation(CodeConditionalRequest, "304 Not Modified must not have body")
+		}
+		return true, nil
+	case http.StatusOK:
+		// If-None-Match of other methods is a precondition of the change,
+		// e.g. creating the resource only if it doesn't exist
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			return false, nil
+		}
+		if etag == "" {
+			return false, newViolation(CodeConditionalRequest, "ETag header is missing")
+		}
+		tags, ok := parseETags(etag)
+		if !ok || len(tags) != 1 {
+			return false, newViolation(CodeConditionalRequest, "ETag is not an entity tag: "+etag)
+		}
+		if ifNoneMatch == "*" || etagMatches(ifNoneMatch, tags[0]) {
+			return false, newViolation(CodeConditionalRequest, "ETag matches If-None-Match, but 304 Not Modified is not sent")
+		}
+	}
+	return false, nil
+}
+
+// parseETags parses comma separated list of entity tags,
+// e.g. "xyzzy", W/"r2d2xxxx"
+func parseETags(header string) ([]string, bool) {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		opaque := strings.TrimPrefix(tag, "W/")
+		if len(opaque) < 2 || opaque[0] != '"' || opaque[len(opaque)-1] != '"' ||
+			strings.Contains(opaque[1:len(opaque)-1], `"`) {
+			return nil, false
+		}
+		tags = append(tags, tag)
+	}
+	return tags, true
+}
+
+// etagMatches checks if any of entity tags of If-None-Match header matches
+// the entity tag using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	tags, ok := parseETags(ifNoneMatch)
+	if !ok {
+		return false
+	}
+	for _, tag := range tags {
+		if strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConditionalRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		reqBody     string
+		ifNoneMatch string
+		status      int
+		etag        string
+		body        string
+		wantReqErr  bool
+		wantRespErr bool
+	}{
+		{
+			name:   "etag",
+			status: 200, etag: `"v1"`, body: `{"total": 30}`,
+		},
+		{
+			name:   "weak etag",
+			status: 200, etag: `W/"v1"`, body: `{"total": 30}`,
+		},
+		{
+			name:   "missing etag",
+			status: 200, body: `{"total": 30}`,
+			wantRespErr: true,
+		},
+		{
+			name:   "invalid etag",
+			status: 200, etag: `v1`, body: `{"total": 30}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "modified",
+			ifNoneMatch: `"v1", "v2"`,
+			status:      200, etag: `"v3"`, body: `{"total": 30}`,
+		},
+		{
+			name:        "not modified",
+			ifNoneMatch: `"v1", "v2"`,
+			status:      304,
+		},
+		{
+			name:        "not modified with body",
+			ifNoneMatch: `"v1"`,
+			status:      304, body: `{"total": 30}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "matching etag",
+			ifNoneMatch: `"v1", "v2"`,
+			status:      200, etag: `W/"v2"`, body: `{"total": 30}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "any etag",
+			ifNoneMatch: `*`,
+			status:      200, etag: `"v1"`, body: `{"total": 30}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "unconditional not modified",
+			status:      304,
+			wantRespErr: true,
+		},
+		{
+			name:        "invalid If-None-Match",
+			ifNoneMatch: `v1`,
+			status:      200, etag: `"v2"`, body: `{"total": 30}`,
+			wantReqErr: true,
+		},
+		{
+			name:        "put precondition",
+			method:      "PUT",
+			reqBody:     `{"total": 30}`,
+			ifNoneMatch: `*`,
+			status:      200, body: `{"total": 30}`,
+		},
+		{
+			name:   "not supported",
+			path:   "/v1/orders",
+			status: 200, body: `[{"total": 30}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"etag.yaml", CheckConditionalRequests)
+			require.NoError(t, err)
+			method, path := tt.method, tt.path
+			if method == "" {
+				method = "GET"
+			}
+			if path == "" {
+				path = "/v1/orders/1"
+			}
+
+			req := httptest.NewRequest(method, path, bytes.NewBufferString(tt.reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			assertConditionalErr(t, v.VerifyRequest(req), tt.wantReqErr)
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			if tt.etag != "" {
+				rec.Header().Set("ETag", tt.etag)
+			}
+			rec.WriteHeader(tt.status)
+			rec.Body = bytes.NewBufferString(tt.body)
+			assertConditionalErr(t, v.VerifyResponse(rec.Result(), req), tt.wantRespErr)
+		})
+	}
+}
+
+func assertConditionalErr(t *testing.T, err error, wantErr bool) {
+	if wantErr {
+		assertVerifyErr(t, err, "", CodeConditionalRequest)
+	} else {
+		assert.NoError(t, err)
+	}
+}
+
+func TestCheckConditionalRequests_Warning(t *testing.T) {
+	var warnings []string
+	_, err := New(testdata+"etag.yaml", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "GET /orders/{id}: x-supports-etag is not enforced")
+}
@@ -0,0 +1,242 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// echoesExt is a response extension which maps JSON pointers of response
+// body fields to request values the fields must be equal to, e.g.
+//
+//	'201':
+//	  x-echoes:
+//	    /name: /name
+//	    /owner: path.username
+//
+// Request values are JSON pointers of request body fields or parameters
+// named as path.<name>, query.<name> or header.<name>.
+const echoesExt = "x-echoes"
+
+// fieldsParamExt is an operation extension which names the query parameter
+// listing comma-separated fields the response objects are filtered to, e.g.
+//
+//	x-fields-param: fields
+const fieldsParamExt = "x-fields-param"
+
+// DecodedExchange holds a request and the response made to it
+// along with their decoded bodies
+type DecodedExchange struct {
+	Request  *http.Request
+	Response *http.Response
+	// RequestBody is nil if the request has no body
+	// or it can't be decoded
+	RequestBody  interface{}
+	ResponseBody interface{}
+	Params       Params
+}
+
+// CheckConsistency enables checks of responses against requests they are
+// made to, which are declared with extensions: response fields listed in
+// x-echoes must be equal to request values, and response objects of
+// operations declared with x-fields-param must have only fields requested
+// with the parameter. Inconsistent responses are reported as Violation with
+// CodeInconsistentResponse code.
+func CheckConsistency(a *apiVerifier) {
+	a.opts.checkConsistency = true
+}
+
+// WithConsistencyCheck attaches check of responses of the operation
+// identified by operationID against requests they are made to, e.g. that
+// created resource is the requested one. The check runs after the response
+// is verified against the definition. Several checks of the operation run
+// in the order they are added.
+func WithConsistencyCheck(operationID string, check func(e DecodedExchange) error) option {
+	return func(a *apiVerifier) {
+		if a.opts.consistencyChecks == nil {
+			a.opts.consistencyChecks = make(map[string][]func(DecodedExchange) error)
+		}
+		a.opts.consistencyChecks[operationID] = append(a.opts.consistencyChecks[operationID], check)
+	}
+}
+
+// verifyConsistency checks decoded body of the response
+// against the request it is made to
+func (a *apiVerifier) verifyConsistency(req *http.Request, res *http.Response, response *spec.Response, decoded interface{}) error {
+	if !a.opts.checkConsistency && len(a.opts.consistencyChecks) == 0 {
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	checks := a.opts.consistencyChecks[operation.ID]
+	if !a.opts.checkConsistency && len(checks) == 0 {
+		return nil
+	}
+	e := DecodedExchange{
+		Request:      req,
+		Response:     res,
+		RequestBody:  a.decodeRequestBody(req),
+		ResponseBody: decoded,
+		Params:       a.requestParams(req),
+	}
+	if a.opts.checkConsistency {
+		if err := verifyEchoes(response, e); err != nil {
+			return err
+		}
+		if err := verifyFieldsFilter(operation, e); err != nil {
+			return err
+		}
+	}
+	for _, check := range checks {
+		if err := check(e); err != nil {
+			return errors.Wrap(err, "consistency check of "+operation.ID+" failed")
+		}
+	}
+	return nil
+}
+
+// decodeRequestBody returns decoded body of the request,
+// which is nil if the body is empty or can't be decoded
+func (a *apiVerifier) decodeRequestBody(req *http.Request) interface{} {
+	body, err := readRequestBody(req)
+	if err != nil || len(body) == 0 {
+		return nil
+	}
+	decoded, err := decodeBody(req.Header.Get("Content-Type"), body)
+	if err != nil {
+		return nil
+	}
+	decoded, err = a.unwrapEnvelope(req, decoded)
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
+
+// verifyEchoes checks response fields declared with x-echoes extension
+func verifyEchoes(response *spec.Response, e DecodedExchange) error {
+	ext, ok := response.Extensions[echoesExt]
+	if !ok {
+		return nil
+	}
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		return errors.Wrap(err, "failed to read "+echoesExt)
+	}
+	var echoes map[string]string
+	err = json.Unmarshal(raw, &echoes)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse "+echoesExt)
+	}
+	for _, field := range sortedKeys(echoes) {
+		source := echoes[field]
+		want, ok, err := echoedValue(source, e)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		tokens, ok := pointerTokens(field)
+		if !ok {
+			return errors.New("failed to parse " + echoesExt + ": invalid pointer " + field)
+		}
+		got, ok := valueAt(e.ResponseBody, tokens)
+		if !ok {
+			return newViolation(CodeInconsistentResponse, "response field "+field+" is missing, which echoes "+source)
+		}
+		if _, param := want.(string); param && !strings.HasPrefix(source, "/") {
+			got = paramString(got)
+		}
+		if !reflect.DeepEqual(got, want) {
+			return newViolation(CodeInconsistentResponse, "response field "+field+" doesn't match "+source)
+		}
+	}
+	return nil
+}
+
+// echoedValue returns the request value named by x-echoes source, second
+// return value is false if the request doesn't have the value
+func echoedValue(source string, e DecodedExchange) (interface{}, bool, error) {
+	if strings.HasPrefix(source, "/") {
+		tokens, ok := pointerTokens(source)
+		if !ok {
+			return nil, false, errors.New("failed to parse " + echoesExt + ": invalid pointer " + source)
+		}
+		value, ok := valueAt(e.RequestBody, tokens)
+		return value, ok, nil
+	}
+	parts := strings.SplitN(source, ".", 2)
+	if len(parts) != 2 {
+		return nil, false, errors.New("failed to parse " + echoesExt + ": invalid source " + source)
+	}
+	switch parts[0] {
+	case "path":
+		value, ok := e.Params.Path[parts[1]]
+		return value, ok, nil
+	case "query":
+		values, ok := e.Params.Query[parts[1]]
+		if !ok || len(values) == 0 {
+			return nil, false, nil
+		}
+		return values[0], true, nil
+	case "header":
+		values, ok := e.Request.Header[http.CanonicalHeaderKey(parts[1])]
+		if !ok || len(values) == 0 {
+			return nil, false, nil
+		}
+		return values[0], true, nil
+	}
+	return nil, false, errors.New("failed to parse " + echoesExt + ": invalid source " + source)
+}
+
+// paramString formats decoded JSON scalar as a parameter value
+func paramString(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return value
+}
+
+// verifyFieldsFilter checks that response objects of operation declared with
+// x-fields-param extension have only requested fields
+func verifyFieldsFilter(operation *spec.Operation, e DecodedExchange) error {
+	param, ok := operation.Extensions[fieldsParamExt].(string)
+	if !ok {
+		return nil
+	}
+	values, ok := e.Params.Query[param]
+	if !ok || len(values) == 0 || values[0] == "" {
+		return nil
+	}
+	requested := make(map[string]bool)
+	for _, field := range strings.Split(values[0], ",") {
+		requested[strings.TrimSpace(field)] = true
+	}
+	objects := []interface{}{e.ResponseBody}
+	if items, ok := e.ResponseBody.([]interface{}); ok {
+		objects = items
+	}
+	for _, object := range objects {
+		object, ok := object.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, field := range sortedKeys(object) {
+			if !requested[field] {
+				return newViolation(CodeInconsistentResponse, "response field "+field+" is not requested with "+param)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		size     string
+		reqBody  string
+		status   int
+		resBody  string
+		wantErr  string
+		wantCode string
+	}{
+		{
+			name:   "echoed",
+			method: "POST", url: "/v1/users/alice/projects", size: "10",
+			reqBody: `{"name": "revisor"}`,
+			status:  201, resBody: `{"id": 1, "name": "revisor", "owner": "alice", "size": 10}`,
+		},
+		{
+			name:   "missing request values",
+			method: "POST", url: "/v1/users/alice/projects",
+			reqBody: `{}`,
+			status:  201, resBody: `{"id": 1, "owner": "alice"}`,
+		},
+		{
+			name:   "body field mismatch",
+			method: "POST", url: "/v1/users/alice/projects",
+			reqBody: `{"name": "revisor"}`,
+			status:  201, resBody: `{"id": 1, "name": "other", "owner": "alice"}`,
+			wantErr:  "response field /name doesn't match /name",
+			wantCode: CodeInconsistentResponse,
+		},
+		{
+			name:   "path parameter mismatch",
+			method: "POST", url: "/v1/users/alice/projects",
+			reqBody: `{"name": "revisor"}`,
+			status:  201, resBody: `{"id": 1, "name": "revisor", "owner": "bob"}`,
+			wantErr:  "response field /owner doesn't match path.username",
+			wantCode: CodeInconsistentResponse,
+		},
+		{
+			name:   "header mismatch",
+			method: "POST", url: "/v1/users/alice/projects", size: "10",
+			reqBody: `{"name": "revisor"}`,
+			status:  201, resBody: `{"id": 1, "name": "revisor", "owner": "alice", "size": 11}`,
+			wantErr:  "response field /size doesn't match header.X-Size",
+			wantCode: CodeInconsistentResponse,
+		},
+		{
+			name:   "missing echo",
+			method: "POST", url: "/v1/users/alice/projects",
+			reqBody: `{"name": "revisor"}`,
+			status:  201, resBody: `{"id": 1, "owner": "alice"}`,
+			wantErr:  "response field /name is missing, which echoes /name",
+			wantCode: CodeInconsistentResponse,
+		},
+		{
+			name:   "filtered fields",
+			method: "GET", url: "/v1/users/alice/projects?fields=id,name",
+			status: 200, resBody: `[{"id": 1, "name": "revisor"}, {"id": 2}]`,
+		},
+		{
+			name:   "not filtered",
+			method: "GET", url: "/v1/users/alice/projects",
+			status: 200, resBody: `[{"id": 1, "name": "revisor", "owner": "alice"}]`,
+		},
+		{
+			name:   "unrequested field",
+			method: "GET", url: "/v1/users/alice/projects?fields=id,name",
+			status: 200, resBody: `[{"id": 1, "name": "revisor", "owner": "alice"}]`,
+			wantErr:  "response field owner is not requested with fields",
+			wantCode: CodeInconsistentResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"consistency.yaml", CheckConsistency)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.size != "" {
+				req.Header.Set("X-Size", tt.size)
+			}
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			rec.WriteHeader(tt.status)
+			rec.Body = bytes.NewBufferString(tt.resBody)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), tt.wantErr, tt.wantCode)
+		})
+	}
+}
+
+func TestWithConsistencyCheck(t *testing.T) {
+	var exchanges []DecodedExchange
+	v, err := New(testdata+"consistency.yaml", WithConsistencyCheck("createProject", func(e DecodedExchange) error {
+		exchanges = append(exchanges, e)
+		if e.ResponseBody.(map[string]interface{})["id"] == float64(0) {
+			return errors.New("id is not assigned")
+		}
+		return nil
+	}))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/v1/users/alice/projects", strings.NewReader(`{"name": "revisor"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(201)
+	rec.Body = bytes.NewBufferString(`{"id": 0, "name": "other"}`)
+	err = v.Verify(rec.Result(), req)
+	assert.EqualError(t, err, "response validation failed: consistency check of createProject failed: id is not assigned",
+		"x-echoes is not checked without CheckConsistency")
+
+	require.Len(t, exchanges, 1)
+	assert.Equal(t, map[string]interface{}{"name": "revisor"}, exchanges[0].RequestBody)
+	assert.Equal(t, map[string]string{"username": "alice"}, exchanges[0].Params.Path)
+	assert.Equal(t, 201, exchanges[0].Response.StatusCode)
+}
+
+func TestCheckConsistency_Warning(t *testing.T) {
+	var warnings []string
+	_, err := New(testdata+"consistency.yaml", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "GET /users/{username}/projects: x-fields-param is not enforced")
+	assert.Contains(t, warnings, "POST /users/{username}/projects: x-echoes is not enforced")
+}
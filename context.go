@@ -0,0 +1,76 @@
+package revisor
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+type contextKey string
+
+// Context keys the verified request values are stored under
+// by the function returned from NewContextVerifier
+const (
+	// BodyContextKey is a key of decoded request body
+	BodyContextKey = contextKey("revisor.body")
+	// ParamsContextKey is a key of request Params
+	ParamsContextKey = contextKey("revisor.params")
+)
+
+// Params holds request parameters parsed while the request is verified
+type Params struct {
+	// Path holds values of path template variables
+	Path map[string]string
+	// Query holds query parameters
+	Query url.Values
+}
+
+// NewContextVerifier returns a function that verifies the request same way as
+// the function returned from NewRequestVerifier does, reporting metrics and
+// applying validation budget and stages the same way too. If the request is valid,
+// a shallow copy of it is returned with decoded body and parsed parameters
+// stored in its context, so that handlers can avoid decoding the body again.
+// Use DecodedBody and RequestParams to access stored values.
+func NewContextVerifier(definitionPath string, options ...option) (func(*http.Request) (*http.Request, error), error) {
+	a, err := newInitializedVerifier(definitionPath, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create verifier function")
+	}
+	return a.verifyRequestWithContext, nil
+}
+
+func (a *apiVerifier) verifyRequestWithContext(req *http.Request) (*http.Request, error) {
+	if req == nil {
+		return nil, ErrNilInput
+	}
+	req = a.pinSatisfiedTemplate(req)
+	decoded, err := a.verifyMeasuredRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.WithValue(req.Context(), BodyContextKey, decoded)
+	ctx = context.WithValue(ctx, ParamsContextKey, a.requestParams(req))
+	return req.WithContext(ctx), nil
+}
+
+// requestParams parses parameters of the request
+func (a *apiVerifier) requestParams(req *http.Request) Params {
+	_, vars, _ := a.mapper.mapRequest(req)
+	return Params{Path: vars, Query: req.URL.Query()}
+}
+
+// DecodedBody returns request body decoded by the verifier, second return
+// parameter reports if the body was stored in the context
+func DecodedBody(ctx context.Context) (interface{}, bool) {
+	body := ctx.Value(BodyContextKey)
+	return body, body != nil
+}
+
+// RequestParams returns request parameters parsed by the verifier, second return
+// parameter reports if parameters were stored in the context
+func RequestParams(ctx context.Context) (Params, bool) {
+	params, ok := ctx.Value(ParamsContextKey).(Params)
+	return params, ok
+}
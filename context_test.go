@@ -0,0 +1,64 @@
+package revisor
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextVerifier(t *testing.T) {
+	verifier, err := NewContextVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	t.Run("valid request", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/v2/user/testuser?trace=true", bytes.NewReader([]byte(`{"id":1}`)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		req, err = verifier(req)
+		require.NoError(t, err)
+
+		body, ok := DecodedBody(req.Context())
+		assert.True(t, ok)
+		assert.Equal(t, map[string]interface{}{"id": float64(1)}, body)
+
+		params, ok := RequestParams(req.Context())
+		assert.True(t, ok)
+		assert.Equal(t, map[string]string{"username": "testuser"}, params.Path)
+		assert.Equal(t, "true", params.Query.Get("trace"))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", nil)
+		require.NoError(t, err)
+
+		req, err = verifier(req)
+		assert.Regexp(t, "body is empty", err)
+		assert.Nil(t, req)
+	})
+
+	t.Run("verification is measured", func(t *testing.T) {
+		sink := &recordingSink{}
+		verifier, err := NewContextVerifier(testdata+sampleV2YAML, WithMetrics(sink))
+		require.NoError(t, err)
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", nil)
+		require.NoError(t, err)
+		_, err = verifier(req)
+		assert.Error(t, err)
+		assert.Equal(t, []string{
+			"verifications kind=request operation=updateUser",
+			"violations code=invalid kind=request operation=updateUser",
+		}, sink.counters)
+	})
+
+	t.Run("values are not stored", func(t *testing.T) {
+		_, ok := DecodedBody(context.Background())
+		assert.False(t, ok)
+		_, ok = RequestParams(context.Background())
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,1135 @@
+package revisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+)
+
+// refPrefixes maps Swagger 2.0 reference prefixes to OpenAPI 3.0 ones
+var refPrefixes = [][2]string{
+	{"#/definitions/", "#/components/schemas/"},
+	{"#/parameters/", "#/components/parameters/"},
+	{"#/responses/", "#/components/responses/"},
+}
+
+// simpleSchemaKeys are keys shared by Swagger 2.0 non-body parameters,
+// items and headers with OpenAPI 3.0 schemas
+var simpleSchemaKeys = []string{
+	"type", "format", "items", "enum", "default", "maximum", "exclusiveMaximum",
+	"minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern",
+	"maxItems", "minItems", "uniqueItems", "multipleOf",
+}
+
+var formMediaTypes = []string{"application/x-www-form-urlencoded", "multipart/form-data"}
+
+// ConvertToOpenAPI3 converts Swagger 2.0 definition loaded from definitionPath
+// to an equivalent OpenAPI 3.0 document and writes it to w as JSON.
+// References are kept and rewritten to point to components.
+func ConvertToOpenAPI3(definitionPath string, w io.Writer) error {
+	doc, err := loadJSONObject(definitionPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert definition")
+	}
+	if doc["swagger"] != "2.0" {
+		return errors.New("failed to convert definition: not a Swagger 2.0 document")
+	}
+	converted, err := swagger2ToOpenAPI3(doc)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert definition")
+	}
+	return errors.Wrap(writeJSON(w, converted), "failed to write definition")
+}
+
+// ConvertToSwagger2 converts OpenAPI 3.0 definition loaded from definitionPath
+// to an equivalent Swagger 2.0 document and writes it to w as JSON.
+// Documentation only parts without Swagger 2.0 counterpart, like callbacks and
+// links, are omitted, constraints which can't be expressed in Swagger 2.0,
+// like oneOf schemas or cookie parameters, are reported as errors.
+func ConvertToSwagger2(definitionPath string, w io.Writer) error {
+	doc, err := loadJSONObject(definitionPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert definition")
+	}
+	converted, err := openAPI3ToSwagger2(doc, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert definition")
+	}
+	return errors.Wrap(writeJSON(w, converted), "failed to write definition")
+}
+
+// loadJSONObject loads definition without interpreting it
+func loadJSONObject(definitionPath string) (map[string]interface{}, error) {
+	b, err := swag.LoadFromFileOrHTTP(definitionPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load definition")
+	}
+	raw, err := definitionJSON(definitionPath, b)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]interface{})
+	err = json.Unmarshal(raw, &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode definition")
+	}
+	return doc, nil
+}
+
+// swagger2JSON returns Swagger 2.0 definition as it is and converts
+// OpenAPI 3.0 definition to Swagger 2.0, so that verifiers accept both.
+// Constructs which can't be expressed in Swagger 2.0 are dropped from
+// the converted definition and listed in the second return parameter.
+func swagger2JSON(raw json.RawMessage) (json.RawMessage, []string, error) {
+	if openAPIVersion(raw) == "" {
+		return raw, nil, nil
+	}
+	doc := make(map[string]interface{})
+	err := json.Unmarshal(raw, &doc)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode definition")
+	}
+	var dropped []string
+	converted, err := openAPI3ToSwagger2(doc, &dropped)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to convert OpenAPI 3 definition")
+	}
+	b, err := json.Marshal(converted)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to convert OpenAPI 3 definition")
+	}
+	return b, dropped, nil
+}
+
+// openAPIVersion returns OpenAPI version of the definition, which is empty
+// for Swagger 2.0 ones
+func openAPIVersion(raw json.RawMessage) string {
+	var version struct {
+		OpenAPI string `json:"openapi"`
+	}
+	_ = json.Unmarshal(raw, &version)
+	return version.OpenAPI
+}
+
+// warnUnconverted reports constructs of OpenAPI 3.0 definition which were
+// dropped during conversion to Swagger 2.0 and aren't validated, they are
+// only logged if the logger is set with WithLogger
+func (a *apiVerifier) warnUnconverted() {
+	if !a.opts.loggerSet {
+		return
+	}
+	for _, construct := range a.unconverted {
+		a.opts.logf("revisor: warning: %s is not supported by Swagger 2.0 and is not validated", construct)
+	}
+}
+
+// swagger2ToOpenAPI3 converts decoded Swagger 2.0 document
+func swagger2ToOpenAPI3(doc map[string]interface{}) (map[string]interface{}, error) {
+	out := map[string]interface{}{"openapi": "3.0.0"}
+	copyKeys(out, doc, "info", "tags", "externalDocs", "security")
+	copyExtensions(out, doc)
+	out["servers"] = swagger2Servers(doc)
+
+	consumes := stringList(doc["consumes"])
+	produces := stringList(doc["produces"])
+	parameters := objectValue(doc["parameters"])
+
+	components := make(map[string]interface{})
+	if definitions := objectValue(doc["definitions"]); len(definitions) != 0 {
+		schemas := make(map[string]interface{})
+		for name, schema := range definitions {
+			schemas[name] = swagger2Schema(schema)
+		}
+		components["schemas"] = schemas
+	}
+	if len(parameters) != 0 {
+		params := make(map[string]interface{})
+		bodies := make(map[string]interface{})
+		for name, p := range parameters {
+			param := objectValue(p)
+			switch param["in"] {
+			case "body":
+				bodies[name] = swagger2RequestBody([]map[string]interface{}{param}, consumes)
+			case "formData":
+				// form parameters are inlined into request bodies of operations
+			default:
+				params[name] = swagger2Parameter(param)
+			}
+		}
+		setNonEmpty(components, "parameters", params)
+		setNonEmpty(components, "requestBodies", bodies)
+	}
+	if responses := objectValue(doc["responses"]); len(responses) != 0 {
+		converted := make(map[string]interface{})
+		for name, r := range responses {
+			converted[name] = swagger2Response(objectValue(r), produces)
+		}
+		components["responses"] = converted
+	}
+	if definitions := objectValue(doc["securityDefinitions"]); len(definitions) != 0 {
+		schemes := make(map[string]interface{})
+		for name, d := range definitions {
+			schemes[name] = swagger2SecurityScheme(objectValue(d))
+		}
+		components["securitySchemes"] = schemes
+	}
+	setNonEmpty(out, "components", components)
+
+	paths := make(map[string]interface{})
+	for path, item := range objectValue(doc["paths"]) {
+		pathItem := objectValue(item)
+		convertedItem := make(map[string]interface{})
+		copyExtensions(convertedItem, pathItem)
+		var pathBodyParams []map[string]interface{}
+		var pathParams []interface{}
+		for _, p := range listValue(pathItem["parameters"]) {
+			param, body := resolveSwagger2Parameter(objectValue(p), parameters)
+			if body {
+				pathBodyParams = append(pathBodyParams, param)
+				continue
+			}
+			pathParams = append(pathParams, swagger2Parameter(objectValue(p)))
+		}
+		if len(pathParams) != 0 {
+			convertedItem["parameters"] = pathParams
+		}
+		for _, method := range httpMethods {
+			op := objectValue(pathItem[strings.ToLower(method)])
+			if op == nil {
+				continue
+			}
+			convertedItem[strings.ToLower(method)] = swagger2Operation(op, pathBodyParams, parameters, consumes, produces)
+		}
+		paths[path] = convertedItem
+	}
+	out["paths"] = paths
+	return out, nil
+}
+
+func swagger2Servers(doc map[string]interface{}) []interface{} {
+	host, _ := doc["host"].(string)
+	basePath, _ := doc["basePath"].(string)
+	if host == "" {
+		if basePath == "" {
+			basePath = "/"
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	schemes := stringList(doc["schemes"])
+	if len(schemes) == 0 {
+		return []interface{}{map[string]interface{}{"url": "//" + host + basePath}}
+	}
+	var servers []interface{}
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+func swagger2Operation(op map[string]interface{}, pathBodyParams []map[string]interface{}, parameters map[string]interface{}, consumes, produces []string) map[string]interface{} {
+	out := make(map[string]interface{})
+	copyKeys(out, op, "tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security")
+	copyExtensions(out, op)
+	if opConsumes := stringList(op["consumes"]); len(opConsumes) != 0 {
+		consumes = opConsumes
+	}
+	if opProduces := stringList(op["produces"]); len(opProduces) != 0 {
+		produces = opProduces
+	}
+
+	bodyParams := append([]map[string]interface{}{}, pathBodyParams...)
+	var params []interface{}
+	var bodyRef string
+	for _, p := range listValue(op["parameters"]) {
+		param, body := resolveSwagger2Parameter(objectValue(p), parameters)
+		if !body {
+			params = append(params, swagger2Parameter(objectValue(p)))
+			continue
+		}
+		bodyParams = append(bodyParams, param)
+		if ref, ok := objectValue(p)["$ref"].(string); ok && param["in"] == "body" {
+			bodyRef = strings.TrimPrefix(ref, "#/parameters/")
+		}
+	}
+	if len(params) != 0 {
+		out["parameters"] = params
+	}
+	if bodyRef != "" && len(bodyParams) == 1 {
+		out["requestBody"] = map[string]interface{}{"$ref": "#/components/requestBodies/" + bodyRef}
+	} else if len(bodyParams) != 0 {
+		out["requestBody"] = swagger2RequestBody(bodyParams, consumes)
+	}
+
+	responses := make(map[string]interface{})
+	for code, r := range objectValue(op["responses"]) {
+		if strings.HasPrefix(code, "x-") {
+			responses[code] = r
+			continue
+		}
+		responses[code] = swagger2Response(objectValue(r), produces)
+	}
+	out["responses"] = responses
+	return out
+}
+
+// resolveSwagger2Parameter returns referenced parameter definition if param is
+// a reference, second return parameter reports if it is body or formData parameter
+func resolveSwagger2Parameter(param, parameters map[string]interface{}) (map[string]interface{}, bool) {
+	if ref, ok := param["$ref"].(string); ok && strings.HasPrefix(ref, "#/parameters/") {
+		if resolved := objectValue(parameters[strings.TrimPrefix(ref, "#/parameters/")]); resolved != nil {
+			param = resolved
+		}
+	}
+	return param, param["in"] == "body" || param["in"] == "formData"
+}
+
+func swagger2Parameter(param map[string]interface{}) map[string]interface{} {
+	if ref, ok := param["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": rewriteRef(ref, false)}
+	}
+	out := make(map[string]interface{})
+	copyKeys(out, param, "name", "in", "description", "required", "allowEmptyValue")
+	copyExtensions(out, param)
+	out["schema"] = simpleToSchema(param)
+	if param["type"] != "array" {
+		return out
+	}
+	switch param["collectionFormat"] {
+	case "multi":
+		out["style"], out["explode"] = "form", true
+	case "ssv":
+		out["style"] = "spaceDelimited"
+	case "pipes":
+		out["style"] = "pipeDelimited"
+	default:
+		if param["in"] == "query" {
+			out["style"], out["explode"] = "form", false
+		} else {
+			out["style"] = "simple"
+		}
+	}
+	return out
+}
+
+// simpleToSchema converts non-body parameter, items or header to schema
+func simpleToSchema(simple map[string]interface{}) map[string]interface{} {
+	schema := make(map[string]interface{})
+	copyKeys(schema, simple, simpleSchemaKeys...)
+	if items := objectValue(simple["items"]); items != nil {
+		schema["items"] = simpleToSchema(items)
+	}
+	if schema["type"] == "file" {
+		schema["type"], schema["format"] = "string", "binary"
+	}
+	return schema
+}
+
+func swagger2RequestBody(params []map[string]interface{}, consumes []string) map[string]interface{} {
+	out := make(map[string]interface{})
+	if params[0]["in"] == "body" {
+		copyKeys(out, params[0], "description", "required")
+		copyExtensions(out, params[0])
+		if len(consumes) == 0 {
+			consumes = []string{"application/json"}
+		}
+		content := make(map[string]interface{})
+		for _, mediaType := range consumes {
+			content[mediaType] = map[string]interface{}{"schema": swagger2Schema(params[0]["schema"])}
+		}
+		out["content"] = content
+		return out
+	}
+
+	properties := make(map[string]interface{})
+	var required []interface{}
+	mediaType := "application/x-www-form-urlencoded"
+	for _, param := range params {
+		if param["in"] != "formData" {
+			continue
+		}
+		name, _ := param["name"].(string)
+		property := simpleToSchema(param)
+		copyKeys(property, param, "description")
+		properties[name] = property
+		if param["required"] == true {
+			required = append(required, name)
+		}
+		if param["type"] == "file" {
+			mediaType = "multipart/form-data"
+		}
+	}
+	for _, c := range consumes {
+		if c == "multipart/form-data" {
+			mediaType = c
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) != 0 {
+		schema["required"] = required
+		out["required"] = true
+	}
+	out["content"] = map[string]interface{}{mediaType: map[string]interface{}{"schema": schema}}
+	return out
+}
+
+func swagger2Response(r map[string]interface{}, produces []string) map[string]interface{} {
+	if ref, ok := r["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": rewriteRef(ref, false)}
+	}
+	out := make(map[string]interface{})
+	copyKeys(out, r, "description")
+	copyExtensions(out, r)
+	if headers := objectValue(r["headers"]); len(headers) != 0 {
+		converted := make(map[string]interface{})
+		for name, h := range headers {
+			header := objectValue(h)
+			convertedHeader := map[string]interface{}{"schema": simpleToSchema(header)}
+			copyKeys(convertedHeader, header, "description")
+			converted[name] = convertedHeader
+		}
+		out["headers"] = converted
+	}
+	examples := objectValue(r["examples"])
+	schema, hasSchema := r["schema"]
+	if !hasSchema && len(examples) == 0 {
+		return out
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+	content := make(map[string]interface{})
+	for _, mediaType := range produces {
+		media := make(map[string]interface{})
+		if hasSchema {
+			media["schema"] = swagger2Schema(schema)
+		}
+		if example, ok := examples[mediaType]; ok {
+			media["example"] = example
+		}
+		content[mediaType] = media
+	}
+	out["content"] = content
+	return out
+}
+
+func swagger2SecurityScheme(d map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	copyKeys(out, d, "description")
+	copyExtensions(out, d)
+	switch d["type"] {
+	case "basic":
+		out["type"], out["scheme"] = "http", "basic"
+	case "apiKey":
+		copyKeys(out, d, "type", "name", "in")
+	case "oauth2":
+		out["type"] = "oauth2"
+		flow := make(map[string]interface{})
+		copyKeys(flow, d, "authorizationUrl", "tokenUrl")
+		flow["scopes"] = objectValue(d["scopes"])
+		if flow["scopes"] == nil {
+			flow["scopes"] = map[string]interface{}{}
+		}
+		name := map[interface{}]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}[d["flow"]]
+		out["flows"] = map[string]interface{}{name: flow}
+	}
+	return out
+}
+
+// swagger2Schema converts Swagger 2.0 schema to OpenAPI 3.0 one
+func swagger2Schema(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for key, nested := range value {
+			switch key {
+			case "$ref":
+				if ref, ok := nested.(string); ok {
+					out[key] = rewriteRef(ref, false)
+					continue
+				}
+			case "x-nullable":
+				out["nullable"] = nested
+				continue
+			case "discriminator":
+				if name, ok := nested.(string); ok {
+					out[key] = map[string]interface{}{"propertyName": name}
+					continue
+				}
+			case "example", "default", "enum":
+				out[key] = nested
+				continue
+			}
+			out[key] = swagger2Schema(nested)
+		}
+		if out["type"] == "file" {
+			out["type"], out["format"] = "string", "binary"
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i := range value {
+			out[i] = swagger2Schema(value[i])
+		}
+		return out
+	}
+	return v
+}
+
+// openAPI3ToSwagger2 converts decoded OpenAPI 3.0 document, constructs which
+// can't be expressed in Swagger 2.0 are reported as errors unless dropped
+// is not nil, in which case they are omitted and appended to dropped
+func openAPI3ToSwagger2(doc map[string]interface{}, dropped *[]string) (map[string]interface{}, error) {
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, errors.New("not an OpenAPI 3 document")
+	}
+	out := map[string]interface{}{"swagger": "2.0"}
+	copyKeys(out, doc, "info", "tags", "externalDocs", "security")
+	copyExtensions(out, doc)
+	err := openAPI3Servers(listValue(doc["servers"]), out)
+	if err != nil {
+		return nil, err
+	}
+
+	components := objectValue(doc["components"])
+	c := &openAPI3Converter{
+		requestBodies:  objectValue(components["requestBodies"]),
+		responses:      objectValue(components["responses"]),
+		dropped:        dropped,
+		droppedSchemes: make(map[string]bool),
+		droppedParams:  make(map[string]bool),
+	}
+	if schemas := objectValue(components["schemas"]); len(schemas) != 0 {
+		definitions := make(map[string]interface{})
+		for _, name := range sortedKeys(schemas) {
+			schema := schemas[name]
+			c.location = "schema " + name
+			definitions[name], err = c.schema(schema)
+			if err != nil {
+				return nil, errors.Wrap(err, "schema "+name)
+			}
+		}
+		out["definitions"] = definitions
+	}
+	if params := objectValue(components["parameters"]); len(params) != 0 {
+		converted := make(map[string]interface{})
+		for _, name := range sortedKeys(params) {
+			p := params[name]
+			c.location = "parameter " + name
+			param, err := c.parameter(objectValue(p))
+			if err != nil {
+				return nil, errors.Wrap(err, "parameter "+name)
+			}
+			if param == nil {
+				c.droppedParams["#/components/parameters/"+name] = true
+				continue
+			}
+			converted[name] = param
+		}
+		out["parameters"] = converted
+	}
+	if responses := objectValue(components["responses"]); len(responses) != 0 {
+		converted := make(map[string]interface{})
+		for _, name := range sortedKeys(responses) {
+			r := responses[name]
+			c.location = "response " + name
+			converted[name], _, err = c.response(objectValue(r))
+			if err != nil {
+				return nil, errors.Wrap(err, "response "+name)
+			}
+		}
+		out["responses"] = converted
+	}
+	if schemes := objectValue(components["securitySchemes"]); len(schemes) != 0 {
+		definitions := make(map[string]interface{})
+		for _, name := range sortedKeys(schemes) {
+			s := schemes[name]
+			c.location = "security scheme " + name
+			scheme, err := c.securityScheme(objectValue(s))
+			if err != nil {
+				return nil, errors.Wrap(err, "security scheme "+name)
+			}
+			if scheme == nil {
+				c.droppedSchemes[name] = true
+				continue
+			}
+			definitions[name] = scheme
+		}
+		out["securityDefinitions"] = definitions
+	}
+	if security, ok := out["security"]; ok {
+		out["security"] = c.security(security)
+	}
+
+	paths := make(map[string]interface{})
+	docPaths := objectValue(doc["paths"])
+	for _, path := range sortedKeys(docPaths) {
+		pathItem := objectValue(docPaths[path])
+		convertedItem := make(map[string]interface{})
+		copyExtensions(convertedItem, pathItem)
+		c.location = path
+		params, err := c.parameters(listValue(pathItem["parameters"]))
+		if err != nil {
+			return nil, errors.Wrap(err, path)
+		}
+		if len(params) != 0 {
+			convertedItem["parameters"] = params
+		}
+		for _, method := range httpMethods {
+			op := objectValue(pathItem[strings.ToLower(method)])
+			if op == nil {
+				continue
+			}
+			c.location = method + " " + path
+			convertedItem[strings.ToLower(method)], err = c.operation(op)
+			if err != nil {
+				return nil, errors.Wrap(err, method+" "+path)
+			}
+		}
+		paths[path] = convertedItem
+	}
+	out["paths"] = paths
+	return out, nil
+}
+
+// basePathsExt lists paths of all servers of OpenAPI 3.0 definition,
+// which are used as base paths unless WithBasePaths is set
+const basePathsExt = "x-base-paths"
+
+// openAPI3Servers sets schemes, host and basePath from server URLs,
+// host and basePath are taken from the first server. Paths of all servers
+// are listed in basePathsExt with variables kept, so that they match any value.
+func openAPI3Servers(servers []interface{}, out map[string]interface{}) error {
+	var schemes, basePaths []interface{}
+	seenPaths, seenSchemes := make(map[string]bool), make(map[string]bool)
+	for i, s := range servers {
+		server := objectValue(s)
+		rawURL, _ := server["url"].(string)
+		if basePath := serverPath(rawURL); !seenPaths[basePath] {
+			seenPaths[basePath] = true
+			basePaths = append(basePaths, basePath)
+		}
+		for name, v := range objectValue(server["variables"]) {
+			value, _ := objectValue(v)["default"].(string)
+			rawURL = strings.Replace(rawURL, "{"+name+"}", value, -1)
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse server url")
+		}
+		if u.Scheme != "" && !seenSchemes[u.Scheme] {
+			seenSchemes[u.Scheme] = true
+			schemes = append(schemes, u.Scheme)
+		}
+		if i != 0 {
+			continue
+		}
+		if u.Host != "" {
+			out["host"] = u.Host
+		}
+		if u.Path != "" {
+			out["basePath"] = u.Path
+		}
+	}
+	if len(schemes) != 0 {
+		out["schemes"] = schemes
+	}
+	if len(basePaths) != 0 {
+		out[basePathsExt] = basePaths
+	}
+	return nil
+}
+
+type openAPI3Converter struct {
+	requestBodies map[string]interface{}
+	responses     map[string]interface{}
+	// dropped lists unsupported constructs omitted from the converted document,
+	// they are reported as errors if it's nil
+	dropped        *[]string
+	droppedSchemes map[string]bool
+	droppedParams  map[string]bool
+	// location describes the part of the document being converted
+	location string
+}
+
+// unsupported reports construct which can't be expressed in Swagger 2.0,
+// it returns nil if the construct should be omitted instead
+func (c *openAPI3Converter) unsupported(construct string) error {
+	if c.dropped == nil {
+		return errors.New(construct + " is not supported by Swagger 2.0")
+	}
+	*c.dropped = append(*c.dropped, c.location+": "+construct)
+	return nil
+}
+
+// security omits dropped security schemes from security requirements,
+// so that requirements relying only on them are satisfied by any request
+func (c *openAPI3Converter) security(v interface{}) interface{} {
+	if len(c.droppedSchemes) == 0 {
+		return v
+	}
+	var requirements []interface{}
+	for _, r := range listValue(v) {
+		requirement := make(map[string]interface{})
+		for name, scopes := range objectValue(r) {
+			if !c.droppedSchemes[name] {
+				requirement[name] = scopes
+			}
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements
+}
+
+func (c *openAPI3Converter) operation(op map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	copyKeys(out, op, "tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security")
+	copyExtensions(out, op)
+	if security, ok := out["security"]; ok {
+		out["security"] = c.security(security)
+	}
+	params, err := c.parameters(listValue(op["parameters"]))
+	if err != nil {
+		return nil, err
+	}
+	if body := objectValue(op["requestBody"]); body != nil {
+		if ref, ok := body["$ref"].(string); ok {
+			body = objectValue(c.requestBodies[strings.TrimPrefix(ref, "#/components/requestBodies/")])
+			if body == nil {
+				return nil, errors.New("unresolved request body reference " + ref)
+			}
+		}
+		bodyParams, consumes, err := c.requestBody(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "request body")
+		}
+		params = append(params, bodyParams...)
+		if len(consumes) != 0 {
+			out["consumes"] = consumes
+		}
+	}
+	if len(params) != 0 {
+		out["parameters"] = params
+	}
+
+	responses := make(map[string]interface{})
+	producesSet := make(map[string]bool)
+	for code, r := range objectValue(op["responses"]) {
+		if strings.HasPrefix(code, "x-") {
+			responses[code] = r
+			continue
+		}
+		var produces []string
+		responses[code], produces, err = c.response(objectValue(r))
+		if err != nil {
+			return nil, errors.Wrap(err, "response "+code)
+		}
+		if ref, ok := objectValue(r)["$ref"].(string); ok {
+			referenced := objectValue(c.responses[strings.TrimPrefix(ref, "#/components/responses/")])
+			produces = sortedKeys(objectValue(referenced["content"]))
+		}
+		for _, mediaType := range produces {
+			producesSet[mediaType] = true
+		}
+	}
+	out["responses"] = responses
+	if len(producesSet) != 0 {
+		out["produces"] = sortedKeys(producesSet)
+	}
+	return out, nil
+}
+
+func (c *openAPI3Converter) parameters(params []interface{}) ([]interface{}, error) {
+	var converted []interface{}
+	for _, p := range params {
+		param, err := c.parameter(objectValue(p))
+		if err != nil {
+			return nil, errors.Wrap(err, "parameter")
+		}
+		if param != nil {
+			converted = append(converted, param)
+		}
+	}
+	return converted, nil
+}
+
+// parameter converts parameter, it returns nil parameter if it's omitted
+func (c *openAPI3Converter) parameter(param map[string]interface{}) (map[string]interface{}, error) {
+	if ref, ok := param["$ref"].(string); ok {
+		if c.droppedParams[ref] {
+			return nil, nil
+		}
+		return map[string]interface{}{"$ref": rewriteRef(ref, true)}, nil
+	}
+	name, _ := param["name"].(string)
+	if param["in"] == "cookie" {
+		return nil, c.unsupported("cookie parameter " + name)
+	}
+	if _, ok := param["content"]; ok {
+		return nil, c.unsupported("content of parameter " + name)
+	}
+	out := make(map[string]interface{})
+	copyKeys(out, param, "name", "in", "description", "required", "allowEmptyValue")
+	copyExtensions(out, param)
+	err := schemaToSimple(objectValue(param["schema"]), out)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+	if out["type"] != "array" {
+		return out, nil
+	}
+	style, _ := param["style"].(string)
+	explode, hasExplode := param["explode"].(bool)
+	switch style {
+	case "spaceDelimited":
+		out["collectionFormat"] = "ssv"
+	case "pipeDelimited":
+		out["collectionFormat"] = "pipes"
+	case "", "form":
+		if param["in"] == "query" && (explode || !hasExplode) {
+			out["collectionFormat"] = "multi"
+		} else {
+			out["collectionFormat"] = "csv"
+		}
+	default:
+		out["collectionFormat"] = "csv"
+	}
+	return out, nil
+}
+
+// schemaToSimple flattens schema into non-body parameter, items or header
+func schemaToSimple(schema, out map[string]interface{}) error {
+	if _, ok := schema["$ref"]; ok {
+		return errors.New("schema references are not supported by Swagger 2.0 for non-body values")
+	}
+	copyKeys(out, schema, simpleSchemaKeys...)
+	if schema["type"] == "string" && schema["format"] == "binary" {
+		out["type"] = "file"
+		delete(out, "format")
+	}
+	if items := objectValue(schema["items"]); items != nil {
+		convertedItems := make(map[string]interface{})
+		err := schemaToSimple(items, convertedItems)
+		if err != nil {
+			return errors.Wrap(err, "items")
+		}
+		out["items"] = convertedItems
+	}
+	return nil
+}
+
+func (c *openAPI3Converter) requestBody(body map[string]interface{}) ([]interface{}, []string, error) {
+	content := objectValue(body["content"])
+	consumes := sortedKeys(content)
+	for _, formType := range formMediaTypes {
+		media := objectValue(content[formType])
+		if media == nil {
+			continue
+		}
+		schema := objectValue(media["schema"])
+		if _, ok := schema["$ref"]; ok {
+			return nil, nil, errors.New("form schema references are not supported by Swagger 2.0")
+		}
+		required := make(map[string]bool)
+		for _, name := range listValue(schema["required"]) {
+			if n, ok := name.(string); ok {
+				required[n] = true
+			}
+		}
+		properties := objectValue(schema["properties"])
+		var params []interface{}
+		for _, name := range sortedKeys(properties) {
+			property := objectValue(properties[name])
+			param := map[string]interface{}{"name": name, "in": "formData"}
+			copyKeys(param, property, "description")
+			if required[name] {
+				param["required"] = true
+			}
+			err := schemaToSimple(property, param)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, name)
+			}
+			params = append(params, param)
+		}
+		return params, consumes, nil
+	}
+
+	param := map[string]interface{}{"name": "body", "in": "body"}
+	if name, ok := body["x-codegen-request-body-name"].(string); ok {
+		param["name"] = name
+	}
+	copyKeys(param, body, "description", "required")
+	if len(consumes) != 0 {
+		schema, err := c.schema(objectValue(content[preferredMediaType(consumes)])["schema"])
+		if err != nil {
+			return nil, nil, err
+		}
+		param["schema"] = schema
+	}
+	return []interface{}{param}, consumes, nil
+}
+
+// response converts response, second return parameter lists media types of its content
+func (c *openAPI3Converter) response(r map[string]interface{}) (map[string]interface{}, []string, error) {
+	if ref, ok := r["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": rewriteRef(ref, true)}, nil, nil
+	}
+	out := make(map[string]interface{})
+	copyKeys(out, r, "description")
+	copyExtensions(out, r)
+	if headers := objectValue(r["headers"]); len(headers) != 0 {
+		converted := make(map[string]interface{})
+		for name, h := range headers {
+			header := objectValue(h)
+			convertedHeader := make(map[string]interface{})
+			copyKeys(convertedHeader, header, "description")
+			err := schemaToSimple(objectValue(header["schema"]), convertedHeader)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "header "+name)
+			}
+			converted[name] = convertedHeader
+		}
+		out["headers"] = converted
+	}
+	content := objectValue(r["content"])
+	produces := sortedKeys(content)
+	if len(produces) == 0 {
+		return out, nil, nil
+	}
+	examples := make(map[string]interface{})
+	for mediaType, m := range content {
+		if example, ok := objectValue(m)["example"]; ok {
+			examples[mediaType] = example
+		}
+	}
+	setNonEmpty(out, "examples", examples)
+	if schema, ok := objectValue(content[preferredMediaType(produces)])["schema"]; ok {
+		converted, err := c.schema(schema)
+		if err != nil {
+			return nil, nil, err
+		}
+		out["schema"] = converted
+	}
+	return out, produces, nil
+}
+
+// securityScheme converts security scheme, it returns nil scheme if it's omitted
+func (c *openAPI3Converter) securityScheme(s map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	copyKeys(out, s, "description")
+	copyExtensions(out, s)
+	switch s["type"] {
+	case "apiKey":
+		if s["in"] == "cookie" {
+			return nil, c.unsupported("cookie api key")
+		}
+		copyKeys(out, s, "type", "name", "in")
+	case "http":
+		if s["scheme"] != "basic" {
+			return nil, c.unsupported(fmt.Sprintf("http %v authentication", s["scheme"]))
+		}
+		out["type"] = "basic"
+	case "oauth2":
+		out["type"] = "oauth2"
+		flows := objectValue(s["flows"])
+		for _, f := range [][2]string{
+			{"implicit", "implicit"},
+			{"password", "password"},
+			{"clientCredentials", "application"},
+			{"authorizationCode", "accessCode"},
+		} {
+			flow := objectValue(flows[f[0]])
+			if flow == nil {
+				continue
+			}
+			out["flow"] = f[1]
+			copyKeys(out, flow, "authorizationUrl", "tokenUrl", "scopes")
+			break
+		}
+	default:
+		return nil, c.unsupported(fmt.Sprintf("%v security scheme", s["type"]))
+	}
+	return out, nil
+}
+
+// schema converts OpenAPI 3.0 schema to Swagger 2.0 one
+func (c *openAPI3Converter) schema(v interface{}) (interface{}, error) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for _, key := range sortedKeys(value) {
+			nested := value[key]
+			switch key {
+			case "oneOf", "anyOf", "not":
+				err := c.unsupported(key)
+				if err != nil {
+					return nil, err
+				}
+				// kept as an extension, which also keeps the schema from being
+				// empty, as empty definitions are lost on loading
+				out["x-"+key] = nested
+				continue
+			case "$ref":
+				if ref, ok := nested.(string); ok {
+					out[key] = rewriteRef(ref, true)
+					continue
+				}
+			case "nullable":
+				out["x-nullable"] = nested
+				continue
+			case "discriminator":
+				if name, ok := objectValue(nested)["propertyName"]; ok {
+					out[key] = name
+					continue
+				}
+			case "example", "default", "enum":
+				out[key] = nested
+				continue
+			case "properties", "definitions", "patternProperties":
+				// keys of these maps are names rather than keywords
+				if schemas, ok := nested.(map[string]interface{}); ok {
+					converted := make(map[string]interface{}, len(schemas))
+					for _, name := range sortedKeys(schemas) {
+						schema, err := c.schema(schemas[name])
+						if err != nil {
+							return nil, errors.Wrap(err, key+"."+name)
+						}
+						converted[name] = schema
+					}
+					out[key] = converted
+					continue
+				}
+			}
+			converted, err := c.schema(nested)
+			if err != nil {
+				return nil, errors.Wrap(err, key)
+			}
+			out[key] = converted
+		}
+		c.nullableType(value, out)
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i := range value {
+			converted, err := c.schema(value[i])
+			if err != nil {
+				return nil, err
+			}
+			out[i] = converted
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// nullableType allows null values of nullable schema, which x-nullable
+// extension doesn't as it isn't understood by the validator. The type is
+// only extended with null for verification, as Swagger 2.0 documents
+// can't list several types.
+func (c *openAPI3Converter) nullableType(schema, out map[string]interface{}) {
+	if nullable, _ := schema["nullable"].(bool); !nullable || c.dropped == nil {
+		return
+	}
+	if t, ok := out["type"].(string); ok {
+		out["type"] = []interface{}{t, "null"}
+	}
+}
+
+// rewriteRef rewrites local Swagger 2.0 reference to OpenAPI 3.0 one or vice versa
+func rewriteRef(ref string, toSwagger2 bool) string {
+	for _, prefixes := range refPrefixes {
+		from, to := prefixes[0], prefixes[1]
+		if toSwagger2 {
+			from, to = to, from
+		}
+		if strings.HasPrefix(ref, from) {
+			return to + strings.TrimPrefix(ref, from)
+		}
+	}
+	return ref
+}
+
+// preferredMediaType returns JSON media type if it is listed,
+// otherwise the first media type
+func preferredMediaType(mediaTypes []string) string {
+	for _, mediaType := range mediaTypes {
+		if strings.Contains(mediaType, "json") {
+			return mediaType
+		}
+	}
+	return mediaTypes[0]
+}
+
+func copyKeys(dst, src map[string]interface{}, keys ...string) {
+	for _, key := range keys {
+		if v, ok := src[key]; ok {
+			dst[key] = v
+		}
+	}
+}
+
+func copyExtensions(dst, src map[string]interface{}) {
+	for key, v := range src {
+		if strings.HasPrefix(key, "x-") {
+			dst[key] = v
+		}
+	}
+}
+
+func setNonEmpty(dst map[string]interface{}, key string, v map[string]interface{}) {
+	if len(v) != 0 {
+		dst[key] = v
+	}
+}
+
+func objectValue(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func listValue(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func stringList(v interface{}) []string {
+	var list []string
+	for _, item := range listValue(v) {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// sortedKeys returns sorted keys of map[string]interface{} or map[string]bool
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]interface{}:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]bool:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]string:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,214 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToOpenAPI3(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, ConvertToOpenAPI3(testdata+sampleV2YAML, buf))
+
+	doc := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "3.0.0", doc["openapi"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"url": "http://petstore.swagger.io/v2"},
+	}, doc["servers"])
+
+	paths := objectValue(doc["paths"])
+	addPet := objectValue(objectValue(paths["/pet"])["post"])
+	assert.Equal(t, map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/Pet"}},
+		"application/xml":  map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/Pet"}},
+	}, objectValue(addPet["requestBody"])["content"])
+
+	upload := objectValue(objectValue(paths["/pet/{petId}/uploadImage"])["post"])
+	form := objectValue(objectValue(upload["requestBody"])["content"])
+	schema := objectValue(objectValue(form["multipart/form-data"])["schema"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "binary", "description": "file to upload"},
+		objectValue(schema["properties"])["file"])
+
+	findByStatus := objectValue(objectValue(paths["/pet/findByStatus"])["get"])
+	status := objectValue(listValue(findByStatus["parameters"])[0])
+	assert.Equal(t, "form", status["style"])
+	assert.Equal(t, true, status["explode"])
+
+	schemes := objectValue(objectValue(doc["components"])["securitySchemes"])
+	assert.Equal(t, "implicit", sortedKeys(objectValue(objectValue(schemes["petstore_auth"])["flows"]))[0])
+
+	assert.Regexp(t, "not a Swagger 2.0 document", ConvertToOpenAPI3(testdata+"cassette.yaml", &bytes.Buffer{}))
+}
+
+func TestConvertToSwagger2(t *testing.T) {
+	dir, err := ioutil.TempDir("", "revisor")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, ConvertToOpenAPI3(testdata+sampleV2YAML, buf))
+	v3Path := filepath.Join(dir, "v3.json")
+	require.NoError(t, ioutil.WriteFile(v3Path, buf.Bytes(), 0644))
+
+	buf.Reset()
+	require.NoError(t, ConvertToSwagger2(v3Path, buf))
+	v2Path := filepath.Join(dir, "v2.json")
+	require.NoError(t, ioutil.WriteFile(v2Path, buf.Bytes(), 0644))
+
+	t.Run("definitions survive round trip", func(t *testing.T) {
+		original, err := loadJSONObject(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		converted, err := loadJSONObject(v2Path)
+		require.NoError(t, err)
+		assert.Equal(t, original["definitions"], converted["definitions"])
+		assert.Equal(t, original["securityDefinitions"], converted["securityDefinitions"])
+		assert.Equal(t, original["host"], converted["host"])
+		assert.Equal(t, original["basePath"], converted["basePath"])
+	})
+
+	t.Run("converted definition verifies", func(t *testing.T) {
+		verify, err := NewVerifier(v2Path)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		_, err = rec.WriteString(`{"id":"1"}`)
+		require.NoError(t, err)
+		assert.Regexp(t, "id in body must be of type integer",
+			verify(rec.Result(), httptest.NewRequest("GET", "/v2/user/testuser", nil)))
+	})
+
+	t.Run("unsupported constructs", func(t *testing.T) {
+		for name, doc := range map[string]string{
+			"oneOf":  `{"openapi":"3.0.0","paths":{},"components":{"schemas":{"A":{"oneOf":[{"type":"string"}]}}}}`,
+			"cookie": `{"openapi":"3.0.0","paths":{"/a":{"get":{"parameters":[{"name":"c","in":"cookie","schema":{"type":"string"}}]}}}}`,
+			"bearer": `{"openapi":"3.0.0","paths":{},"components":{"securitySchemes":{"b":{"type":"http","scheme":"bearer"}}}}`,
+		} {
+			path := filepath.Join(dir, name+".json")
+			require.NoError(t, ioutil.WriteFile(path, []byte(doc), 0644))
+			assert.Regexp(t, "not supported by Swagger 2.0", ConvertToSwagger2(path, &bytes.Buffer{}), name)
+		}
+		assert.Regexp(t, "not an OpenAPI 3 document", ConvertToSwagger2(testdata+sampleV2YAML, &bytes.Buffer{}))
+	})
+}
+
+func TestOpenAPI3Definition(t *testing.T) {
+	verify, err := NewVerifier(testdata + "openapi3.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		status   int
+		response string
+		err      string
+	}{
+		{"valid exchange", "POST", "/v1/pets", `{"name":"Rex"}`, 201, `{"id":1,"name":"Rex"}`, ""},
+		{"invalid request body", "POST", "/v1/pets", `{"id":1}`, 201, `{"id":1,"name":"Rex"}`, "name in body is required"},
+		{"path outside of server URL", "GET", "/pets/1", "", 200, `{"name":"Rex"}`, "no path template matches"},
+		{"path of another server", "GET", "/v2/pets/1", "", 200, `{"name":"Rex"}`, ""},
+		{"server variable", "GET", "/other/api/pets/1", "", 200, `{"name":"Rex"}`, ""},
+		{"invalid response body", "GET", "/v1/pets/1", "", 200, `{"id":1}`, "name in body is required"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.url, bytes.NewBufferString(test.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			rec.WriteHeader(test.status)
+			rec.WriteString(test.response)
+
+			err := verify(rec.Result(), req)
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("schemas", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "revisor-openapi3")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(`{"openapi":"3.0.0","paths":{"/pets":{"post":{
+			"requestBody":{"required":true,"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}},
+			"responses":{"201":{"description":"created"}}}}},
+			"components":{"schemas":{
+			"Pet":{"type":"object","required":["name"],"properties":{
+				"name":{"type":"string"},
+				"nickname":{"type":"string","nullable":true},
+				"default":{"$ref":"#/components/schemas/Tag"},
+				"enum":{"type":"string","enum":["a"]}}},
+			"Tag":{"type":"object","properties":{"label":{"type":"string"}}}}}}`)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		verify, err := NewRequestVerifier(f.Name(), WithLogger(func(string, ...interface{}) {}))
+		require.NoError(t, err)
+		request := func(body string) *http.Request {
+			req := httptest.NewRequest("POST", "/pets", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		}
+
+		assert.NoError(t, verify(request(`{"name":"rex","nickname":null,"default":{"label":"x"},"enum":"a"}`)),
+			"nullable schema accepts null, keyword named properties are schemas")
+		assert.Regexp(t, "nickname in body must be of type string", verify(request(`{"name":"rex","nickname":1}`)))
+
+		err = verify(request(`{"name":"rex","default":{"label":1}}`))
+		mismatches := Mismatches(err)
+		require.Len(t, mismatches, 1, "%v", err)
+		assert.Equal(t, "#/components/schemas/Tag/properties/label", mismatches[0].Schema)
+	})
+
+	t.Run("unsupported constructs", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "revisor-openapi3")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(`{"openapi":"3.0.0","paths":{"/a":{"get":{
+			"security":[{"b":[]}],
+			"parameters":[{"name":"c","in":"cookie","required":true,"schema":{"type":"string"}}],
+			"responses":{"200":{"description":"ok","content":{"application/json":{"schema":{"$ref":"#/components/schemas/A"}}}}}}}},
+			"components":{"schemas":{"A":{"oneOf":[{"type":"string"}]}},"securitySchemes":{"b":{"type":"http","scheme":"bearer"}}}}`)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		var logged []string
+		logf := func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}
+		verify, err := NewVerifier(f.Name(), WithLogger(logf))
+		require.NoError(t, err)
+		assert.Subset(t, logged, []string{
+			"revisor: warning: schema A: oneOf is not supported by Swagger 2.0 and is not validated",
+			"revisor: warning: security scheme b: http bearer authentication is not supported by Swagger 2.0 and is not validated",
+			"revisor: warning: GET /a: cookie parameter c is not supported by Swagger 2.0 and is not validated",
+		})
+
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		rec.WriteString(`1`)
+		assert.NoError(t, verify(rec.Result(), httptest.NewRequest("GET", "/a", nil)))
+
+		buf := &bytes.Buffer{}
+		log.SetOutput(buf)
+		defer log.SetOutput(os.Stderr)
+		_, err = NewVerifier(f.Name())
+		require.NoError(t, err)
+		assert.Empty(t, buf.String(), "nothing is logged by default")
+	})
+}
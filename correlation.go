@@ -0,0 +1,37 @@
+package revisor
+
+import "net/http"
+
+// defaultCorrelationHeader is the correlation header checked by default
+const defaultCorrelationHeader = "X-Request-ID"
+
+// CheckCorrelationHeader enables checks that responses echo correlation
+// header of the request with the name, which is X-Request-ID if it is empty.
+// Responses to requests with the header, which don't have it or have
+// a different value, are reported as Violation with CodeCorrelationHeader code.
+func CheckCorrelationHeader(name string) option {
+	return func(a *apiVerifier) {
+		if name == "" {
+			name = defaultCorrelationHeader
+		}
+		a.opts.correlationHeader = name
+	}
+}
+
+// verifyCorrelationHeader checks that the response echoes correlation header of the request
+func (a *apiVerifier) verifyCorrelationHeader(req *http.Request, res *http.Response) error {
+	name := a.opts.correlationHeader
+	id := req.Header.Get(name)
+	if id == "" {
+		return nil
+	}
+	echoed, ok := res.Header[http.CanonicalHeaderKey(name)]
+	if !ok {
+		return newViolation(CodeCorrelationHeader, name+" header of the request is not echoed")
+	}
+	if len(echoed) != 1 || echoed[0] != id {
+		return newViolation(CodeCorrelationHeader, name+" header doesn't match the request: "+
+			"expected "+id+", got "+res.Header.Get(name))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCorrelationHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		requestID string
+		echoed    []string
+		wantCode  string
+	}{
+		{
+			name:      "echoed",
+			requestID: "abc", echoed: []string{"abc"},
+		},
+		{
+			name: "no request header",
+		},
+		{
+			name:      "missing",
+			requestID: "abc",
+			wantCode:  CodeCorrelationHeader,
+		},
+		{
+			name:      "mismatch",
+			requestID: "abc", echoed: []string{"def"},
+			wantCode: CodeCorrelationHeader,
+		},
+		{
+			name:      "echoed twice",
+			requestID: "abc", echoed: []string{"abc", "abc"},
+			wantCode: CodeCorrelationHeader,
+		},
+		{
+			name:   "custom header",
+			header: "X-Correlation-ID", requestID: "abc", echoed: []string{"abc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+sampleV2YAML, CheckCorrelationHeader(tt.header))
+			require.NoError(t, err)
+			header := tt.header
+			if header == "" {
+				header = "X-Request-ID"
+			}
+
+			req := httptest.NewRequest("GET", "/v2/pet/1", nil)
+			if tt.requestID != "" {
+				req.Header.Set(header, tt.requestID)
+			}
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			for _, id := range tt.echoed {
+				rec.Header().Add(header, id)
+			}
+			rec.Body = bytes.NewBufferString(`{"name": "doggie", "photoUrls": []}`)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), "", tt.wantCode)
+		})
+	}
+}
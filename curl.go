@@ -0,0 +1,91 @@
+package revisor
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ReportCurlCommand makes request verification errors carry a curl command
+// reconstructing the offending request, which can be retrieved with CurlCommand
+func ReportCurlCommand(a *apiVerifier) {
+	a.opts.reportCurl = true
+}
+
+// curlError is a request verification error annotated with a curl command
+type curlError struct {
+	err  error
+	curl string
+}
+
+func newCurlError(err error, req *http.Request) error {
+	return &curlError{err: err, curl: curlCommand(req)}
+}
+
+func (e *curlError) Error() string {
+	return e.err.Error()
+}
+
+// Cause returns the underlying error, so that errors.Cause keeps working
+func (e *curlError) Cause() error {
+	return e.err
+}
+
+// CurlCommand returns curl command reconstructing the request which failed
+// verification, if verifier was created with ReportCurlCommand option.
+// Empty string is returned otherwise.
+func CurlCommand(err error) string {
+	for err != nil {
+		if e, ok := err.(*curlError); ok {
+			return e.curl
+		}
+		cause, ok := err.(interface {
+			Cause() error
+		})
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return ""
+}
+
+// curlCommand builds curl command sending the same request
+func curlCommand(req *http.Request) string {
+	args := []string{"curl", "-X", req.Method}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			args = append(args, "-H", shellQuote(name+": "+value))
+		}
+	}
+	body, err := readRequestBody(req)
+	if err == nil && len(body) != 0 {
+		args = append(args, "--data-binary", shellQuote(string(body)))
+	}
+	return strings.Join(append(args, shellQuote(requestURL(req))), " ")
+}
+
+// requestURL returns absolute URL of the request, which is
+// reconstructed from Host header for server requests
+func requestURL(req *http.Request) string {
+	u := *req.URL
+	if u.Host == "" {
+		u.Host = req.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if req.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	return u.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
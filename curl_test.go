@@ -0,0 +1,32 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurlCommand(t *testing.T) {
+	verifyRequest, err := NewRequestVerifier(testdata+sampleV2YAML, ReportCurlCommand)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/v2/pet", strings.NewReader(`{"name":"it's"}`))
+	req.Header.Set("Content-Type", "application/json")
+	err = verifyRequest(req)
+	require.Error(t, err)
+	assert.Equal(t,
+		`curl -X POST -H 'Content-Type: application/json' --data-binary '{"name":"it'\''s"}' 'http://example.com/v2/pet'`,
+		CurlCommand(errors.Wrap(err, "wrapped")))
+
+	err = verifyRequest(httptest.NewRequest("GET", "/v2/undocumented", nil))
+	assert.Equal(t, `curl -X GET 'http://example.com/v2/undocumented'`, CurlCommand(err))
+	assert.IsType(t, &Violation{}, errors.Cause(err))
+
+	verifyRequest, err = NewRequestVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	assert.Empty(t, CurlCommand(verifyRequest(httptest.NewRequest("GET", "/v2/undocumented", nil))))
+}
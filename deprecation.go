@@ -0,0 +1,30 @@
+package revisor
+
+import "net/http"
+
+// WarnDeprecated enables warnings logged whenever operations marked as
+// deprecated in API document are invoked, so that their callers can be found
+// before operations are removed.
+func WarnDeprecated(a *apiVerifier) {
+	a.opts.warnDeprecated = true
+}
+
+// RejectDeprecated enables rejection of requests to operations marked as
+// deprecated in API document, which are reported as Violation with
+// CodeDeprecatedOperation code.
+func RejectDeprecated(a *apiVerifier) {
+	a.opts.rejectDeprecated = true
+}
+
+// verifyDeprecation warns about or rejects request to deprecated operation
+func (a *apiVerifier) verifyDeprecation(req *http.Request) error {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !operation.Deprecated {
+		return err
+	}
+	if a.opts.rejectDeprecated {
+		return newViolation(CodeDeprecatedOperation, "operation "+a.operationKey(req, operation)+" is deprecated")
+	}
+	a.opts.logf("revisor: warning: deprecated operation %s is invoked", a.operationKey(req, operation))
+	return nil
+}
@@ -0,0 +1,72 @@
+package revisor
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecated(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      []option
+		path         string
+		wantCode     string
+		wantWarnings []string
+	}{
+		{
+			name: "default",
+			path: "/v2/pet/findByTags?tags=dog",
+		},
+		{
+			name:         "warn",
+			options:      []option{WarnDeprecated},
+			path:         "/v2/pet/findByTags?tags=dog",
+			wantWarnings: []string{"revisor: warning: deprecated operation findPetsByTags is invoked"},
+		},
+		{
+			name:     "reject",
+			options:  []option{RejectDeprecated},
+			path:     "/v2/pet/findByTags?tags=dog",
+			wantCode: CodeDeprecatedOperation,
+		},
+		{
+			name:    "not deprecated",
+			options: []option{WarnDeprecated, RejectDeprecated},
+			path:    "/v2/pet/findByStatus?status=sold",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings []string
+			logf := func(format string, args ...interface{}) {
+				warnings = append(warnings, fmt.Sprintf(format, args...))
+			}
+			v, err := New(testdata+sampleV2YAML, append(tt.options, WithLogger(logf))...)
+			require.NoError(t, err)
+			warnings = nil
+
+			assertVerifyErr(t, v.VerifyRequest(httptest.NewRequest("GET", tt.path, nil)), "", tt.wantCode)
+			assert.Equal(t, tt.wantWarnings, warnings)
+		})
+	}
+}
+
+func TestDeprecated_Metrics(t *testing.T) {
+	sink := &recordingSink{}
+	v, err := New(testdata+sampleV2YAML, WithMetrics(sink), RejectDeprecated)
+	require.NoError(t, err)
+
+	assert.NoError(t, v.VerifyRequest(httptest.NewRequest("GET", "/v2/pet/findByStatus?status=sold", nil)))
+	assert.Error(t, v.VerifyRequest(httptest.NewRequest("GET", "/v2/pet/findByTags?tags=dog", nil)))
+
+	assert.Equal(t, []string{
+		"verifications kind=request operation=findPetsByStatus",
+		"verifications kind=request operation=findPetsByTags",
+		"deprecated_calls kind=request operation=findPetsByTags",
+		"violations code=deprecated_operation kind=request operation=findPetsByTags",
+	}, sink.counters)
+}
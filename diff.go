@@ -0,0 +1,327 @@
+package revisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	openapierrors "github.com/go-openapi/errors"
+	"github.com/go-openapi/spec"
+)
+
+// maxReferenceChain limits how many references are followed in a row
+const maxReferenceChain = 32
+
+// Mismatch describes a body value which type or format doesn't match the schema
+type Mismatch struct {
+	// Pointer is JSON pointer of the offending value in the body
+	Pointer string
+	// Schema is JSON pointer of the governing schema in the original definition
+	Schema string
+	// Expected is a type, optionally followed by a format, required by the schema
+	Expected string
+	// Actual is the offending value
+	Actual interface{}
+}
+
+func (m Mismatch) String() string {
+	s := fmt.Sprintf("%s: expected: %s from schema %s, got: %s",
+		m.Pointer, m.Expected, m.Schema, jsonType(m.Actual))
+	if m.Actual != nil {
+		actual, _ := json.Marshal(m.Actual)
+		s += " " + string(actual)
+	}
+	return s
+}
+
+// bodyError is a body validation error annotated with type and format mismatches
+type bodyError struct {
+	err        error
+	mismatches []Mismatch
+}
+
+func (e *bodyError) Error() string {
+	lines := []string{e.err.Error()}
+	for _, m := range e.mismatches {
+		lines = append(lines, m.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Cause returns the underlying error, so that errors.Cause keeps working
+func (e *bodyError) Cause() error {
+	return e.err
+}
+
+// Mismatches returns type and format mismatches of the body which failed verification
+func Mismatches(err error) []Mismatch {
+	for err != nil {
+		if e, ok := err.(*bodyError); ok {
+			return e.mismatches
+		}
+		cause, ok := err.(interface {
+			Cause() error
+		})
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return nil
+}
+
+// describeBodyError annotates schema validation error with type and format
+// mismatches, schema is the body schema in the original definition found at pointer
+func describeBodyError(err error, decoded interface{}, root *spec.Swagger, schema *spec.Schema, pointer string) error {
+	if err == nil || schema == nil {
+		return err
+	}
+	var mismatches []Mismatch
+	for _, path := range invalidTypePaths(err) {
+		var tokens []string
+		if path != "" {
+			tokens = strings.Split(path, ".")
+		}
+		value, ok := valueAt(decoded, tokens)
+		if !ok {
+			continue
+		}
+		governing, schemaPointer, ok := schemaAt(root, schema, pointer, tokens)
+		if !ok {
+			continue
+		}
+		dataPointer := ""
+		for _, token := range tokens {
+			dataPointer += "/" + escapePointerToken(token)
+		}
+		mismatches = append(mismatches, mismatchesAt(root, value, governing, schemaPointer, dataPointer)...)
+	}
+	if len(mismatches) == 0 {
+		return err
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Pointer < mismatches[j].Pointer
+	})
+	return &bodyError{err: err, mismatches: mismatches}
+}
+
+// describeBodyError annotates schema validation error of the body of the
+// exchange, schema pointers of OpenAPI 3.0 definitions point to components
+// of the original document rather than to the converted one
+func (a *apiVerifier) describeBodyError(err error, decoded interface{}, schema *spec.Schema, pointer string) error {
+	err = describeBodyError(err, decoded, a.doc.OrigSpec(), schema, pointer)
+	if e, ok := err.(*bodyError); ok && a.openAPI3 {
+		for i := range e.mismatches {
+			e.mismatches[i].Schema = rewriteRef(e.mismatches[i].Schema, false)
+		}
+	}
+	return err
+}
+
+// mismatchesAt returns mismatches of the value reported with invalid type error.
+// Reported paths of array items don't include indexes, so items are checked here.
+func mismatchesAt(root *spec.Swagger, value interface{}, schema *spec.Schema, schemaPointer, dataPointer string) []Mismatch {
+	if items, ok := value.([]interface{}); ok && schema.Type.Contains("array") {
+		var mismatches []Mismatch
+		for i, item := range items {
+			index := strconv.Itoa(i)
+			governing, itemPointer, ok := schemaAt(root, schema, schemaPointer, []string{index})
+			if ok && !matchesType(governing, item) {
+				mismatches = append(mismatches, mismatchesAt(root, item, governing, itemPointer, dataPointer+"/"+index)...)
+			}
+		}
+		return mismatches
+	}
+	expected := strings.Join(schema.Type, ",")
+	if schema.Format != "" {
+		expected += " (" + schema.Format + ")"
+	}
+	return []Mismatch{{Pointer: dataPointer, Schema: schemaPointer, Expected: expected, Actual: value}}
+}
+
+// matchesType reports if JSON type of the value is allowed by the schema
+func matchesType(schema *spec.Schema, value interface{}) bool {
+	if len(schema.Type) == 0 {
+		return true
+	}
+	t := jsonType(value)
+	return schema.Type.Contains(t) || (t == "integer" && schema.Type.Contains("number"))
+}
+
+// invalidTypePaths returns dot separated paths of values reported with invalid type errors
+func invalidTypePaths(err error) []string {
+	switch e := err.(type) {
+	case *openapierrors.CompositeError:
+		var paths []string
+		for _, nested := range e.Errors {
+			paths = append(paths, invalidTypePaths(nested)...)
+		}
+		return paths
+	case *openapierrors.Validation:
+		if e.Code() == openapierrors.InvalidTypeCode {
+			return []string{e.Name}
+		}
+	}
+	return nil
+}
+
+// valueAt returns decoded value found at the path
+func valueAt(value interface{}, path []string) (interface{}, bool) {
+	for _, token := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// schemaAt follows the path through the schema in the original definition, resolving
+// local references, and returns the schema governing the value with its JSON pointer
+func schemaAt(root *spec.Swagger, schema *spec.Schema, pointer string, path []string) (*spec.Schema, string, bool) {
+	for i := 0; schema.Ref.String() != "" && i < maxReferenceChain; i++ {
+		pointer = schema.Ref.String()
+		resolved, err := spec.ResolveRef(root, &schema.Ref)
+		if err != nil || !strings.HasPrefix(pointer, "#/") {
+			return nil, "", false
+		}
+		schema = resolved
+	}
+	if len(path) == 0 {
+		return schema, pointer, true
+	}
+	token, rest := path[0], path[1:]
+	if prop, ok := schema.Properties[token]; ok {
+		return schemaAt(root, &prop, pointer+"/properties/"+escapePointerToken(token), rest)
+	}
+	if _, err := strconv.Atoi(token); err == nil && schema.Items != nil && schema.Items.Schema != nil {
+		return schemaAt(root, schema.Items.Schema, pointer+"/items", rest)
+	}
+	for i := range schema.AllOf {
+		if governing, p, ok := schemaAt(root, &schema.AllOf[i], pointer+"/allOf/"+strconv.Itoa(i), path); ok {
+			return governing, p, true
+		}
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		return schemaAt(root, schema.AdditionalProperties.Schema, pointer+"/additionalProperties", rest)
+	}
+	return nil, "", false
+}
+
+// requestSchemaOrigin returns body parameter schema of the operation
+// in the original definition with its JSON pointer
+func (a *apiVerifier) requestSchemaOrigin(req *http.Request) (*spec.Schema, string) {
+	root := a.doc.OrigSpec()
+	tmpl, pathItem, operation, ok := a.origOperation(req)
+	if !ok {
+		return nil, ""
+	}
+	base := "#/paths/" + escapePointerToken(tmpl)
+	for _, params := range []struct {
+		pointer string
+		list    []spec.Parameter
+	}{
+		{base + "/" + strings.ToLower(req.Method) + "/parameters/", operation.Parameters},
+		{base + "/parameters/", pathItem.Parameters},
+	} {
+		for i, param := range params.list {
+			pointer := params.pointer + strconv.Itoa(i)
+			if param.Ref.String() != "" {
+				pointer = param.Ref.String()
+				resolved, err := spec.ResolveParameter(root, param.Ref)
+				if err != nil {
+					return nil, ""
+				}
+				param = *resolved
+			}
+			if param.In == "body" {
+				return param.Schema, pointer + "/schema"
+			}
+		}
+	}
+	return nil, ""
+}
+
+// responseSchemaOrigin returns schema of the response in the original
+// definition with its JSON pointer
+func (a *apiVerifier) responseSchemaOrigin(req *http.Request, res *http.Response) (*spec.Schema, string) {
+	tmpl, _, operation, ok := a.origOperation(req)
+	if !ok || operation.Responses == nil {
+		return nil, ""
+	}
+	pointer := "#/paths/" + escapePointerToken(tmpl) + "/" + strings.ToLower(req.Method) + "/responses/"
+	response, ok := operation.Responses.StatusCodeResponses[res.StatusCode]
+	if ok {
+		pointer += strconv.Itoa(res.StatusCode)
+	} else if operation.Responses.Default != nil {
+		response = *operation.Responses.Default
+		pointer += "default"
+	} else {
+		return nil, ""
+	}
+	if response.Ref.String() != "" {
+		pointer = response.Ref.String()
+		resolved, err := spec.ResolveResponse(a.doc.OrigSpec(), response.Ref)
+		if err != nil {
+			return nil, ""
+		}
+		response = *resolved
+	}
+	return response.Schema, pointer + "/schema"
+}
+
+// origOperation returns path template, path item and operation of the request
+// in the original definition
+func (a *apiVerifier) origOperation(req *http.Request) (string, *spec.PathItem, *spec.Operation, bool) {
+	tmpl, _, ok := a.mapper.mapRequest(req)
+	if !ok {
+		return "", nil, nil, false
+	}
+	pathItem, ok := a.doc.OrigSpec().Paths.Paths[tmpl]
+	if !ok {
+		return "", nil, nil, false
+	}
+	operation := pathOperation(req.Method, &pathItem)
+	return tmpl, &pathItem, operation, operation != nil
+}
+
+// jsonType returns JSON type name of the decoded value
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,51 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMismatches(t *testing.T) {
+	verifyRequest, err := NewRequestVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/v2/pet", strings.NewReader(`{"id":"abc","name":"doggie","photoUrls":["a",1]}`))
+	req.Header.Set("Content-Type", "application/json")
+	err = verifyRequest(req)
+	require.Error(t, err)
+	assert.Equal(t, []Mismatch{
+		{Pointer: "/id", Schema: "#/definitions/Pet/properties/id", Expected: "integer (int64)", Actual: "abc"},
+		{Pointer: "/photoUrls/1", Schema: "#/definitions/Pet/properties/photoUrls/items", Expected: "string", Actual: float64(1)},
+	}, Mismatches(errors.Wrap(err, "wrapped")))
+	assert.Contains(t, err.Error(), `/id: expected: integer (int64) from schema #/definitions/Pet/properties/id, got: string "abc"`)
+
+	verify, err := NewVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	_, err = rec.WriteString(`{"id":true}`)
+	require.NoError(t, err)
+	err = verify(rec.Result(), httptest.NewRequest("GET", "/v2/user/testuser", nil))
+	assert.Equal(t, []Mismatch{
+		{Pointer: "/id", Schema: "#/definitions/UserImmutable/properties/id", Expected: "integer (int64)", Actual: true},
+	}, Mismatches(err))
+
+	assert.Nil(t, Mismatches(errors.New("unrelated")))
+}
+
+func TestMismatch_String(t *testing.T) {
+	m := Mismatch{Pointer: "/id", Schema: "#/definitions/Pet/properties/id", Expected: "integer (int64)", Actual: "abc"}
+	assert.Equal(t, `/id: expected: integer (int64) from schema #/definitions/Pet/properties/id, got: string "abc"`, m.String())
+
+	m.Actual = nil
+	assert.Equal(t, `/id: expected: integer (int64) from schema #/definitions/Pet/properties/id, got: null`, m.String())
+
+	m.Actual = json.Number("9007199254740993")
+	assert.Equal(t, `/id: expected: integer (int64) from schema #/definitions/Pet/properties/id, got: integer 9007199254740993`, m.String())
+}
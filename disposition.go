@@ -0,0 +1,130 @@
+package revisor
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// attachmentExt is a response extension which declares that the response
+// is a file download, which must have attachment Content-Disposition
+// with a filename, e.g.
+//
+//	'200':
+//	  description: invoice document
+//	  x-attachment: true
+const attachmentExt = "x-attachment"
+
+// CheckContentDisposition enables checks of Content-Disposition header of
+// responses which declare the header or x-attachment extension. The header
+// must be present and valid according to RFC 6266, and attachments must
+// have filename or filename* parameter. Responses of x-attachment must be
+// attachments. Invalid headers are reported as Violation with
+// CodeInvalidContentDisposition code.
+func CheckContentDisposition(a *apiVerifier) {
+	a.opts.checkDisposition = true
+}
+
+func isAttachment(response *spec.Response) bool {
+	attachment, _ := response.Extensions[attachmentExt].(bool)
+	return attachment
+}
+
+// verifyContentDisposition checks Content-Disposition header of the response
+func (a *apiVerifier) verifyContentDisposition(req *http.Request, res *http.Response) error {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	response, err := a.responseByStatus(res.StatusCode, operation)
+	if err != nil {
+		return nil
+	}
+	attachment := isAttachment(response)
+	declared := attachment
+	for name := range response.Headers {
+		declared = declared || http.CanonicalHeaderKey(name) == "Content-Disposition"
+	}
+	if !declared {
+		return nil
+	}
+	header := res.Header.Get("Content-Disposition")
+	if header == "" {
+		return newViolation(CodeInvalidContentDisposition, "Content-Disposition header is required")
+	}
+	typ, params, reason := parseContentDisposition(header)
+	if reason != "" {
+		return newViolation(CodeInvalidContentDisposition, "Content-Disposition is not valid: "+reason+": "+strconv.Quote(header))
+	}
+	if attachment && typ != "attachment" {
+		return newViolation(CodeInvalidContentDisposition, "Content-Disposition of the response must be attachment")
+	}
+	if typ != "attachment" {
+		return nil
+	}
+	if _, ok := params["filename"]; !ok {
+		if _, ok := params["filename*"]; !ok {
+			return newViolation(CodeInvalidContentDisposition, "filename of the attachment is required")
+		}
+	}
+	return nil
+}
+
+// parseContentDisposition parses Content-Disposition header and returns its
+// lowercase type and parameters, or the reason why the header is not valid
+func parseContentDisposition(header string) (string, map[string]string, string) {
+	s := strings.TrimLeft(header, " \t")
+	i := 0
+	for i < len(s) && isTokenChar(s[i]) {
+		i++
+	}
+	if i == 0 {
+		return "", nil, "disposition type is required"
+	}
+	typ := strings.ToLower(s[:i])
+	s = strings.TrimLeft(s[i:], " \t")
+	params := make(map[string]string)
+	for s != "" {
+		if s[0] != ';' {
+			return "", nil, "unexpected " + string(s[0])
+		}
+		param := strings.TrimLeft(s[1:], " \t")
+		name, value, rest, err := parseHeaderParam(param)
+		if err != nil || !strings.Contains(param[:len(param)-len(rest)], "=") {
+			return "", nil, "parameter is not valid"
+		}
+		if _, ok := params[name]; ok {
+			return "", nil, name + " parameter is repeated"
+		}
+		if strings.HasSuffix(name, "*") && !validExtValue(value) {
+			return "", nil, name + " parameter is not a valid extended value"
+		}
+		params[name] = value
+		s = rest
+	}
+	return typ, params, ""
+}
+
+// validExtValue checks if value is RFC 5987 ext-value,
+// e.g. UTF-8'en'%e2%82%ac%20rates
+func validExtValue(value string) bool {
+	parts := strings.SplitN(value, "'", 3)
+	if len(parts) != 3 || parts[0] == "" {
+		return false
+	}
+	for i := 0; i < len(parts[0]); i++ {
+		if !isTokenChar(parts[0][i]) || parts[0][i] == '\'' {
+			return false
+		}
+	}
+	for i := 0; i < len(parts[2]); i++ {
+		if c := parts[2][i]; c != '%' && (!isTokenChar(c) || strings.IndexByte("*'%", c) >= 0) {
+			return false
+		}
+	}
+	_, err := url.PathUnescape(parts[2])
+	return err == nil
+}
@@ -0,0 +1,128 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckContentDisposition(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		disposition string
+		wantErr     string
+	}{
+		{
+			name:        "attachment",
+			path:        "/v1/invoices/1/document",
+			disposition: `attachment; filename="invoice 1.pdf"`,
+		},
+		{
+			name:        "extended filename",
+			path:        "/v1/invoices/1/document",
+			disposition: `Attachment; filename*=UTF-8''%e2%82%ac%20invoice.pdf`,
+		},
+		{
+			name:    "missing header",
+			path:    "/v1/invoices/1/document",
+			wantErr: "Content-Disposition header is required",
+		},
+		{
+			name:        "inline attachment",
+			path:        "/v1/invoices/1/document",
+			disposition: `inline; filename=invoice.pdf`,
+			wantErr:     "Content-Disposition of the response must be attachment",
+		},
+		{
+			name:        "missing filename",
+			path:        "/v1/invoices/1/document",
+			disposition: `attachment; size=10`,
+			wantErr:     "filename of the attachment is required",
+		},
+		{
+			name:        "declared header",
+			path:        "/v1/invoices/1/preview",
+			disposition: `inline`,
+		},
+		{
+			name:        "declared attachment",
+			path:        "/v1/invoices/1/preview",
+			disposition: `attachment`,
+			wantErr:     "filename of the attachment is required",
+		},
+		{
+			name:        "invalid header",
+			path:        "/v1/invoices/1/preview",
+			disposition: `inline filename=invoice.pdf`,
+			wantErr:     `Content-Disposition is not valid: unexpected f: "inline filename=invoice.pdf"`,
+		},
+		{
+			name: "not declared",
+			path: "/v1/invoices/1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"downloads.yaml", CheckContentDisposition)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			if tt.disposition != "" {
+				rec.Header().Set("Content-Disposition", tt.disposition)
+			}
+			err = v.verifier().verifyContentDisposition(httptest.NewRequest("GET", tt.path, nil), rec.Result())
+			wantCode := ""
+			if tt.wantErr != "" {
+				wantCode = CodeInvalidContentDisposition
+			}
+			assertVerifyErr(t, err, tt.wantErr, wantCode)
+		})
+	}
+}
+
+func TestParseContentDisposition(t *testing.T) {
+	tests := []struct {
+		header     string
+		wantType   string
+		wantParams map[string]string
+		wantReason string
+	}{
+		{
+			header:     `inline`,
+			wantType:   "inline",
+			wantParams: map[string]string{},
+		},
+		{
+			header:     `attachment; FileName="a \"b\".txt" ; filename*=utf-8'en'a%20b.txt`,
+			wantType:   "attachment",
+			wantParams: map[string]string{"filename": `a "b".txt`, "filename*": "utf-8'en'a%20b.txt"},
+		},
+		{header: `; filename=a`, wantReason: "disposition type is required"},
+		{header: `attachment; filename`, wantReason: "parameter is not valid"},
+		{header: `attachment; filename="a`, wantReason: "parameter is not valid"},
+		{header: `attachment; filename=a; filename=b`, wantReason: "filename parameter is repeated"},
+		{header: `attachment; filename*=a.txt`, wantReason: "filename* parameter is not a valid extended value"},
+		{header: `attachment; filename*=utf-8''a%2`, wantReason: "filename* parameter is not a valid extended value"},
+		{header: `attachment; filename*=''a.txt`, wantReason: "filename* parameter is not a valid extended value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			typ, params, reason := parseContentDisposition(tt.header)
+			assert.Equal(t, tt.wantReason, reason)
+			assert.Equal(t, tt.wantType, typ)
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestCheckContentDisposition_Warning(t *testing.T) {
+	var warnings []string
+	_, err := New(testdata+"downloads.yaml", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "GET /invoices/{id}/document: x-attachment is not enforced")
+}
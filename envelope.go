@@ -0,0 +1,67 @@
+package revisor
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// envelopeExt is an operation extension which sets JSON pointer of the
+// payload wrapped in an envelope for the operation, overriding WithEnvelope,
+// e.g.
+//
+//	x-envelope: /data
+//
+// Empty pointer disables unwrapping for the operation.
+const envelopeExt = "x-envelope"
+
+// WithEnvelope unwraps request and response bodies wrapped in an envelope,
+// e.g. {"data": ..., "meta": ...}, for APIs which spec documents only the
+// wrapped payload. Payload is found at JSON pointer, e.g. "/data", and
+// validated against schema instead of the whole body. Bodies without
+// payload at pointer are reported as Violation with CodeMissingEnvelope code.
+func WithEnvelope(pointer string) option {
+	return func(a *apiVerifier) {
+		a.opts.envelope = pointer
+	}
+}
+
+// unwrapEnvelope returns payload of decoded body wrapped in an envelope
+// configured globally or for the operation
+func (a *apiVerifier) unwrapEnvelope(req *http.Request, decoded interface{}) (interface{}, error) {
+	pointer := a.opts.envelope
+	if _, operation, err := a.getOperationDef(req); err == nil {
+		if p, ok := operationEnvelope(operation); ok {
+			pointer = p
+		}
+	}
+	if pointer == "" || pointer == "/" {
+		return decoded, nil
+	}
+	tokens, ok := pointerTokens(pointer)
+	if ok {
+		decoded, ok = valueAt(decoded, tokens)
+	}
+	if !ok {
+		return nil, newViolation(CodeMissingEnvelope, "body has no payload at "+pointer)
+	}
+	return decoded, nil
+}
+
+func operationEnvelope(operation *spec.Operation) (string, bool) {
+	pointer, ok := operation.Extensions[envelopeExt].(string)
+	return pointer, ok
+}
+
+// pointerTokens splits JSON pointer into unescaped reference tokens
+func pointerTokens(pointer string) ([]string, bool) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	var tokens []string
+	for _, token := range strings.Split(pointer[1:], "/") {
+		tokens = append(tokens, strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1))
+	}
+	return tokens, true
+}
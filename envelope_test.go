@@ -0,0 +1,128 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []option
+		method   string
+		path     string
+		status   int
+		body     string
+		wantErr  string
+		wantCode string
+	}{
+		{
+			name:   "global",
+			method: "POST", path: "/v1/articles", status: 201,
+			options: []option{WithEnvelope("/data")},
+			body:    `{"data": {"title": "envelopes"}, "meta": {"version": 1}}`,
+		},
+		{
+			name:   "global invalid payload",
+			method: "POST", path: "/v1/articles", status: 201,
+			options: []option{WithEnvelope("/data")},
+			body:    `{"data": {}, "meta": {"version": 1}}`,
+			wantErr: "title in body is required",
+		},
+		{
+			name:   "global missing payload",
+			method: "POST", path: "/v1/articles", status: 201,
+			options:  []option{WithEnvelope("/data")},
+			body:     `{"meta": {"version": 1}}`,
+			wantCode: CodeMissingEnvelope,
+		},
+		{
+			name:   "not configured",
+			method: "POST", path: "/v1/articles", status: 201,
+			body:    `{"data": {"title": "envelopes"}}`,
+			wantErr: "title in body is required",
+		},
+		{
+			name:   "operation",
+			method: "GET", path: "/v1/articles", status: 200,
+			body: `{"data": {"items": [{"title": "envelopes"}]}}`,
+		},
+		{
+			name:   "operation overrides global",
+			method: "GET", path: "/v1/articles", status: 200,
+			options: []option{WithEnvelope("/data")},
+			body:    `{"data": {"items": [{"title": "envelopes"}]}}`,
+		},
+		{
+			name:   "operation invalid payload",
+			method: "GET", path: "/v1/articles", status: 200,
+			body:    `{"data": {"items": [{}]}}`,
+			wantErr: "title in body is required",
+		},
+		{
+			name:   "operation disabled",
+			method: "GET", path: "/v1/health", status: 200,
+			options: []option{WithEnvelope("/data")},
+			body:    `{"status": "ok"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"envelope.yaml", tt.options...)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.method == "POST" {
+				req = httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+				assertVerifyErr(t, v.VerifyRequest(req), tt.wantErr, tt.wantCode)
+			}
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			rec.WriteHeader(tt.status)
+			rec.Body = bytes.NewBufferString(tt.body)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), tt.wantErr, tt.wantCode)
+		})
+	}
+}
+
+func assertVerifyErr(t *testing.T, err error, wantErr, wantCode string) {
+	switch {
+	case wantCode != "":
+		violation, ok := errors.Cause(err).(*Violation)
+		require.True(t, ok, "%v", err)
+		assert.Equal(t, wantCode, violation.Code)
+	case wantErr != "":
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), wantErr)
+	default:
+		assert.NoError(t, err)
+	}
+}
+
+func TestPointerTokens(t *testing.T) {
+	tests := []struct {
+		pointer string
+		want    []string
+		wantOk  bool
+	}{
+		{pointer: "/data/0", want: []string{"data", "0"}, wantOk: true},
+		{pointer: "/a~1b/c~0d", want: []string{"a/b", "c~d"}, wantOk: true},
+		{pointer: "/", want: []string{""}, wantOk: true},
+		{pointer: "data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			got, ok := pointerTokens(tt.pointer)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package revisor
+
+import (
+	"strconv"
+
+	"github.com/go-openapi/spec"
+)
+
+// CheckExamples enables comparison of decoded bodies of responses with
+// examples documented for them, either with examples of the response for
+// the content type or example of its schema. Bodies must have the same
+// structure as examples: the same properties of objects and the same
+// types of values, while values themselves aren't compared. Properties
+// named volatile, e.g. ids and timestamps, aren't compared at all.
+// Divergence is reported as Violation with CodeStaleExample code.
+func CheckExamples(volatile ...string) option {
+	return func(a *apiVerifier) {
+		a.opts.checkExamples = true
+		if a.opts.volatileFields == nil {
+			a.opts.volatileFields = make(map[string]bool)
+		}
+		for _, name := range volatile {
+			a.opts.volatileFields[name] = true
+		}
+	}
+}
+
+// responseExample returns example documented for the response
+// with the content type
+func responseExample(response *spec.Response, contentType string) (interface{}, bool) {
+	if example, ok := response.Examples[contentType]; ok {
+		return example, true
+	}
+	if response.Schema != nil && response.Schema.Example != nil {
+		return response.Schema.Example, true
+	}
+	return nil, false
+}
+
+// verifyExample compares decoded body of the response with its example
+func (a *apiVerifier) verifyExample(response *spec.Response, contentType string, decoded interface{}) error {
+	example, ok := responseExample(response, contentType)
+	if !ok {
+		return nil
+	}
+	if divergence := a.divergence(example, decoded, ""); divergence != "" {
+		return newViolation(CodeStaleExample, "response diverges from documented example: "+divergence)
+	}
+	return nil
+}
+
+// divergence describes the first structural difference
+// of the value from the example
+func (a *apiVerifier) divergence(example, value interface{}, pointer string) string {
+	if exampleType(example) != exampleType(value) {
+		return bodyField(pointer) + " is " + exampleType(value) + " but " + exampleType(example) + " in example"
+	}
+	switch e := example.(type) {
+	case map[string]interface{}:
+		v := value.(map[string]interface{})
+		for _, key := range sortedKeys(e) {
+			if _, ok := v[key]; !ok && !a.opts.volatileFields[key] {
+				return pointer + "/" + escapePointerToken(key) + " is missing"
+			}
+		}
+		for _, key := range sortedKeys(v) {
+			if a.opts.volatileFields[key] {
+				continue
+			}
+			property := pointer + "/" + escapePointerToken(key)
+			exampleValue, ok := e[key]
+			if !ok {
+				return property + " is not in example"
+			}
+			if divergence := a.divergence(exampleValue, v[key], property); divergence != "" {
+				return divergence
+			}
+		}
+	case []interface{}:
+		if len(e) == 0 {
+			return ""
+		}
+		for i, item := range value.([]interface{}) {
+			if divergence := a.divergence(e[0], item, pointer+"/"+strconv.Itoa(i)); divergence != "" {
+				return divergence
+			}
+		}
+	}
+	return ""
+}
+
+// exampleType returns JSON type of the value, which is number
+// for both integer and fractional numbers
+func exampleType(value interface{}) string {
+	if _, ok := value.(float64); ok {
+		return "number"
+	}
+	return jsonType(value)
+}
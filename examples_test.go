@@ -0,0 +1,78 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckExamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		wantErr string
+	}{
+		{
+			name:   "same structure",
+			method: "GET", path: "/v1/orders/2",
+			body: `{"id": 2, "createdAt": "2019-05-05T10:00:00Z", "total": 10, "lines": [{"sku": "B", "quantity": 1}], "note": null}`,
+		},
+		{
+			name:   "volatile fields",
+			method: "GET", path: "/v1/orders/2",
+			body: `{"id": "o-2", "total": 10, "lines": [], "note": null}`,
+		},
+		{
+			name:   "missing field",
+			method: "GET", path: "/v1/orders/2",
+			body:    `{"id": 2, "total": 10, "lines": []}`,
+			wantErr: "response diverges from documented example: /note is missing",
+		},
+		{
+			name:   "undocumented field",
+			method: "GET", path: "/v1/orders/2",
+			body:    `{"id": 2, "total": 10, "lines": [{"sku": "B", "quantity": 1, "gift": true}], "note": null}`,
+			wantErr: "response diverges from documented example: /lines/0/gift is not in example",
+		},
+		{
+			name:   "different type",
+			method: "GET", path: "/v1/orders/2",
+			body:    `{"id": 2, "total": "10.00", "lines": [], "note": null}`,
+			wantErr: "response diverges from documented example: /total is string but number in example",
+		},
+		{
+			name:   "schema example",
+			method: "GET", path: "/v1/orders",
+			body:    `[{"id": 1}, {"id": 2, "status": "new"}]`,
+			wantErr: "response diverges from documented example: /1/status is not in example",
+		},
+		{
+			name:   "no example",
+			method: "POST", path: "/v1/orders",
+			body: `{"anything": true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"examples.yaml", CheckExamples("id", "createdAt"))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			if tt.method == "POST" {
+				rec.WriteHeader(201)
+			}
+			rec.Body = bytes.NewBufferString(tt.body)
+			err = v.VerifyResponse(rec.Result(), httptest.NewRequest(tt.method, tt.path, nil))
+			wantCode := ""
+			if tt.wantErr != "" {
+				wantCode = CodeStaleExample
+			}
+			assertVerifyErr(t, err, tt.wantErr, wantCode)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// FixReport lists response body locations altered by the fixer
+type FixReport struct {
+	// Filled holds JSON pointers of required properties which were missing
+	// and were filled with documented examples or defaults
+	Filled []string
+	// Coerced holds JSON pointers of values converted to the documented type
+	Coerced []string
+}
+
+// Empty reports if nothing was altered in the response
+func (r *FixReport) Empty() bool {
+	return len(r.Filled) == 0 && len(r.Coerced) == 0
+}
+
+// NewResponseFixer returns a function that makes JSON response body conform to
+// OpenAPI definition where it is possible: missing required properties are filled
+// with documented examples or defaults, scalar values are coerced to the documented
+// type. It is meant for test and mocking contexts, e.g. contract test harnesses, where
+// returned report shows what had to be fixed.
+func NewResponseFixer(definitionPath string, options ...option) (func(*http.Response, *http.Request) (*FixReport, error), error) {
+	a, err := newInitializedVerifier(definitionPath, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create fixer function")
+	}
+	return a.fixResponse, nil
+}
+
+// fixResponse fills and coerces response body values according to the schema
+func (a *apiVerifier) fixResponse(res *http.Response, req *http.Request) (*FixReport, error) {
+	if res == nil || req == nil {
+		return nil, ErrNilInput
+	}
+	response, _, err := a.getResponseDef(req, res)
+	if err != nil {
+		return nil, err
+	}
+	report := &FixReport{}
+	if response.Schema == nil {
+		return report, nil
+	}
+	body, err := readResponseBody(res)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fix response")
+	}
+	if len(body) == 0 {
+		return report, nil
+	}
+	decoded, err := jsonNumberDecoder(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	decoded = fixValue(response.Schema, decoded, "", report)
+	if report.Empty() {
+		return report, nil
+	}
+	sort.Strings(report.Filled)
+	sort.Strings(report.Coerced)
+	body, err = json.Marshal(decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode response")
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	if res.Header.Get("Content-Length") != "" {
+		res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	return report, nil
+}
+
+// fixValue returns the value fixed according to the schema
+func fixValue(schema *spec.Schema, value interface{}, pointer string, report *FixReport) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, additional, _ := declaredProperties(schema)
+		for _, name := range requiredProperties(schema) {
+			if _, ok := v[name]; ok {
+				continue
+			}
+			if sample, ok := documentedSample(props[name]); ok {
+				v[name] = sample
+				report.Filled = append(report.Filled, pointer+"/"+escapePointerToken(name))
+			}
+		}
+		for name, prop := range v {
+			propSchema, ok := props[name]
+			if !ok {
+				propSchema = additional
+			}
+			if propSchema != nil {
+				v[name] = fixValue(propSchema, prop, pointer+"/"+escapePointerToken(name), report)
+			}
+		}
+		return v
+	case []interface{}:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i, item := range v {
+				v[i] = fixValue(schema.Items.Schema, item, pointer+"/"+strconv.Itoa(i), report)
+			}
+		}
+		return v
+	}
+	if coerced, ok := coerceScalar(schema, value); ok {
+		report.Coerced = append(report.Coerced, pointer)
+		return coerced
+	}
+	return value
+}
+
+// requiredProperties collects required properties of the schema and its allOf members
+func requiredProperties(schema *spec.Schema) []string {
+	required := append([]string{}, schema.Required...)
+	for i := range schema.AllOf {
+		required = append(required, requiredProperties(&schema.AllOf[i])...)
+	}
+	return required
+}
+
+// documentedSample returns example or default value documented for the schema
+func documentedSample(schema *spec.Schema) (interface{}, bool) {
+	if schema == nil {
+		return nil, false
+	}
+	if schema.Example != nil {
+		return schema.Example, true
+	}
+	if schema.Default != nil {
+		return schema.Default, true
+	}
+	return nil, false
+}
+
+// coerceScalar converts scalar value to the type documented by the schema,
+// second return parameter reports if the value was converted
+func coerceScalar(schema *spec.Schema, value interface{}) (interface{}, bool) {
+	if len(schema.Type) != 1 {
+		return nil, false
+	}
+	switch v := value.(type) {
+	case string:
+		switch schema.Type[0] {
+		case "integer":
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i, true
+			}
+		case "number":
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, true
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, true
+			}
+		}
+	case json.Number:
+		if schema.Type[0] == "string" {
+			return v.String(), true
+		}
+	case bool:
+		if schema.Type[0] == "string" {
+			return strconv.FormatBool(v), true
+		}
+	}
+	return nil, false
+}
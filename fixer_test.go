@@ -0,0 +1,69 @@
+package revisor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseFixer(t *testing.T) {
+	fixer, err := NewResponseFixer(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	verifier, err := NewVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	respond := func(payload string) *http.Response {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		rec.WriteHeader(http.StatusOK)
+		_, err := rec.WriteString(payload)
+		require.NoError(t, err)
+		return rec.Result()
+	}
+
+	t.Run("fills and coerces values", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v2/pet/1", nil)
+		res := respond(`{"id":"12","photoUrls":[],"tags":[{"id":"1","name":2}]}`)
+		report, err := fixer(res, req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/name"}, report.Filled)
+		assert.Equal(t, []string{"/id", "/tags/0/id", "/tags/0/name"}, report.Coerced)
+
+		body, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":12,"name":"doggie","photoUrls":[],"tags":[{"id":1,"name":"2"}]}`, string(body))
+
+		res = respond(string(body))
+		assert.NoError(t, verifier(res, req))
+	})
+
+	t.Run("keeps numbers as they are", func(t *testing.T) {
+		res := respond(`{"id":9007199254740993,"photoUrls":[],"tags":[{"id":1,"name":2.50}]}`)
+		report, err := fixer(res, httptest.NewRequest("GET", "/v2/pet/1", nil))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/tags/0/name"}, report.Coerced)
+
+		body, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":9007199254740993,"name":"doggie","photoUrls":[],"tags":[{"id":1,"name":"2.50"}]}`, string(body))
+	})
+
+	t.Run("valid response is not altered", func(t *testing.T) {
+		payload, err := json.Marshal(map[string]interface{}{"name": "cat", "photoUrls": []string{}})
+		require.NoError(t, err)
+		res := respond(string(payload))
+		report, err := fixer(res, httptest.NewRequest("GET", "/v2/pet/1", nil))
+		require.NoError(t, err)
+		assert.True(t, report.Empty())
+	})
+
+	t.Run("response definition is not found", func(t *testing.T) {
+		_, err := fixer(respond("{}"), httptest.NewRequest("GET", "/v2/not-found", nil))
+		assert.Regexp(t, "no path template matches current request", err)
+	})
+}
@@ -0,0 +1,33 @@
+package revisor
+
+import (
+	"net/http"
+)
+
+// verifyFraming checks request message framing for inconsistencies which
+// are commonly exploited for request smuggling.
+// net/http drops Content-Length and Transfer-Encoding headers of requests
+// it reads and resolves the framing into req.ContentLength and
+// req.TransferEncoding, so ambiguous framing is only detectable on raw
+// captured exchanges, while bodies sent to body-less operations are
+// reported for server received requests as well
+func (a *apiVerifier) verifyFraming(req *http.Request) error {
+	chunked := len(req.TransferEncoding) != 0 || req.Header.Get("Transfer-Encoding") != ""
+	if chunked && (req.Header.Get("Content-Length") != "" || req.ContentLength > 0) {
+		return newViolation(CodeAmbiguousFraming, "both Content-Length and Transfer-Encoding are set")
+	}
+	hasBody := chunked || (req.ContentLength != 0 && req.Body != nil && req.Body != http.NoBody)
+	if !hasBody {
+		return nil
+	}
+	pathDef, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	for _, param := range operationParams(pathDef, operation) {
+		if param.In == "body" || param.In == "formData" {
+			return nil
+		}
+	}
+	return newViolation(CodeUnexpectedBody, "body is sent to operation which doesn't declare one")
+}
@@ -0,0 +1,113 @@
+package revisor
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVerifierV2_VerifyFraming(t *testing.T) {
+
+	a, err := newAPIVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	a.setOptions(CheckFraming)
+	err = a.initMapper(a.doc.Spec().BasePath)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   []byte
+		header map[string]string
+		code   string
+	}{
+		{
+			"body-less request",
+			"GET",
+			"/v2/user/testuser",
+			nil,
+			nil,
+			"",
+		},
+		{
+			"both content-length and transfer-encoding",
+			"PUT",
+			"/v2/user/testuser",
+			[]byte("{}"),
+			map[string]string{"Content-Length": "2", "Transfer-Encoding": "chunked"},
+			CodeAmbiguousFraming,
+		},
+		{
+			"body on body-less operation",
+			"GET",
+			"/v2/user/testuser",
+			[]byte("{}"),
+			nil,
+			CodeUnexpectedBody,
+		},
+		{
+			"body on form operation",
+			"POST",
+			"/v2/pet/1",
+			[]byte("name=cat"),
+			nil,
+			"",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(test.method, test.path, bytes.NewReader(test.body))
+			require.NoError(t, err)
+			if test.body == nil {
+				req.Body = http.NoBody
+				req.ContentLength = 0
+			}
+			for k, v := range test.header {
+				req.Header.Set(k, v)
+			}
+			err = a.verifyFraming(req)
+			if test.code == "" {
+				assert.NoError(t, err)
+				return
+			}
+			violation, ok := errors.Cause(err).(*Violation)
+			require.True(t, ok)
+			assert.Equal(t, test.code, violation.Code)
+		})
+	}
+
+	t.Run("violation is reported by request verifier", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/v2/user/testuser", bytes.NewReader([]byte("{}")))
+		require.NoError(t, err)
+		err = a.verifyRequest(req)
+		assert.Regexp(t, CodeUnexpectedBody, err)
+	})
+
+	t.Run("chunked body of server received request", func(t *testing.T) {
+		raw := "GET /v2/user/testuser HTTP/1.1\r\nHost: petstore.swagger.io\r\n" +
+			"Transfer-Encoding: chunked\r\n\r\n2\r\n{}\r\n0\r\n\r\n"
+		req, err := http.ReadRequest(bufio.NewReader(strings.NewReader(raw)))
+		require.NoError(t, err)
+		err = a.verifyFraming(req)
+		violation, ok := errors.Cause(err).(*Violation)
+		require.True(t, ok)
+		assert.Equal(t, CodeUnexpectedBody, violation.Code)
+	})
+
+	t.Run("content length with transfer encoding", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", bytes.NewReader([]byte("{}")))
+		require.NoError(t, err)
+		req.TransferEncoding = []string{"chunked"}
+		err = a.verifyFraming(req)
+		violation, ok := errors.Cause(err).(*Violation)
+		require.True(t, ok)
+		assert.Equal(t, CodeAmbiguousFraming, violation.Code)
+	})
+}
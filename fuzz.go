@@ -0,0 +1,93 @@
+//go:build go1.18
+// +build go1.18
+
+package revisor
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fuzzCorpusSize is a number of seeds added to the corpus of every mutation
+const fuzzCorpusSize = 8
+
+// FuzzOperation fuzzes handler with requests of the operation identified by
+// operationID. Fuzzing inputs are a generator seed and a mutation: the seed
+// produces parameters and a schema-valid body, the mutation picks one of the
+// negative cases of the body or keeps it valid, so that handler receives only
+// structurally plausible requests. Fuzzing fails if handler responds with
+// a server error, accepts invalid request or responds with a response which
+// doesn't conform to the definition.
+//
+//	func FuzzAddPet(f *testing.F) {
+//		v, _ := revisor.New("./petstore.yaml")
+//		revisor.FuzzOperation(f, v, "addPet", newServer())
+//	}
+func FuzzOperation(f *testing.F, v *Verifier, operationID string, handler http.Handler) {
+	if _, err := v.GenerateParams(NewGenerator(0), operationID); err != nil {
+		f.Fatal(err)
+	}
+	_, hasBody := v.SchemaAt(operationID, 0, "")
+	for seed := int64(0); seed < fuzzCorpusSize; seed++ {
+		f.Add(seed, uint8(0))
+		if hasBody == nil {
+			f.Add(seed, uint8(seed+1))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64, mutation uint8) {
+		req, err := v.fuzzRequest(NewGenerator(seed), operationID, int(mutation))
+		if err != nil {
+			t.Skip(err)
+		}
+		body, err := readRequestBody(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reqErr := v.VerifyRequest(req)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		res := rec.Result()
+
+		switch {
+		case res.StatusCode >= http.StatusInternalServerError:
+			t.Errorf("%s %s: server error: %s", req.Method, req.URL, res.Status)
+		case reqErr != nil && res.StatusCode < http.StatusBadRequest:
+			t.Errorf("%s %s: invalid request is accepted: %s: %v", req.Method, req.URL, res.Status, reqErr)
+		case reqErr != nil:
+			if err := v.VerifyResponse(res, req); err != nil {
+				t.Errorf("%s %s: response validation failed: %v", req.Method, req.URL, err)
+			}
+		default:
+			if err := v.Verify(res, req); err != nil {
+				t.Errorf("%s %s: %v", req.Method, req.URL, err)
+			}
+		}
+	})
+}
+
+// fuzzRequest builds request of the operation with generated parameters and body,
+// mutation selects negative case of the body applied to it, 0 keeps body valid
+func (v *Verifier) fuzzRequest(g *Generator, operationID string, mutation int) (*http.Request, error) {
+	params, err := v.GenerateParams(g, operationID)
+	if err != nil {
+		return nil, err
+	}
+	var body interface{}
+	if schema, err := v.SchemaAt(operationID, 0, ""); err == nil {
+		body, err = copyValue(g.Value(schema))
+		if err != nil {
+			return nil, err
+		}
+		mutations := negativeMutations(schema, body, nil, 0)
+		if i := mutation % (len(mutations) + 1); i != 0 {
+			m := mutations[i-1]
+			body = replaceAt(body, m.path, m.value, m.remove)
+		}
+	}
+	return v.BuildRequest(operationID, params, body)
+}
@@ -0,0 +1,55 @@
+//go:build go1.18
+// +build go1.18
+
+package revisor
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderHandler rejects invalid orders and echoes valid ones
+func orderHandler(v *Verifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := v.VerifyRequest(r); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": err.Error()})
+			return
+		}
+		_, _ = io.Copy(w, r.Body)
+	}
+}
+
+func FuzzOperation_PlaceOrder(f *testing.F) {
+	v, err := New(testdata + "fuzz.yaml")
+	require.NoError(f, err)
+	FuzzOperation(f, v, "placeOrder", orderHandler(v))
+}
+
+func TestVerifier_fuzzRequest(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	req, err := v.fuzzRequest(NewGenerator(1), "addPet", 0)
+	require.NoError(t, err)
+	assert.NoError(t, v.VerifyRequest(req))
+
+	for mutation := 1; mutation < 10; mutation++ {
+		req, err := v.fuzzRequest(NewGenerator(1), "addPet", mutation)
+		require.NoError(t, err)
+		assert.Error(t, v.VerifyRequest(req), "mutation %d", mutation)
+	}
+
+	req, err = v.fuzzRequest(NewGenerator(1), "getPetById", 3)
+	require.NoError(t, err)
+	assert.NoError(t, v.VerifyRequest(req))
+
+	_, err = v.fuzzRequest(NewGenerator(1), "unknown", 0)
+	assert.EqualError(t, err, "operation is not defined: unknown")
+}
@@ -0,0 +1,139 @@
+package revisor
+
+import (
+	"bytes"
+	"go/format"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// contractTestEntry is a single table entry of generated contract tests
+type contractTestEntry struct {
+	Operation string
+	Method    string
+	Path      string
+	Status    int
+}
+
+var contractTestTemplate = template.Must(template.New("contract").Parse(`// Code generated by revisor from {{ printf "%q" .DefinitionPath }}.
+// It bootstraps contract tests of the API, one entry per operation and
+// documented status code. Adjust paths and bodies to reach each status.
+
+package {{ .Package }}
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/krnkl/revisor/revisortest"
+)
+
+// contractHandler is the handler under test, set it before tests run, e.g. in TestMain
+var contractHandler http.Handler
+
+func TestContract(t *testing.T) {
+	if contractHandler == nil {
+		t.Skip("contractHandler is not set")
+	}
+	revisortest.RunContract(t, {{ printf "%q" .DefinitionPath }}, contractHandler, []revisortest.ContractCase{
+{{- range .Entries }}
+		{Operation: {{ printf "%q" .Operation }}, Method: {{ printf "%q" .Method }}, Path: {{ printf "%q" .Path }}, Status: {{ .Status }}},
+{{- end }}
+	})
+}
+`))
+
+// GenerateContractTests writes source of a _test.go file of the package with
+// table-driven contract tests, one table entry per operation and documented
+// status code run with revisortest.RunContract, which bootstraps contract
+// test suite of existing service
+func GenerateContractTests(definitionPath, packageName string, w io.Writer) error {
+	a, err := newAPIVerifier(definitionPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate contract tests")
+	}
+	swagger := a.doc.Spec()
+
+	var entries []contractTestEntry
+	for _, path := range sortedPaths(swagger) {
+		pathDef := swagger.Paths.Paths[path]
+		for _, method := range httpMethods {
+			operation := pathOperation(method, &pathDef)
+			if operation == nil || operation.Responses == nil {
+				continue
+			}
+			name := operation.ID
+			if name == "" {
+				name = method + " " + path
+			}
+			samplePath := swagger.BasePath + samplePathValues(path, operationParams(&pathDef, operation))
+			codes := make([]int, 0, len(operation.Responses.StatusCodeResponses))
+			for code := range operation.Responses.StatusCodeResponses {
+				codes = append(codes, code)
+			}
+			sort.Ints(codes)
+			for _, code := range codes {
+				entries = append(entries, contractTestEntry{name, method, samplePath, code})
+			}
+		}
+	}
+
+	src := &bytes.Buffer{}
+	err = contractTestTemplate.Execute(src, map[string]interface{}{
+		"DefinitionPath": definitionPath,
+		"Package":        packageName,
+		"Entries":        entries,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to generate contract tests")
+	}
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "failed to format contract tests")
+	}
+	_, err = w.Write(formatted)
+	return errors.Wrap(err, "failed to write contract tests")
+}
+
+// samplePathValues substitutes path template variables with
+// default values of corresponding parameters or type-specific samples
+func samplePathValues(path string, params []spec.Parameter) string {
+	for _, param := range params {
+		if param.In != "path" {
+			continue
+		}
+		value := "example"
+		switch {
+		case param.Default != nil:
+			value = url.PathEscape(toString(param.Default))
+		case param.Type == "integer" || param.Type == "number":
+			value = "1"
+			if param.Minimum != nil {
+				value = strconv.FormatFloat(*param.Minimum, 'f', -1, 64)
+			}
+		case param.Type == "boolean":
+			value = "true"
+		}
+		path = strings.Replace(path, "{"+param.Name+"}", value, -1)
+	}
+	return path
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
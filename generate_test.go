@@ -0,0 +1,43 @@
+package revisor
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateContractTests(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := GenerateContractTests(testdata+sampleV2YAML, "petstore", out)
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "contract_test.go", out.Bytes(), parser.AllErrors)
+	require.NoError(t, err)
+
+	src := out.String()
+	assert.Contains(t, src, "package petstore")
+	assert.Contains(t, src, "revisortest.RunContract(t, ")
+	assert.Contains(t, src, `{Operation: "getPetById", Method: "GET", Path: "/v2/pet/1", Status: 200}`)
+	assert.Contains(t, src, `{Operation: "getPetById", Method: "GET", Path: "/v2/pet/1", Status: 404}`)
+	assert.Contains(t, src, `{Operation: "getUserByName", Method: "GET", Path: "/v2/user/example", Status: 400}`)
+	assert.NotContains(t, src, `"createUser"`)
+
+	t.Run("definition not found", func(t *testing.T) {
+		err := GenerateContractTests("./non-existing-file.yaml", "petstore", &bytes.Buffer{})
+		assert.Regexp(t, "failed to generate contract tests", err)
+	})
+}
+
+func TestSamplePathValues(t *testing.T) {
+	assert.Equal(t, "/store/order/1", samplePathValues("/store/order/{orderId}", []spec.Parameter{
+		*spec.PathParam("orderId").Typed("integer", "int64").WithMinimum(1, false),
+	}))
+	assert.Equal(t, "/user/admin", samplePathValues("/user/{username}", []spec.Parameter{
+		*spec.PathParam("username").Typed("string", "").WithDefault("admin"),
+	}))
+}
@@ -0,0 +1,375 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxGeneratedRepeat limits repetitions of unbounded regular expression operators
+	maxGeneratedRepeat = 5
+	// maxGeneratedItems limits length of arrays without maxItems constraint
+	maxGeneratedItems = 3
+	// maxGeneratedLength limits length of strings without maxLength constraint
+	maxGeneratedLength = 12
+	// maxGeneratedAttempts limits attempts to generate string matching
+	// both pattern and length constraints
+	maxGeneratedAttempts = 16
+)
+
+const alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// Generator produces random values satisfying schemas. Values respect types,
+// formats, enums, patterns, length and range constraints. Generator created
+// with the same seed produces the same values, so that generated data is
+// reproducible. Generator is not safe for concurrent use.
+type Generator struct {
+	rand *rand.Rand
+}
+
+// NewGenerator returns Generator seeded with seed
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// NewGeneratorFromSource returns Generator drawing random numbers from src
+func NewGeneratorFromSource(src rand.Source) *Generator {
+	return &Generator{rand: rand.New(src)}
+}
+
+// GenerateBody returns random body of the response with status of the operation
+// identified by operationID, request body is generated if status is 0
+func (v *Verifier) GenerateBody(g *Generator, operationID string, status int) (interface{}, error) {
+	schema, err := v.SchemaAt(operationID, status, "")
+	if err != nil {
+		return nil, err
+	}
+	return g.Value(schema), nil
+}
+
+// GenerateParams returns random values of non-body parameters of the operation
+// identified by operationID, keyed by parameter names, so that they can be passed
+// to BuildRequest. Required parameters are always generated, optional ones randomly.
+func (v *Verifier) GenerateParams(g *Generator, operationID string) (map[string]interface{}, error) {
+	_, _, operation, params := v.verifier().operationByID(operationID)
+	if operation == nil {
+		return nil, errors.New("operation is not defined: " + operationID)
+	}
+	values := make(map[string]interface{})
+	for _, param := range params {
+		// operation parameters override path item ones
+		if _, ok := values[param.Name]; ok || param.In == "body" || param.Type == "file" {
+			continue
+		}
+		if !param.Required && g.rand.Intn(2) == 0 {
+			continue
+		}
+		values[param.Name] = g.Value(parameterSchema(&param.SimpleSchema, &param.CommonValidations))
+	}
+	return values, nil
+}
+
+// Value returns random value satisfying the schema
+func (g *Generator) Value(schema *spec.Schema) interface{} {
+	return g.value(schema, 0)
+}
+
+func (g *Generator) value(schema *spec.Schema, depth int) interface{} {
+	if len(schema.Enum) != 0 {
+		return schema.Enum[g.rand.Intn(len(schema.Enum))]
+	}
+	switch {
+	case schema.Type.Contains("object") || len(schema.Properties) != 0 || len(schema.AllOf) != 0:
+		return g.object(schema, depth)
+	case schema.Type.Contains("array"):
+		return g.array(schema, depth)
+	case schema.Type.Contains("integer"):
+		return g.integer(schema)
+	case schema.Type.Contains("number"):
+		return g.number(schema)
+	case schema.Type.Contains("boolean"):
+		return g.rand.Intn(2) == 0
+	case schema.Type.Contains("string"):
+		return g.string(schema)
+	}
+	if sample, ok := documentedSample(schema); ok {
+		return sample
+	}
+	return nil
+}
+
+// object generates required properties and randomly picked optional ones,
+// optional properties are omitted below maximum depth
+func (g *Generator) object(schema *spec.Schema, depth int) map[string]interface{} {
+	object := make(map[string]interface{})
+	props, _, _ := declaredProperties(schema)
+	required := make(map[string]bool)
+	for _, name := range requiredProperties(schema) {
+		required[name] = true
+	}
+	for _, name := range sortedSchemaNames(props) {
+		if !required[name] && (depth >= maxSampleDepth || g.rand.Intn(2) == 0) {
+			continue
+		}
+		object[name] = g.value(props[name], depth+1)
+	}
+	return object
+}
+
+func (g *Generator) array(schema *spec.Schema, depth int) []interface{} {
+	items := []interface{}{}
+	if schema.Items == nil || schema.Items.Schema == nil {
+		return items
+	}
+	min, max := int64(0), int64(maxGeneratedItems)
+	if schema.MinItems != nil {
+		min = *schema.MinItems
+	}
+	if schema.MaxItems != nil {
+		max = *schema.MaxItems
+	} else if max < min {
+		max = min
+	}
+	count := min
+	if max > min {
+		count += g.rand.Int63n(max - min + 1)
+	}
+	seen := make(map[string]bool)
+	for attempt := 0; int64(len(items)) < count && attempt < int(count)*maxGeneratedAttempts; attempt++ {
+		item := g.value(schema.Items.Schema, depth+1)
+		if schema.UniqueItems {
+			key, _ := json.Marshal(item)
+			if seen[string(key)] {
+				continue
+			}
+			seen[string(key)] = true
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// bounds returns inclusive range of the number schema, defaults
+// to the range of the format or to [-1000, 1000]
+func bounds(schema *spec.Schema, step float64) (float64, float64) {
+	min, max := -1000.0, 1000.0
+	if schema.Format == "int32" {
+		min, max = math.MinInt32, math.MaxInt32
+	}
+	if schema.Minimum != nil {
+		min = *schema.Minimum
+		if schema.ExclusiveMinimum {
+			min += step
+		}
+		if schema.Maximum == nil && max < min {
+			max = min + 1000
+		}
+	}
+	if schema.Maximum != nil {
+		max = *schema.Maximum
+		if schema.ExclusiveMaximum {
+			max -= step
+		}
+		if schema.Minimum == nil && min > max {
+			min = max - 1000
+		}
+	}
+	return min, max
+}
+
+func (g *Generator) integer(schema *spec.Schema) float64 {
+	min, max := bounds(schema, 1)
+	min, max = math.Ceil(min), math.Floor(max)
+	if schema.MultipleOf != nil && *schema.MultipleOf >= 1 {
+		step := *schema.MultipleOf
+		low, high := math.Ceil(min/step), math.Floor(max/step)
+		if high < low {
+			return min
+		}
+		return (low + float64(g.rand.Int63n(int64(high-low)+1))) * step
+	}
+	if max <= min {
+		return min
+	}
+	return min + float64(g.rand.Int63n(int64(max-min)+1))
+}
+
+func (g *Generator) number(schema *spec.Schema) float64 {
+	min, max := bounds(schema, 0.001)
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		step := *schema.MultipleOf
+		low, high := math.Ceil(min/step), math.Floor(max/step)
+		if high < low {
+			return min
+		}
+		return (low + float64(g.rand.Int63n(int64(high-low)+1))) * step
+	}
+	if max <= min {
+		return min
+	}
+	return min + g.rand.Float64()*(max-min)
+}
+
+func (g *Generator) string(schema *spec.Schema) string {
+	if s, ok := g.formatted(schema.Format); ok {
+		return s
+	}
+	min, max := int64(0), int64(maxGeneratedLength)
+	if schema.MinLength != nil {
+		min = *schema.MinLength
+	}
+	if schema.MaxLength != nil {
+		max = *schema.MaxLength
+	} else if max < min {
+		max = min + maxGeneratedLength
+	}
+	if schema.Pattern != "" {
+		for attempt := 0; attempt < maxGeneratedAttempts; attempt++ {
+			s, err := g.matching(schema.Pattern)
+			if err != nil {
+				break
+			}
+			if n := int64(len([]rune(s))); n >= min && n <= max {
+				return s
+			}
+		}
+	}
+	length := min
+	if max > min {
+		length += g.rand.Int63n(max - min + 1)
+	}
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = alphanumeric[g.rand.Intn(len(alphanumeric))]
+	}
+	return string(b)
+}
+
+// formatted returns random string of the format if the format is known
+func (g *Generator) formatted(format string) (string, bool) {
+	switch format {
+	case "date":
+		return g.time().Format("2006-01-02"), true
+	case "date-time":
+		return g.time().Format(time.RFC3339), true
+	case "email":
+		return g.word(8) + "@example.com", true
+	case "hostname":
+		return g.word(8) + ".example.com", true
+	case "uri", "url":
+		return "http://example.com/" + g.word(8), true
+	case "ipv4":
+		return fmt.Sprintf("%d.%d.%d.%d", g.rand.Intn(256), g.rand.Intn(256), g.rand.Intn(256), g.rand.Intn(256)), true
+	case "ipv6":
+		groups := make([]string, 8)
+		for i := range groups {
+			groups[i] = fmt.Sprintf("%x", g.rand.Intn(1<<16))
+		}
+		return strings.Join(groups, ":"), true
+	case "uuid":
+		b := make([]byte, 16)
+		g.rand.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), true
+	case "byte":
+		b := make([]byte, 1+g.rand.Intn(maxGeneratedLength))
+		g.rand.Read(b)
+		return base64.StdEncoding.EncodeToString(b), true
+	}
+	return "", false
+}
+
+func (g *Generator) time() time.Time {
+	return time.Unix(g.rand.Int63n(4102444800), 0).UTC()
+}
+
+func (g *Generator) word(length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = alphanumeric[g.rand.Intn(26)]
+	}
+	return string(b)
+}
+
+// matching returns random string matching regular expression pattern
+func (g *Generator) matching(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse pattern")
+	}
+	buf := &bytes.Buffer{}
+	g.regexp(re.Simplify(), buf)
+	return buf.String(), nil
+}
+
+func (g *Generator) regexp(re *syntax.Regexp, buf *bytes.Buffer) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			buf.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		if len(re.Rune) < 2 {
+			return
+		}
+		// pairs of inclusive ranges are picked with equal probability
+		i := g.rand.Intn(len(re.Rune)/2) * 2
+		low, high := re.Rune[i], re.Rune[i+1]
+		buf.WriteRune(low + rune(g.rand.Intn(int(high-low)+1)))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		buf.WriteByte(alphanumeric[g.rand.Intn(len(alphanumeric))])
+	case syntax.OpCapture:
+		g.regexp(re.Sub[0], buf)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regexp(sub, buf)
+		}
+	case syntax.OpAlternate:
+		g.regexp(re.Sub[g.rand.Intn(len(re.Sub))], buf)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, -1
+		case syntax.OpPlus:
+			min, max = 1, -1
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+		if max == -1 {
+			max = min + maxGeneratedRepeat
+		}
+		for i := min + g.rand.Intn(max-min+1); i > 0; i-- {
+			g.regexp(re.Sub[0], buf)
+		}
+	}
+}
+
+// parameterSchema returns schema of the simple schema of parameter or items
+func parameterSchema(simple *spec.SimpleSchema, validations *spec.CommonValidations) *spec.Schema {
+	schema := &spec.Schema{}
+	if simple.Type != "" {
+		schema.Type = spec.StringOrArray{simple.Type}
+	}
+	schema.Format = simple.Format
+	schema.Maximum, schema.ExclusiveMaximum = validations.Maximum, validations.ExclusiveMaximum
+	schema.Minimum, schema.ExclusiveMinimum = validations.Minimum, validations.ExclusiveMinimum
+	schema.MaxLength, schema.MinLength, schema.Pattern = validations.MaxLength, validations.MinLength, validations.Pattern
+	schema.MaxItems, schema.MinItems, schema.UniqueItems = validations.MaxItems, validations.MinItems, validations.UniqueItems
+	schema.MultipleOf, schema.Enum = validations.MultipleOf, validations.Enum
+	if simple.Items != nil {
+		schema.Items = &spec.SchemaOrArray{Schema: parameterSchema(&simple.Items.SimpleSchema, &simple.Items.CommonValidations)}
+	}
+	return schema
+}
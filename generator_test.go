@@ -0,0 +1,117 @@
+package revisor
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_Value(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "enum", schema: `{"type": "string", "enum": ["a", "b", "c"]}`},
+		{name: "integer range", schema: `{"type": "integer", "minimum": 5, "maximum": 10, "exclusiveMaximum": true}`},
+		{name: "integer multiple", schema: `{"type": "integer", "minimum": 1, "maximum": 100, "multipleOf": 7}`},
+		{name: "int32", schema: `{"type": "integer", "format": "int32"}`},
+		{name: "number range", schema: `{"type": "number", "minimum": 0.5, "maximum": 1.5}`},
+		{name: "string length", schema: `{"type": "string", "minLength": 3, "maxLength": 5}`},
+		{name: "pattern", schema: `{"type": "string", "pattern": "^[A-Z]{2}-\\d{3,5}(x|yz)?$"}`},
+		{name: "pattern with length", schema: `{"type": "string", "pattern": "^a+$", "minLength": 2, "maxLength": 4}`},
+		{name: "formats", schema: `{"type": "object", "required": ["date", "time", "email", "uuid", "ipv4", "ipv6", "uri", "byte"],
+			"properties": {
+				"date": {"type": "string", "format": "date"},
+				"time": {"type": "string", "format": "date-time"},
+				"email": {"type": "string", "format": "email"},
+				"uuid": {"type": "string", "format": "uuid"},
+				"ipv4": {"type": "string", "format": "ipv4"},
+				"ipv6": {"type": "string", "format": "ipv6"},
+				"uri": {"type": "string", "format": "uri"},
+				"byte": {"type": "string", "format": "byte"}}}`},
+		{name: "unique array", schema: `{"type": "array", "minItems": 2, "maxItems": 4, "uniqueItems": true,
+			"items": {"type": "integer", "minimum": 0, "maximum": 5}}`},
+		{name: "allOf", schema: `{"allOf": [
+			{"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}},
+			{"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "minLength": 1}}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &spec.Schema{}
+			require.NoError(t, json.Unmarshal([]byte(tt.schema), schema))
+			g := NewGenerator(1)
+			for i := 0; i < 100; i++ {
+				value := g.Value(schema)
+				// round trip, so that numbers are typed as in decoded bodies
+				raw, err := json.Marshal(value)
+				require.NoError(t, err)
+				var decoded interface{}
+				require.NoError(t, json.Unmarshal(raw, &decoded))
+				assert.NoError(t, validate.AgainstSchema(schema, decoded, strfmt.Default), string(raw))
+			}
+		})
+	}
+}
+
+func TestGenerator_Reproducible(t *testing.T) {
+	schema := &spec.Schema{}
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "array", "items": {"type": "object",
+		"properties": {"id": {"type": "integer"}, "code": {"type": "string", "pattern": "^[a-f0-9]{8}$"}}}}`), schema))
+
+	first, second := NewGenerator(42), NewGenerator(42)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first.Value(schema), second.Value(schema))
+	}
+	assert.NotEqual(t, NewGenerator(1).Value(schema), NewGenerator(2).Value(schema))
+}
+
+func TestGenerator_Matching(t *testing.T) {
+	g := NewGenerator(7)
+	for _, pattern := range []string{`^\w+@\w+\.com$`, `[0-9a-f]{4}`, `^(GET|POST|PUT)$`, `^.{3}$`} {
+		re := regexp.MustCompile(pattern)
+		for i := 0; i < 50; i++ {
+			s, err := g.matching(pattern)
+			require.NoError(t, err)
+			assert.Regexp(t, re, s)
+		}
+	}
+
+	_, err := g.matching("(")
+	assert.Error(t, err)
+}
+
+func TestVerifier_GenerateBody(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	body, err := v.GenerateBody(NewGenerator(3), "getPetById", 200)
+	require.NoError(t, err)
+	pet, ok := body.(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, pet, "name")
+	assert.Contains(t, pet, "photoUrls")
+
+	_, err = v.GenerateBody(NewGenerator(3), "unknown", 200)
+	assert.EqualError(t, err, "operation is not defined: unknown")
+}
+
+func TestVerifier_GenerateParams(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	params, err := v.GenerateParams(NewGenerator(5), "getPetById")
+	require.NoError(t, err)
+	require.Contains(t, params, "petId")
+	req, err := v.BuildRequest("getPetById", params, nil)
+	require.NoError(t, err)
+	assert.NoError(t, v.VerifyRequest(req))
+
+	_, err = v.GenerateParams(NewGenerator(5), "unknown")
+	assert.EqualError(t, err, "operation is not defined: unknown")
+}
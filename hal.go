@@ -0,0 +1,125 @@
+package revisor
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// halContentType is the media type of HAL documents
+const halContentType = "application/hal+json"
+
+// WithHALLinks enables validation of hypermedia links of HAL documents, i.e.
+// responses of application/hal+json content type, which is then accepted
+// wherever application/json is. Links of the document and its embedded
+// resources are checked to have href and valid templated flag in addition
+// to the declared schema, as links are rarely fully described by it.
+// Invalid links are reported as Violation with CodeInvalidLinks code.
+func WithHALLinks() option {
+	return func(a *apiVerifier) {
+		a.opts.hal = true
+		a.opts.stages = append(a.opts.stages, Stage{
+			Name: "hal",
+			Decoded: func(ctx *StageContext, body interface{}) (interface{}, error) {
+				if ctx.Response == nil || !isHAL(ctx.Response.Header.Get("Content-Type")) {
+					return body, nil
+				}
+				return body, validateHALResource(body, "")
+			},
+		})
+	}
+}
+
+// isHAL checks if content type is HAL media type
+func isHAL(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == halContentType
+}
+
+// validateHALResource checks links of the resource and its embedded resources,
+// path is JSON pointer of the resource in the document
+func validateHALResource(resource interface{}, path string) error {
+	object, ok := resource.(map[string]interface{})
+	if !ok {
+		return newViolation(CodeInvalidLinks, "resource "+halPath(path)+" must be an object")
+	}
+	if links, ok := object["_links"]; ok {
+		links, ok := links.(map[string]interface{})
+		if !ok {
+			return newViolation(CodeInvalidLinks, halPath(path+"/_links")+" must be an object")
+		}
+		for _, rel := range sortedKeys(links) {
+			err := validateHALLinks(rel, links[rel], path+"/_links/"+escapePointerToken(rel))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if embedded, ok := object["_embedded"]; ok {
+		embedded, ok := embedded.(map[string]interface{})
+		if !ok {
+			return newViolation(CodeInvalidLinks, halPath(path+"/_embedded")+" must be an object")
+		}
+		for _, rel := range sortedKeys(embedded) {
+			relPath := path + "/_embedded/" + escapePointerToken(rel)
+			resources, isArray := embedded[rel].([]interface{})
+			if !isArray {
+				resources = []interface{}{embedded[rel]}
+			}
+			for i, resource := range resources {
+				resourcePath := relPath
+				if isArray {
+					resourcePath += "/" + strconv.Itoa(i)
+				}
+				if err := validateHALResource(resource, resourcePath); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateHALLinks checks link object or array of link objects of the relation
+func validateHALLinks(rel string, value interface{}, path string) error {
+	links, isArray := value.([]interface{})
+	if !isArray {
+		links = []interface{}{value}
+	}
+	for i, link := range links {
+		linkPath := path
+		if isArray {
+			linkPath += "/" + strconv.Itoa(i)
+		}
+		object, ok := link.(map[string]interface{})
+		if !ok {
+			return newViolation(CodeInvalidLinks, "link "+halPath(linkPath)+" must be an object")
+		}
+		href, ok := object["href"].(string)
+		if !ok || href == "" {
+			return newViolation(CodeInvalidLinks, "link "+halPath(linkPath)+" must have href")
+		}
+		templated, hasTemplated := object["templated"]
+		if hasTemplated {
+			if _, ok := templated.(bool); !ok {
+				return newViolation(CodeInvalidLinks, "link "+halPath(linkPath)+" templated must be a boolean")
+			}
+		}
+		if templated == true && !strings.Contains(href, "{") {
+			return newViolation(CodeInvalidLinks, "link "+halPath(linkPath)+" is templated, but href is not a URI template")
+		}
+		if rel == "curies" {
+			if name, ok := object["name"].(string); !ok || name == "" {
+				return newViolation(CodeInvalidLinks, "link "+halPath(linkPath)+" must have name")
+			}
+		}
+	}
+	return nil
+}
+
+func halPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,108 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHALLinks(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     string
+		wantCode    string
+	}{
+		{
+			name: "links",
+			body: `{"total": 30, "_links": {"self": {"href": "/orders/1"}, "items": [{"href": "/orders/1/items"}]}}`,
+		},
+		{
+			name: "templated",
+			body: `{"total": 30, "_links": {"find": {"href": "/orders{?id}", "templated": true}}}`,
+		},
+		{
+			name: "curies",
+			body: `{"total": 30, "_links": {"curies": [{"name": "doc", "href": "/docs/{rel}", "templated": true}]}}`,
+		},
+		{
+			name: "embedded",
+			body: `{"total": 30, "_embedded": {"items": [{"_links": {"self": {"href": "/items/1"}}}]}}`,
+		},
+		{
+			name:    "schema is validated",
+			body:    `{"_links": {"self": {"href": "/orders/1"}}}`,
+			wantErr: "total in body is required",
+		},
+		{
+			name:     "missing href",
+			body:     `{"total": 30, "_links": {"self": {"title": "order"}}}`,
+			wantCode: CodeInvalidLinks,
+		},
+		{
+			name:     "invalid templated flag",
+			body:     `{"total": 30, "_links": {"find": {"href": "/orders{?id}", "templated": "yes"}}}`,
+			wantCode: CodeInvalidLinks,
+		},
+		{
+			name:     "templated without template",
+			body:     `{"total": 30, "_links": {"self": {"href": "/orders/1", "templated": true}}}`,
+			wantCode: CodeInvalidLinks,
+		},
+		{
+			name:     "curie without name",
+			body:     `{"total": 30, "_links": {"curies": [{"href": "/docs/{rel}", "templated": true}]}}`,
+			wantCode: CodeInvalidLinks,
+		},
+		{
+			name:     "links is not an object",
+			body:     `{"total": 30, "_links": []}`,
+			wantCode: CodeInvalidLinks,
+		},
+		{
+			name:     "invalid embedded links",
+			body:     `{"total": 30, "_embedded": {"items": [{"_links": {"self": {}}}]}}`,
+			wantCode: CodeInvalidLinks,
+		},
+		{
+			name:        "plain json",
+			contentType: "application/json",
+			body:        `{"total": 30, "_links": {"self": {}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"hal.yaml", WithHALLinks())
+			require.NoError(t, err)
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = halContentType
+			}
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", contentType)
+			rec.Body = bytes.NewBufferString(tt.body)
+			err = v.VerifyResponse(rec.Result(), httptest.NewRequest("GET", "/v1/orders/1", nil))
+			assertVerifyErr(t, err, tt.wantErr, tt.wantCode)
+		})
+	}
+}
+
+func TestHALLinks_Message(t *testing.T) {
+	body := map[string]interface{}{
+		"_embedded": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{},
+				map[string]interface{}{
+					"_links": map[string]interface{}{"self": map[string]interface{}{}},
+				},
+			},
+		},
+	}
+	err := validateHALResource(body, "")
+	assert.EqualError(t, err, "invalid_links: link /_embedded/items/1/_links/self must have href")
+}
@@ -0,0 +1,213 @@
+package revisor
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// harTruncatedComment marks bodies cut to the configured size
+const harTruncatedComment = "body truncated"
+
+type harLog struct {
+	Log struct {
+		Version string `json:"version"`
+		Creator struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         struct {
+		Send    float64 `json:"send"`
+		Wait    float64 `json:"wait"`
+		Receive float64 `json:"receive"`
+	} `json:"timings"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARRecorder verifies exchanges and records them into HAR log, producing
+// replayable artifacts for debugging contract violations. Exchanges which
+// failed verification are always recorded with the violation as a comment,
+// valid ones are sampled.
+type HARRecorder struct {
+	verify      func(*http.Response, *http.Request) error
+	sampleRate  float64
+	maxBodySize int
+	random      func() float64
+	now         func() time.Time
+
+	mu  sync.Mutex
+	har harLog
+}
+
+// NewHARRecorder returns HARRecorder which verifies exchanges with verify function,
+// e.g. returned from NewVerifier. sampleRate is a fraction of valid exchanges to record,
+// recorded bodies are cut to maxBodySize bytes, unless it is 0.
+func NewHARRecorder(verify func(*http.Response, *http.Request) error, sampleRate float64, maxBodySize int) *HARRecorder {
+	r := &HARRecorder{
+		verify:      verify,
+		sampleRate:  sampleRate,
+		maxBodySize: maxBodySize,
+		random:      rand.Float64,
+		now:         time.Now,
+	}
+	r.har.Log.Version = "1.2"
+	r.har.Log.Creator.Name = "revisor"
+	r.har.Log.Creator.Version = "1.0"
+	r.har.Log.Entries = []harEntry{}
+	return r
+}
+
+// SetClock sets a source of time of recorded timestamps
+func (r *HARRecorder) SetClock(clock Clock) {
+	r.now = clock.Now
+}
+
+// SetRandomSource sets a source of random numbers used to sample valid exchanges
+func (r *HARRecorder) SetRandomSource(src rand.Source) {
+	r.random = lockedFloat64(src)
+}
+
+// Verify verifies the exchange and records it according to the sampling.
+// Request and response bodies are restored, so that they can be read again.
+// Nil request or response is reported as ErrNilInput.
+func (r *HARRecorder) Verify(res *http.Response, req *http.Request) error {
+	if res == nil || req == nil {
+		return ErrNilInput
+	}
+	reqBody, resBody, restore, err := captureBodies(res, req)
+	if err != nil {
+		return err
+	}
+	started := r.now()
+	verifyErr := r.verify(res, req)
+	restore()
+	if verifyErr == nil && r.random() >= r.sampleRate {
+		return nil
+	}
+
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harNameValues(req.Header),
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Status:      res.StatusCode,
+			StatusText:  http.StatusText(res.StatusCode),
+			HTTPVersion: res.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harNameValues(res.Header),
+			RedirectURL: res.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(resBody),
+		},
+	}
+	entry.Request.QueryString = harNameValues(req.URL.Query())
+	if len(reqBody) != 0 {
+		text, comment := r.capBody(reqBody)
+		entry.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: text, Comment: comment}
+	}
+	text, comment := r.capBody(resBody)
+	entry.Response.Content = harContent{Size: len(resBody), MimeType: res.Header.Get("Content-Type"), Text: text, Comment: comment}
+	if verifyErr != nil {
+		entry.Comment = verifyErr.Error()
+	}
+
+	r.mu.Lock()
+	r.har.Log.Entries = append(r.har.Log.Entries, entry)
+	r.mu.Unlock()
+	return verifyErr
+}
+
+// Write writes recorded exchanges to w as HAR file
+func (r *HARRecorder) Write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Wrap(writeJSON(w, r.har), "failed to write har")
+}
+
+// capBody returns body cut to maximum body size and a comment if it was cut
+func (r *HARRecorder) capBody(body []byte) (string, string) {
+	if r.maxBodySize == 0 || len(body) <= r.maxBodySize {
+		return string(body), ""
+	}
+	return string(body[:r.maxBodySize]), harTruncatedComment
+}
+
+// harNameValues lists header or query values sorted by name
+func harNameValues(values map[string][]string) []harNameValue {
+	list := []harNameValue{}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range values[name] {
+			list = append(list, harNameValue{Name: name, Value: value})
+		}
+	}
+	return list
+}
@@ -0,0 +1,68 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHARRecorder(t *testing.T) {
+	verify, err := NewVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		sampleRate float64
+		body       string
+		wantErr    bool
+		recorded   bool
+	}{
+		{name: "valid exchange sampled", sampleRate: 1, body: `{"id":1,"username":"testuser"}`, recorded: true},
+		{name: "valid exchange not sampled", sampleRate: 0, body: `{"id":1}`},
+		{name: "violation always recorded", sampleRate: 0, body: `{"id":"12"}`, wantErr: true, recorded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := NewHARRecorder(verify, tt.sampleRate, 10)
+			recorder.random = func() float64 { return 0.5 }
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			_, err := rec.WriteString(tt.body)
+			require.NoError(t, err)
+			res := rec.Result()
+			err = recorder.Verify(res, httptest.NewRequest("GET", "/v2/user/testuser?verbose=true", nil))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			restored, err := ioutil.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(restored))
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, recorder.Write(buf))
+			har := &harLog{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), har))
+			assert.Equal(t, "1.2", har.Log.Version)
+			if !tt.recorded {
+				assert.Empty(t, har.Log.Entries)
+				return
+			}
+			require.Len(t, har.Log.Entries, 1)
+			entry := har.Log.Entries[0]
+			assert.Equal(t, []harNameValue{{Name: "verbose", Value: "true"}}, entry.Request.QueryString)
+			assert.Equal(t, tt.body[:10], entry.Response.Content.Text)
+			assert.Equal(t, harTruncatedComment, entry.Response.Content.Comment)
+			assert.Equal(t, len(tt.body), entry.Response.Content.Size)
+			assert.Equal(t, tt.wantErr, strings.Contains(entry.Comment, "id in body must be of type integer"))
+		})
+	}
+}
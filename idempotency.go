@@ -0,0 +1,85 @@
+package revisor
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+)
+
+// requiresIdempotencyKeyExt is an operation extension which declares that
+// requests of unsafe operation must have Idempotency-Key header, e.g.
+//
+//	x-requires-idempotency-key: true
+const requiresIdempotencyKeyExt = "x-requires-idempotency-key"
+
+// maxIdempotencyKeyLength is the maximum length of idempotency key
+const maxIdempotencyKeyLength = 255
+
+// CheckIdempotencyKeys enables checks of Idempotency-Key header of requests
+// to unsafe operations declared with x-requires-idempotency-key extension.
+// Requests without the header are reported as Violation with
+// CodeMissingIdempotencyKey code, and requests with a key which is neither
+// a quoted string nor a token of at most 255 visible characters are reported
+// with CodeInvalidIdempotencyKey code.
+func CheckIdempotencyKeys(a *apiVerifier) {
+	a.opts.checkIdempotency = true
+}
+
+// requiresIdempotencyKey checks if the operation is declared
+// with x-requires-idempotency-key extension
+func requiresIdempotencyKey(operation *spec.Operation) bool {
+	required, _ := operation.Extensions[requiresIdempotencyKeyExt].(bool)
+	return required
+}
+
+// verifyIdempotencyKey checks Idempotency-Key header of the request
+func (a *apiVerifier) verifyIdempotencyKey(req *http.Request) error {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !requiresIdempotencyKey(operation) {
+		return err
+	}
+	keys := req.Header[http.CanonicalHeaderKey("Idempotency-Key")]
+	if len(keys) == 0 {
+		return newViolation(CodeMissingIdempotencyKey, "Idempotency-Key header is required")
+	}
+	if len(keys) > 1 {
+		return newViolation(CodeInvalidIdempotencyKey, "Idempotency-Key header is sent more than once")
+	}
+	if !validIdempotencyKey(keys[0]) {
+		return newViolation(CodeInvalidIdempotencyKey, "Idempotency-Key is not valid: "+strconv.Quote(keys[0]))
+	}
+	return nil
+}
+
+// validIdempotencyKey checks if key is a non-empty quoted string with
+// escaped quotes and backslashes, or a token of visible characters
+func validIdempotencyKey(key string) bool {
+	quoted := len(key) > 1 && key[0] == '"' && key[len(key)-1] == '"'
+	if quoted {
+		key = key[1 : len(key)-1]
+	}
+	length := 0
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c < 0x20 || c > 0x7e:
+			return false
+		case quoted && c == '\\':
+			i++
+			if i == len(key) || key[i] != '"' && key[i] != '\\' {
+				return false
+			}
+		case quoted && c == '"':
+			return false
+		case !quoted && (c == ' ' || c == '"' || c == ',' || c == ';' || c == '\\'):
+			return false
+		}
+		length++
+	}
+	return length > 0 && length <= maxIdempotencyKeyLength
+}
@@ -0,0 +1,108 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIdempotencyKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		keys     []string
+		wantCode string
+	}{
+		{
+			name:   "token",
+			method: "POST", path: "/v1/payments",
+			keys: []string{"8e03978e-40d5-43e8-bc93-6894a57f9324"},
+		},
+		{
+			name:   "quoted string",
+			method: "POST", path: "/v1/payments",
+			keys: []string{`"8e03978e 40d5"`},
+		},
+		{
+			name:   "missing",
+			method: "POST", path: "/v1/payments",
+			wantCode: CodeMissingIdempotencyKey,
+		},
+		{
+			name:   "empty",
+			method: "POST", path: "/v1/payments",
+			keys:     []string{""},
+			wantCode: CodeInvalidIdempotencyKey,
+		},
+		{
+			name:   "sent twice",
+			method: "POST", path: "/v1/payments",
+			keys:     []string{"a", "b"},
+			wantCode: CodeInvalidIdempotencyKey,
+		},
+		{
+			name:   "invalid",
+			method: "POST", path: "/v1/payments",
+			keys:     []string{"a b"},
+			wantCode: CodeInvalidIdempotencyKey,
+		},
+		{
+			name:   "safe method",
+			method: "GET", path: "/v1/payments",
+		},
+		{
+			name:   "not required",
+			method: "POST", path: "/v1/refunds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"idempotency.yaml", CheckIdempotencyKeys)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for _, key := range tt.keys {
+				req.Header.Add("Idempotency-Key", key)
+			}
+			assertVerifyErr(t, v.VerifyRequest(req), "", tt.wantCode)
+		})
+	}
+}
+
+func TestValidIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "abc-123", want: true},
+		{key: `"abc 123"`, want: true},
+		{key: `"a\"b\\c"`, want: true},
+		{key: strings.Repeat("a", maxIdempotencyKeyLength), want: true},
+		{key: strings.Repeat("a", maxIdempotencyKeyLength+1)},
+		{key: ""},
+		{key: `""`},
+		{key: `"a\nb"`},
+		{key: `"a"b"`},
+		{key: `"abc\"`},
+		{key: "a,b"},
+		{key: "ключ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.want, validIdempotencyKey(tt.key))
+		})
+	}
+}
+
+func TestCheckIdempotencyKeys_Warning(t *testing.T) {
+	var warnings []string
+	_, err := New(testdata+"idempotency.yaml", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "POST /payments: x-requires-idempotency-key is not enforced")
+}
@@ -0,0 +1,26 @@
+package revisor
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// InternalError is reported when verification panics, e.g. due to a pathological
+// schema or a decoder bug, so that it never crashes the goroutine of the caller.
+// Stack is the stack trace of the panic, which is worth including in bug reports.
+type InternalError struct {
+	Panic interface{}
+	Stack []byte
+}
+
+func (e *InternalError) Error() string {
+	return fmt.Sprintf("internal error: %v", e.Panic)
+}
+
+// recoverInternalError recovers from a panic and sets err to InternalError,
+// it must be deferred directly
+func recoverInternalError(err *error) {
+	if p := recover(); p != nil {
+		*err = &InternalError{Panic: p, Stack: debug.Stack()}
+	}
+}
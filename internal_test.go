@@ -0,0 +1,55 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingBody is a body which panics when read
+type panickingBody struct{}
+
+func (panickingBody) Read([]byte) (int, error) {
+	panic("broken reader")
+}
+
+func (panickingBody) Close() error {
+	return nil
+}
+
+func TestInternalError(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []option
+	}{
+		{name: "default"},
+		{name: "within budget", options: []option{WithValidationBudget(time.Minute)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+sampleV2YAML, tt.options...)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "/v2/pet", nil)
+			req.Header.Set("Content-Type", "application/json")
+			req.Body = panickingBody{}
+			err = v.VerifyRequest(req)
+			internal, ok := errors.Cause(err).(*InternalError)
+			require.True(t, ok, "%v", err)
+			assert.Equal(t, "broken reader", internal.Panic)
+			assert.EqualError(t, internal, "internal error: broken reader")
+			assert.Contains(t, string(internal.Stack), "panickingBody.Read")
+
+			rec := httptest.NewRecorder()
+			res := rec.Result()
+			res.Body = panickingBody{}
+			err = v.VerifyResponse(res, httptest.NewRequest("GET", "/v2/pet/1", nil))
+			_, ok = errors.Cause(err).(*InternalError)
+			assert.True(t, ok, "%v", err)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package revisor
+
+import (
+	"mime"
+	"sort"
+	"strings"
+)
+
+// jsonAPIContentType is the media type of JSON:API documents
+const jsonAPIContentType = "application/vnd.api+json"
+
+// jsonAPIMembers are members allowed at the top level of JSON:API document
+var jsonAPIMembers = map[string]bool{
+	"data":     true,
+	"errors":   true,
+	"meta":     true,
+	"links":    true,
+	"included": true,
+	"jsonapi":  true,
+}
+
+// WithJSONAPI enables handling of JSON:API documents, i.e. bodies of
+// application/vnd.api+json content type, which is then accepted wherever
+// application/json is. Definitions referenced by the spec are expected to
+// describe resource attributes, so attributes of primary data are validated
+// against them instead of the whole document, e.g. attributes of every
+// resource if schema is an array. Documents with errors are validated as is.
+// If validateStructure is set, top level structure of documents is checked
+// and violations are reported with CodeInvalidJSONAPI code.
+func WithJSONAPI(validateStructure bool) option {
+	return func(a *apiVerifier) {
+		a.opts.jsonAPI = true
+		a.opts.stages = append(a.opts.stages, Stage{
+			Name: "jsonapi",
+			Decoded: func(ctx *StageContext, body interface{}) (interface{}, error) {
+				return jsonAPIAttributes(ctx, body, validateStructure)
+			},
+		})
+	}
+}
+
+// isJSONAPI checks if content type is JSON:API media type
+func isJSONAPI(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == jsonAPIContentType
+}
+
+// jsonMediaType checks if content type is JSON based media type enabled
+// with options, which is accepted wherever application/json is
+func (a *apiVerifier) jsonMediaType(contentType string) bool {
+	return a.opts.jsonAPI && isJSONAPI(contentType) || a.opts.hal && isHAL(contentType)
+}
+
+// jsonAPIAttributes returns attributes of primary data of JSON:API document
+func jsonAPIAttributes(ctx *StageContext, body interface{}, validateStructure bool) (interface{}, error) {
+	contentType := ctx.Request.Header.Get("Content-Type")
+	if ctx.Response != nil {
+		contentType = ctx.Response.Header.Get("Content-Type")
+	}
+	if !isJSONAPI(contentType) {
+		return body, nil
+	}
+	document, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, newViolation(CodeInvalidJSONAPI, "document must be an object")
+	}
+	if validateStructure {
+		err := validateJSONAPIDocument(document, ctx.Response == nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := document["errors"]; ok {
+		return body, nil
+	}
+	switch data := document["data"].(type) {
+	case map[string]interface{}:
+		return resourceAttributes(data), nil
+	case []interface{}:
+		attributes := make([]interface{}, len(data))
+		for i, resource := range data {
+			resource, ok := resource.(map[string]interface{})
+			if !ok {
+				return nil, newViolation(CodeInvalidJSONAPI, "data must contain resource objects")
+			}
+			attributes[i] = resourceAttributes(resource)
+		}
+		return attributes, nil
+	}
+	return document["data"], nil
+}
+
+// resourceAttributes returns attributes of resource object,
+// which are empty if it doesn't have any
+func resourceAttributes(resource map[string]interface{}) interface{} {
+	if attributes, ok := resource["attributes"]; ok {
+		return attributes
+	}
+	return map[string]interface{}{}
+}
+
+// validateJSONAPIDocument checks top level structure of JSON:API document,
+// resources of requests may omit id, as it is generated by the server
+func validateJSONAPIDocument(document map[string]interface{}, request bool) error {
+	var unknown []string
+	for member := range document {
+		if !jsonAPIMembers[member] {
+			unknown = append(unknown, member)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return newViolation(CodeInvalidJSONAPI, "unknown top level members: "+strings.Join(unknown, ", "))
+	}
+	data, hasData := document["data"]
+	_, hasErrors := document["errors"]
+	_, hasMeta := document["meta"]
+	if !hasData && !hasErrors && !hasMeta {
+		return newViolation(CodeInvalidJSONAPI, "document must contain at least one of data, errors or meta")
+	}
+	if hasData && hasErrors {
+		return newViolation(CodeInvalidJSONAPI, "document must not contain both data and errors")
+	}
+	if _, ok := document["included"]; ok && !hasData {
+		return newViolation(CodeInvalidJSONAPI, "document must not contain included without data")
+	}
+	if errs, ok := document["errors"]; ok {
+		if _, ok := errs.([]interface{}); !ok {
+			return newViolation(CodeInvalidJSONAPI, "errors must be an array")
+		}
+	}
+	switch data := data.(type) {
+	case nil:
+	case map[string]interface{}:
+		return validateJSONAPIResource(data, request)
+	case []interface{}:
+		for _, resource := range data {
+			resource, ok := resource.(map[string]interface{})
+			if !ok {
+				return newViolation(CodeInvalidJSONAPI, "data must contain resource objects")
+			}
+			if err := validateJSONAPIResource(resource, request); err != nil {
+				return err
+			}
+		}
+	default:
+		return newViolation(CodeInvalidJSONAPI, "data must be null, a resource object or an array of them")
+	}
+	return nil
+}
+
+func validateJSONAPIResource(resource map[string]interface{}, request bool) error {
+	if _, ok := resource["type"].(string); !ok {
+		return newViolation(CodeInvalidJSONAPI, "resource must have type string")
+	}
+	id, hasID := resource["id"]
+	if _, ok := id.(string); !ok && (hasID || !request) {
+		return newViolation(CodeInvalidJSONAPI, "resource must have id string")
+	}
+	if attributes, ok := resource["attributes"]; ok {
+		if _, ok := attributes.(map[string]interface{}); !ok {
+			return newViolation(CodeInvalidJSONAPI, "resource attributes must be an object")
+		}
+	}
+	return nil
+}
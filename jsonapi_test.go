@@ -0,0 +1,159 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONAPI(t *testing.T) {
+	tests := []struct {
+		name              string
+		validateStructure bool
+		method            string
+		status            int
+		contentType       string
+		body              string
+		wantErr           string
+		wantCode          string
+	}{
+		{
+			name:   "resource",
+			method: "POST", status: 201,
+			body: `{"data": {"type": "articles", "id": "1", "attributes": {"title": "JSON:API"}}}`,
+		},
+		{
+			name:   "invalid attributes",
+			method: "POST", status: 201,
+			body:    `{"data": {"type": "articles", "id": "1", "attributes": {"title": 1}}}`,
+			wantErr: "title in body must be of type string",
+		},
+		{
+			name:   "missing attributes",
+			method: "POST", status: 201,
+			body:    `{"data": {"type": "articles", "id": "1"}}`,
+			wantErr: "title in body is required",
+		},
+		{
+			name:   "collection",
+			method: "GET", status: 200,
+			body: `{"data": [{"type": "articles", "id": "1", "attributes": {"title": "JSON:API"}}], "links": {}}`,
+		},
+		{
+			name:   "invalid collection",
+			method: "GET", status: 200,
+			body:    `{"data": [{"type": "articles", "id": "1", "attributes": {}}]}`,
+			wantErr: "title in body is required",
+		},
+		{
+			name:   "errors",
+			method: "POST", status: 422,
+			body: `{"errors": [{"status": "422", "title": "Invalid Attribute"}]}`,
+		},
+		{
+			name:   "plain json",
+			method: "POST", status: 201,
+			contentType: "application/json",
+			body:        `{"title": "JSON"}`,
+		},
+		{
+			name:   "media type parameters",
+			method: "POST", status: 201,
+			contentType: "application/vnd.api+json; ext=bulk",
+			body:        `{"data": {"type": "articles", "id": "1", "attributes": {"title": "JSON:API"}}}`,
+		},
+		{
+			name:   "structure not validated",
+			method: "POST", status: 201,
+			body: `{"data": {"attributes": {"title": "JSON:API"}}, "extra": true}`,
+		},
+		{
+			name:   "unknown member",
+			method: "POST", status: 201, validateStructure: true,
+			body:     `{"data": {"type": "articles", "id": "1", "attributes": {"title": "JSON:API"}}, "extra": true}`,
+			wantCode: CodeInvalidJSONAPI,
+		},
+		{
+			name:   "data and errors",
+			method: "POST", status: 201, validateStructure: true,
+			body:     `{"data": null, "errors": []}`,
+			wantCode: CodeInvalidJSONAPI,
+		},
+		{
+			name:   "missing type",
+			method: "GET", status: 200, validateStructure: true,
+			body:     `{"data": [{"id": "1", "attributes": {"title": "JSON:API"}}]}`,
+			wantCode: CodeInvalidJSONAPI,
+		},
+		{
+			name:   "not an object",
+			method: "GET", status: 200,
+			body:     `[]`,
+			wantCode: CodeInvalidJSONAPI,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"jsonapi.yaml", WithJSONAPI(tt.validateStructure))
+			require.NoError(t, err)
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = jsonAPIContentType
+			}
+
+			req := httptest.NewRequest(tt.method, "/v1/articles", nil)
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", contentType)
+			rec.WriteHeader(tt.status)
+			rec.Body = bytes.NewBufferString(tt.body)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), tt.wantErr, tt.wantCode)
+		})
+	}
+}
+
+func TestJSONAPI_Request(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode string
+	}{
+		{
+			name: "client generated id",
+			body: `{"data": {"type": "articles", "id": "1", "attributes": {"title": "JSON:API"}}}`,
+		},
+		{
+			name: "server generated id",
+			body: `{"data": {"type": "articles", "attributes": {"title": "JSON:API"}}}`,
+		},
+		{
+			name:     "id is not a string",
+			body:     `{"data": {"type": "articles", "id": 1, "attributes": {"title": "JSON:API"}}}`,
+			wantCode: CodeInvalidJSONAPI,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"jsonapi.yaml", WithJSONAPI(true))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "/v1/articles", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", jsonAPIContentType)
+			assertVerifyErr(t, v.VerifyRequest(req), "", tt.wantCode)
+		})
+	}
+}
+
+func TestJSONAPI_Disabled(t *testing.T) {
+	v, err := New(testdata + "jsonapi.yaml")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/v1/articles", strings.NewReader(`{"data": {}}`))
+	req.Header.Set("Content-Type", jsonAPIContentType)
+	err = v.VerifyRequest(req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Content-Type is not configured")
+}
@@ -0,0 +1,96 @@
+package revisor
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+)
+
+// sensitiveExt is a schema extension which marks values which must never
+// be returned in responses, same as values of password format, e.g.
+//
+//	secret:
+//	  type: string
+//	  x-sensitive: true
+const sensitiveExt = "x-sensitive"
+
+// LeakDetector finds values of a kind of sensitive data in strings of
+// response bodies
+type LeakDetector struct {
+	// Name describes the kind of data in reports, e.g. JWT
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Detectors of common sensitive data
+var (
+	// JWTDetector finds JSON Web Tokens
+	JWTDetector = LeakDetector{
+		Name:    "JWT",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+	// CardNumberDetector finds numbers of Visa, Mastercard,
+	// American Express and Discover cards
+	CardNumberDetector = LeakDetector{
+		Name:    "card number",
+		Pattern: regexp.MustCompile(`\b(?:4\d{12}(?:\d{3})?|5[1-5]\d{14}|3[47]\d{13}|6(?:011|5\d{2})\d{12})\b`),
+	}
+)
+
+// WithLeakDetection enables detection of sensitive data in decoded bodies
+// of responses: fields of password format or marked with x-sensitive
+// extension must not be returned, and strings must not match any of
+// detectors. Leaks are reported as Violation with CodeSensitiveData code.
+func WithLeakDetection(detectors ...LeakDetector) option {
+	return func(a *apiVerifier) {
+		a.opts.detectLeaks = true
+		a.opts.leakDetectors = append(a.opts.leakDetectors, detectors...)
+	}
+}
+
+// detectLeaks checks decoded body of the response for sensitive data
+func (a *apiVerifier) detectLeaks(schema *spec.Schema, decoded interface{}) error {
+	err := walkBody(schema, decoded, "", func(schema *spec.Schema, value interface{}, pointer string) error {
+		if value != nil && value != "" && isSensitive(schema) {
+			return newViolation(CodeSensitiveData, bodyField(pointer)+" in body is sensitive")
+		}
+		return nil
+	})
+	if err != nil || len(a.opts.leakDetectors) == 0 {
+		return err
+	}
+	return a.detectPatterns(decoded, "")
+}
+
+// detectPatterns checks strings of the value for matches of detectors
+func (a *apiVerifier) detectPatterns(value interface{}, pointer string) error {
+	switch v := value.(type) {
+	case string:
+		for _, detector := range a.opts.leakDetectors {
+			if detector.Pattern.MatchString(v) {
+				return newViolation(CodeSensitiveData, bodyField(pointer)+" in body contains "+detector.Name)
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if err := a.detectPatterns(v[key], pointer+"/"+escapePointerToken(key)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := a.detectPatterns(item, pointer+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isSensitive checks if the schema is of password format
+// or marked with x-sensitive extension
+func isSensitive(schema *spec.Schema) bool {
+	sensitive, _ := schema.Extensions[sensitiveExt].(bool)
+	return sensitive || schema.Format == "password"
+}
@@ -0,0 +1,221 @@
+package revisor
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// link is a link-value of Link header
+type link struct {
+	target string
+	rels   []string
+}
+
+// CheckLinkHeaders enables checks of Link headers of responses, which must
+// be valid RFC 8288 links with a single rel parameter listing relation
+// types. If requirePaginationLinks is set, successful responses of
+// operations declared with x-pagination extension must link the next page
+// when the page is full, and the previous page when the request has offset
+// or cursor. Invalid links are reported as Violation with
+// CodeInvalidLinkHeader code.
+func CheckLinkHeaders(requirePaginationLinks bool) option {
+	return func(a *apiVerifier) {
+		a.opts.checkLinkHeaders = true
+		a.opts.paginationLinks = requirePaginationLinks
+	}
+}
+
+// verifyLinkHeaders checks syntax of Link headers of the response
+func verifyLinkHeaders(res *http.Response) error {
+	for _, header := range res.Header[http.CanonicalHeaderKey("Link")] {
+		if _, err := parseLinks(header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyPaginationLinks checks that successful response of paginated
+// operation links adjacent pages
+func (a *apiVerifier) verifyPaginationLinks(req *http.Request, res *http.Response, decoded interface{}) error {
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	p, ok, err := operationPagination(operation)
+	if err != nil || !ok {
+		return err
+	}
+	rels := make(map[string]bool)
+	for _, header := range res.Header[http.CanonicalHeaderKey("Link")] {
+		links, err := parseLinks(header)
+		if err != nil {
+			return err
+		}
+		for _, l := range links {
+			for _, rel := range l.rels {
+				rels[rel] = true
+			}
+		}
+	}
+	query := req.URL.Query()
+	paged := query.Get(p.Cursor) != ""
+	if p.Style == paginationOffset {
+		offset, _ := strconv.ParseInt(query.Get(p.Offset), 10, 64)
+		paged = offset > 0
+	}
+	if paged && !rels["prev"] {
+		return newViolation(CodeInvalidLinkHeader, "Link header with prev relation is required")
+	}
+	limit, err := p.limit(req)
+	if err != nil || limit == 0 || p.Items == "" {
+		return nil
+	}
+	items := decoded
+	if p.Items != "/" {
+		tokens, ok := pointerTokens(p.Items)
+		if ok {
+			items, _ = valueAt(decoded, tokens)
+		}
+	}
+	if list, ok := items.([]interface{}); ok && int64(len(list)) >= limit && !rels["next"] {
+		return newViolation(CodeInvalidLinkHeader, "Link header with next relation is required")
+	}
+	return nil
+}
+
+// parseLinks parses comma-separated link-values of Link header
+func parseLinks(header string) ([]link, error) {
+	var links []link
+	s := header
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if !strings.HasPrefix(s, "<") {
+			return nil, invalidLink(header, "target must be enclosed in angle brackets")
+		}
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return nil, invalidLink(header, "target is not terminated")
+		}
+		l := link{target: s[1:end]}
+		if _, err := url.Parse(l.target); err != nil || strings.ContainsAny(l.target, " \t<") {
+			return nil, invalidLink(header, "target is not a valid URI reference")
+		}
+		s = strings.TrimLeft(s[end+1:], " \t")
+		rel := false
+		for strings.HasPrefix(s, ";") {
+			var name, value string
+			var err error
+			name, value, s, err = parseHeaderParam(strings.TrimLeft(s[1:], " \t"))
+			if err != nil {
+				return nil, invalidLink(header, err.Error())
+			}
+			if name != "rel" {
+				continue
+			}
+			if rel {
+				return nil, invalidLink(header, "rel parameter is repeated")
+			}
+			rel = true
+			l.rels = strings.Fields(strings.ToLower(value))
+			if len(l.rels) == 0 {
+				return nil, invalidLink(header, "rel parameter is empty")
+			}
+			for _, r := range l.rels {
+				if !validRelationType(r) {
+					return nil, invalidLink(header, "relation type "+r+" is not valid")
+				}
+			}
+		}
+		if !rel {
+			return nil, invalidLink(header, "rel parameter is required")
+		}
+		links = append(links, l)
+		if s == "" {
+			return links, nil
+		}
+		if s[0] != ',' {
+			return nil, invalidLink(header, "unexpected "+string(s[0]))
+		}
+		s = s[1:]
+	}
+}
+
+// parseHeaderParam parses header parameter and returns its lowercase name,
+// unquoted value and the rest of the header
+func parseHeaderParam(s string) (name, value, rest string, err error) {
+	i := 0
+	for i < len(s) && isTokenChar(s[i]) {
+		i++
+	}
+	if i == 0 {
+		return "", "", "", errInvalidParam
+	}
+	name, s = strings.ToLower(s[:i]), strings.TrimLeft(s[i:], " \t")
+	if !strings.HasPrefix(s, "=") {
+		return name, "", s, nil
+	}
+	s = strings.TrimLeft(s[1:], " \t")
+	if strings.HasPrefix(s, `"`) {
+		var b []byte
+		for i = 1; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+			}
+			b = append(b, s[i])
+		}
+		if i == len(s) {
+			return "", "", "", errInvalidParam
+		}
+		return name, string(b), strings.TrimLeft(s[i+1:], " \t"), nil
+	}
+	i = 0
+	for i < len(s) && isTokenChar(s[i]) {
+		i++
+	}
+	if i == 0 {
+		return "", "", "", errInvalidParam
+	}
+	return name, s[:i], strings.TrimLeft(s[i:], " \t"), nil
+}
+
+// errInvalidParam is returned for parameters which are neither
+// tokens nor tokens with token or quoted-string values
+var errInvalidParam = errors.New("parameter is not valid")
+
+// validRelationType checks if rel is either a registered relation type,
+// which is lowercase, or an extension relation type, which is an absolute URI
+func validRelationType(rel string) bool {
+	if rel[0] >= 'a' && rel[0] <= 'z' {
+		registered := true
+		for i := 1; i < len(rel); i++ {
+			c := rel[i]
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '.' || c == '-') {
+				registered = false
+				break
+			}
+		}
+		if registered {
+			return true
+		}
+	}
+	u, err := url.Parse(rel)
+	return err == nil && u.IsAbs()
+}
+
+// isTokenChar checks if c may be a part of RFC 7230 token
+func isTokenChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+}
+
+func invalidLink(header, reason string) error {
+	return newViolation(CodeInvalidLinkHeader, "Link header is not valid: "+reason+": "+strconv.Quote(header))
+}
@@ -0,0 +1,164 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLinks(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    []link
+		wantErr string
+	}{
+		{
+			header: `<https://example.com/orders?offset=2>; rel="next"`,
+			want:   []link{{target: "https://example.com/orders?offset=2", rels: []string{"next"}}},
+		},
+		{
+			header: `</orders?offset=0>;rel=prev;title="a, b", </orders?offset=4> ; rel="next last"`,
+			want: []link{
+				{target: "/orders?offset=0", rels: []string{"prev"}},
+				{target: "/orders?offset=4", rels: []string{"next", "last"}},
+			},
+		},
+		{
+			header: `<http://example.com/>; REL="Next http://example.com/rel/Page"`,
+			want:   []link{{target: "http://example.com/", rels: []string{"next", "http://example.com/rel/page"}}},
+		},
+		{
+			header:  `https://example.com/; rel=next`,
+			wantErr: "target must be enclosed in angle brackets",
+		},
+		{
+			header:  `<https://example.com/; rel=next`,
+			wantErr: "target is not terminated",
+		},
+		{
+			header:  `<%>; rel=next`,
+			wantErr: "target is not a valid URI reference",
+		},
+		{
+			header:  `</>; title="home"`,
+			wantErr: "rel parameter is required",
+		},
+		{
+			header:  `</>; rel=next; rel=prev`,
+			wantErr: "rel parameter is repeated",
+		},
+		{
+			header:  `</>; rel=""`,
+			wantErr: "rel parameter is empty",
+		},
+		{
+			header:  `</>; rel="next page_1"`,
+			wantErr: "relation type page_1 is not valid",
+		},
+		{
+			header:  `</>; rel="next`,
+			wantErr: "parameter is not valid",
+		},
+		{
+			header:  `</>; rel=next </a>`,
+			wantErr: "unexpected <",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			links, err := parseLinks(tt.header)
+			if tt.wantErr != "" {
+				assertVerifyErr(t, err, tt.wantErr, CodeInvalidLinkHeader)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, links)
+		})
+	}
+}
+
+func TestCheckLinkHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		links    []string
+		body     string
+		wantErr  string
+		required bool
+	}{
+		{
+			name:  "valid links",
+			url:   "/v1/orders?limit=2",
+			links: []string{`</v1/orders?offset=2>; rel=next`, `</v1/orders?offset=4>; rel=last`},
+			body:  `{"data": [{}, {}], "meta": {"total": 6}}`,
+		},
+		{
+			name:    "invalid link",
+			url:     "/v1/orders",
+			links:   []string{`</v1/orders?offset=2>`},
+			body:    `{"data": [], "meta": {"total": 0}}`,
+			wantErr: "rel parameter is required",
+		},
+		{
+			name: "missing pagination links aren't required",
+			url:  "/v1/orders?limit=2&offset=2",
+			body: `{"data": [{}, {}], "meta": {"total": 6}}`,
+		},
+		{
+			name:     "middle page",
+			url:      "/v1/orders?limit=2&offset=2",
+			links:    []string{`</v1/orders?offset=0>; rel=prev, </v1/orders?offset=4>; rel=next`},
+			body:     `{"data": [{}, {}], "meta": {"total": 6}}`,
+			required: true,
+		},
+		{
+			name:     "single page",
+			url:      "/v1/orders?limit=2",
+			body:     `{"data": [{}], "meta": {"total": 1}}`,
+			required: true,
+		},
+		{
+			name:     "missing next",
+			url:      "/v1/orders?limit=2",
+			body:     `{"data": [{}, {}], "meta": {"total": 6}}`,
+			wantErr:  "Link header with next relation is required",
+			required: true,
+		},
+		{
+			name:     "missing prev",
+			url:      "/v1/orders?limit=2&offset=4",
+			body:     `{"data": [{}], "meta": {"total": 5}}`,
+			wantErr:  "Link header with prev relation is required",
+			required: true,
+		},
+		{
+			name:     "missing prev of cursor pagination",
+			url:      "/v1/events?after=abc",
+			body:     `{"data": [], "next": null}`,
+			wantErr:  "Link header with prev relation is required",
+			required: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"pagination.yaml", CheckLinkHeaders(tt.required))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			for _, l := range tt.links {
+				rec.Header().Add("Link", l)
+			}
+			rec.Body = bytes.NewBufferString(tt.body)
+			err = v.VerifyResponse(rec.Result(), httptest.NewRequest("GET", tt.url, nil))
+			wantCode := ""
+			if tt.wantErr != "" {
+				wantCode = CodeInvalidLinkHeader
+			}
+			assertVerifyErr(t, err, tt.wantErr, wantCode)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package revisor
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// Issue describes a problem found in OpenAPI definition by a lint rule
+type Issue struct {
+	Rule    string
+	Method  string
+	Path    string
+	Message string
+}
+
+func (i Issue) String() string {
+	s := "[" + i.Rule + "] "
+	if i.Method != "" {
+		s += i.Method + " "
+	}
+	if i.Path != "" {
+		s += i.Path + ": "
+	}
+	return s + i.Message
+}
+
+// Rule checks OpenAPI definition for a particular kind of issues
+type Rule interface {
+	// Name identifies the rule in reported issues
+	Name() string
+	// Check returns issues found in the document, which is expanded,
+	// while the original specification is available with OrigSpec and Raw
+	Check(doc *loads.Document) []Issue
+}
+
+// DefaultRules returns rules OpenAPI definition is checked with by default
+func DefaultRules() []Rule {
+	return []Rule{
+		NewOperationRule("content-types", lintContentTypes),
+		NewOperationRule("missing-operation-id", lintOperationID),
+		ambiguousPathsRule{},
+		unreachableDefinitionsRule{},
+	}
+}
+
+// WithLintRules sets rules OpenAPI definition is checked with, instead of DefaultRules
+func WithLintRules(rules ...Rule) option {
+	return func(a *apiVerifier) {
+		a.opts.lintRules = rules
+	}
+}
+
+// FailOnLintIssues makes verifier constructors fail if issues are found
+// in OpenAPI definition at load time. By default the definition is linted
+// only if the logger is set with WithLogger, and issues are only logged.
+func FailOnLintIssues(a *apiVerifier) {
+	a.opts.failOnLintIssues = true
+}
+
+// LintDefinition loads OpenAPI definition and checks it with configured rules
+func LintDefinition(definitionPath string, options ...option) ([]Issue, error) {
+	a, err := newAPIVerifier(definitionPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lint definition")
+	}
+	a.setOptions(options...)
+	return a.lint(), nil
+}
+
+// lintDefinition reports issues found in the definition at load time
+func (a *apiVerifier) lintDefinition() error {
+	if !a.opts.loggerSet && !a.opts.failOnLintIssues {
+		return nil
+	}
+	issues := a.lint()
+	if a.opts.loggerSet {
+		for _, issue := range issues {
+			a.opts.logf("revisor: lint: %s", issue)
+		}
+	}
+	if len(issues) != 0 && a.opts.failOnLintIssues {
+		return errors.New("definition has " + strconv.Itoa(len(issues)) + " lint issue(s), first one: " + issues[0].String())
+	}
+	return nil
+}
+
+func (a *apiVerifier) lint() []Issue {
+	rules := a.opts.lintRules
+	if rules == nil {
+		rules = DefaultRules()
+	}
+	var issues []Issue
+	for _, rule := range rules {
+		for _, issue := range rule.Check(a.doc) {
+			issue.Rule = rule.Name()
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// NewOperationRule returns Rule which checks every operation of the definition
+// with check function, returned messages are reported as issues of the operation
+func NewOperationRule(name string, check func(swagger *spec.Swagger, pathItem *spec.PathItem, operation *spec.Operation) []string) Rule {
+	return operationRule{name: name, check: check}
+}
+
+type operationRule struct {
+	name  string
+	check func(swagger *spec.Swagger, pathItem *spec.PathItem, operation *spec.Operation) []string
+}
+
+func (r operationRule) Name() string {
+	return r.name
+}
+
+func (r operationRule) Check(doc *loads.Document) []Issue {
+	var issues []Issue
+	swagger := doc.Spec()
+	for _, path := range sortedPaths(swagger) {
+		pathDef := swagger.Paths.Paths[path]
+		for _, method := range httpMethods {
+			operation := pathOperation(method, &pathDef)
+			if operation == nil {
+				continue
+			}
+			for _, message := range r.check(swagger, &pathDef, operation) {
+				issues = append(issues, Issue{Method: method, Path: path, Message: message})
+			}
+		}
+	}
+	return issues
+}
+
+// lintContentTypes checks that operations accepting or returning bodies declare
+// content types and that responses are declared per status code
+func lintContentTypes(swagger *spec.Swagger, pathDef *spec.PathItem, operation *spec.Operation) []string {
+	var messages []string
+	consumes := len(operation.Consumes) != 0 || len(swagger.Consumes) != 0
+	produces := len(operation.Produces) != 0 || len(swagger.Produces) != 0
+
+	for _, param := range operationParams(pathDef, operation) {
+		if (param.In == "body" || param.In == "formData") && !consumes {
+			messages = append(messages, "operation accepts body but consumes is not defined")
+			break
+		}
+	}
+	if operation.Responses == nil || len(operation.Responses.StatusCodeResponses) == 0 {
+		messages = append(messages, "no response status codes are declared")
+	}
+	if operation.Responses == nil || produces {
+		return messages
+	}
+	if operation.Responses.Default != nil && operation.Responses.Default.Schema != nil {
+		messages = append(messages, "default response has schema but produces is not defined")
+	}
+	codes := make([]int, 0, len(operation.Responses.StatusCodeResponses))
+	for code := range operation.Responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if operation.Responses.StatusCodeResponses[code].Schema != nil {
+			messages = append(messages, "response "+strconv.Itoa(code)+" has schema but produces is not defined")
+		}
+	}
+	return messages
+}
+
+// lintOperationID checks that operation ID is defined
+func lintOperationID(swagger *spec.Swagger, pathDef *spec.PathItem, operation *spec.Operation) []string {
+	if operation.ID == "" {
+		return []string{"operationId is not defined"}
+	}
+	return nil
+}
+
+var pathVariable = regexp.MustCompile(`\{[^}]*\}`)
+
+// ambiguousPathsRule reports path templates which differ only
+// in names of variables, so that requests can't be told apart
+type ambiguousPathsRule struct{}
+
+func (ambiguousPathsRule) Name() string {
+	return "ambiguous-paths"
+}
+
+func (ambiguousPathsRule) Check(doc *loads.Document) []Issue {
+	var issues []Issue
+	seen := make(map[string]string)
+	for _, path := range sortedPaths(doc.Spec()) {
+		normalized := pathVariable.ReplaceAllString(strings.TrimSuffix(path, "/"), "{}")
+		if other, ok := seen[normalized]; ok {
+			issues = append(issues, Issue{Path: path, Message: "path template is ambiguous with " + other})
+			continue
+		}
+		seen[normalized] = path
+	}
+	return issues
+}
+
+// unreachableDefinitionsRule reports definitions which are not referenced
+// from operations directly or through other definitions
+type unreachableDefinitionsRule struct{}
+
+func (unreachableDefinitionsRule) Name() string {
+	return "unreachable-definitions"
+}
+
+func (unreachableDefinitionsRule) Check(doc *loads.Document) []Issue {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(doc.Raw(), &raw); err != nil {
+		return []Issue{{Message: "failed to decode definition: " + err.Error()}}
+	}
+	definitions, _ := raw["definitions"].(map[string]interface{})
+	delete(raw, "definitions")
+
+	const prefix = "#/definitions/"
+	reachable := make(map[string]bool)
+	queue := collectRefs(raw, nil)
+	for len(queue) != 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if !strings.HasPrefix(ref, prefix) {
+			continue
+		}
+		name := strings.Replace(strings.Replace(strings.TrimPrefix(ref, prefix), "~1", "/", -1), "~0", "~", -1)
+		if reachable[name] {
+			continue
+		}
+		reachable[name] = true
+		queue = collectRefs(definitions[name], queue)
+	}
+
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		if !reachable[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	issues := make([]Issue, 0, len(names))
+	for _, name := range names {
+		issues = append(issues, Issue{Message: "definition " + name + " is not referenced"})
+	}
+	return issues
+}
+
+// collectRefs appends values of all $ref properties found in decoded JSON
+func collectRefs(node interface{}, refs []string) []string {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k, v := range n {
+			if ref, ok := v.(string); ok && k == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = collectRefs(v, refs)
+		}
+	case []interface{}:
+		for _, v := range n {
+			refs = collectRefs(v, refs)
+		}
+	}
+	return refs
+}
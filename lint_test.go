@@ -0,0 +1,153 @@
+package revisor
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintDefinition(t *testing.T) {
+	issues, err := LintDefinition(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	assert.Contains(t, issues, Issue{
+		Rule:    "content-types",
+		Method:  "POST",
+		Path:    "/store/order",
+		Message: "operation accepts body but consumes is not defined",
+	})
+	assert.Contains(t, issues, Issue{
+		Rule:    "content-types",
+		Method:  "POST",
+		Path:    "/user",
+		Message: "no response status codes are declared",
+	})
+	assert.NotContains(t, issues, Issue{
+		Rule:    "unreachable-definitions",
+		Message: "definition UserMutable is not referenced",
+	})
+	for _, issue := range issues {
+		assert.NotEqual(t, "missing-operation-id", issue.Rule)
+		assert.NotEqual(t, "ambiguous-paths", issue.Rule)
+	}
+
+	t.Run("custom rules", func(t *testing.T) {
+		rule := NewOperationRule("deprecated", func(_ *spec.Swagger, _ *spec.PathItem, op *spec.Operation) []string {
+			if op.Deprecated {
+				return []string{"operation is deprecated"}
+			}
+			return nil
+		})
+		issues, err := LintDefinition(testdata+sampleV2YAML, WithLintRules(rule))
+		require.NoError(t, err)
+		assert.Equal(t, []Issue{{
+			Rule:    "deprecated",
+			Method:  "GET",
+			Path:    "/pet/findByTags",
+			Message: "operation is deprecated",
+		}}, issues)
+		assert.Equal(t, "[deprecated] GET /pet/findByTags: operation is deprecated", issues[0].String())
+	})
+
+	t.Run("definition not found", func(t *testing.T) {
+		_, err := LintDefinition("./non-existing-file.yaml")
+		assert.Regexp(t, "failed to lint definition", err)
+	})
+}
+
+func TestLintContentTypes(t *testing.T) {
+	swagger := &spec.Swagger{}
+	operation := spec.NewOperation("getUser").
+		RespondsWith(200, spec.NewResponse().WithSchema(spec.StringProperty())).
+		WithDefaultResponse(spec.NewResponse().WithSchema(spec.StringProperty()))
+
+	messages := lintContentTypes(swagger, &spec.PathItem{}, operation)
+	assert.Equal(t, []string{
+		"default response has schema but produces is not defined",
+		"response 200 has schema but produces is not defined",
+	}, messages)
+
+	swagger.Produces = []string{"application/json"}
+	assert.Empty(t, lintContentTypes(swagger, &spec.PathItem{}, operation))
+}
+
+func TestAmbiguousPathsRule(t *testing.T) {
+	doc, err := loads.Analyzed([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "ambiguous", "version": "1.0"},
+		"paths": {
+			"/pets/{id}": {},
+			"/pets/{petId}/": {},
+			"/pets/{petId}/photos": {}
+		}
+	}`), ver2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Issue{{
+		Path:    "/pets/{petId}/",
+		Message: "path template is ambiguous with /pets/{id}",
+	}}, ambiguousPathsRule{}.Check(doc))
+}
+
+func TestUnreachableDefinitionsRule(t *testing.T) {
+	doc, err := loads.Analyzed([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "unreachable", "version": "1.0"},
+		"paths": {
+			"/pets": {"get": {"responses": {"200": {
+				"description": "pets",
+				"schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}
+			}}}}
+		},
+		"definitions": {
+			"Pet": {"properties": {"tag": {"$ref": "#/definitions/Tag"}}},
+			"Tag": {"type": "string"},
+			"Owner": {"properties": {"pet": {"$ref": "#/definitions/Pet"}}}
+		}
+	}`), ver2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Issue{{
+		Message: "definition Owner is not referenced",
+	}}, unreachableDefinitionsRule{}.Check(doc))
+}
+
+func TestFailOnLintIssues(t *testing.T) {
+	_, err := NewRequestVerifier(testdata+sampleV2YAML, FailOnLintIssues, WithLogger(func(string, ...interface{}) {}))
+	assert.Regexp(t, "definition has [0-9]+ lint issue", err)
+
+	t.Run("without logger", func(t *testing.T) {
+		_, err := NewRequestVerifier(testdata+sampleV2YAML, FailOnLintIssues)
+		assert.Regexp(t, "definition has [0-9]+ lint issue", err)
+	})
+}
+
+func TestLintOnLoad(t *testing.T) {
+	t.Run("not logged by default", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log.SetOutput(buf)
+		defer log.SetOutput(os.Stderr)
+		_, err := NewRequestVerifier(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "revisor: lint")
+	})
+
+	t.Run("logged with configured logger", func(t *testing.T) {
+		var logged []string
+		_, err := NewRequestVerifier(testdata+sampleV2YAML, WithLogger(func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}))
+		require.NoError(t, err)
+		issues, err := LintDefinition(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		require.NotEmpty(t, issues)
+		assert.Contains(t, logged, "revisor: lint: "+issues[0].String())
+	})
+}
@@ -0,0 +1,87 @@
+package revisor
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-openapi/spec"
+)
+
+// locationExt is a response extension which declares the path template
+// Location header of the response must match, e.g.
+//
+//	'201':
+//	  description: created user
+//	  x-location: /users/{username}
+const locationExt = "x-location"
+
+// CheckLocation enables checks of Location header of documented 201 and 3xx
+// responses, except 304: the header must be present and its path must match
+// some path template of the definition, or the one declared with x-location
+// extension of the response. Locations on other hosts aren't matched.
+// Broken locations are reported as Violation with CodeInvalidLocation code.
+func CheckLocation(a *apiVerifier) {
+	a.opts.checkLocation = true
+}
+
+// verifyLocation checks Location header of the response
+func (a *apiVerifier) verifyLocation(req *http.Request, res *http.Response) error {
+	if res.StatusCode != http.StatusCreated &&
+		(res.StatusCode < 300 || res.StatusCode > 399 || res.StatusCode == http.StatusNotModified) {
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	if operation.Responses == nil {
+		return nil
+	}
+	response, ok := operation.Responses.StatusCodeResponses[res.StatusCode]
+	if !ok {
+		return nil
+	}
+	location := res.Header.Get("Location")
+	if location == "" {
+		return newViolation(CodeInvalidLocation, "Location header is required")
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return newViolation(CodeInvalidLocation, "Location is not a valid URI: "+location)
+	}
+	target := req.URL.ResolveReference(ref)
+	if ref.Host != "" && ref.Host != req.Host && ref.Host != req.URL.Host {
+		return nil
+	}
+	templates := a.locationTemplates(target)
+	if len(templates) == 0 {
+		return newViolation(CodeInvalidLocation, "no path template matches Location "+location)
+	}
+	documented, ok := responseLocation(&response)
+	if !ok {
+		return nil
+	}
+	for _, tmpl := range templates {
+		if tmpl == documented {
+			return nil
+		}
+	}
+	return newViolation(CodeInvalidLocation, "Location "+location+" doesn't match "+documented)
+}
+
+// locationTemplates returns path templates of any method matching the URL
+func (a *apiVerifier) locationTemplates(target *url.URL) []string {
+	var templates []string
+	for _, method := range httpMethods {
+		tmpl, _, ok := a.mapper.mapRequest(&http.Request{Method: method, URL: target, Header: http.Header{}})
+		if ok {
+			templates = append(templates, tmpl)
+		}
+	}
+	return templates
+}
+
+func responseLocation(response *spec.Response) (string, bool) {
+	tmpl, ok := response.Extensions[locationExt].(string)
+	return tmpl, ok
+}
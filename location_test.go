@@ -0,0 +1,113 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		status   int
+		location string
+		wantErr  string
+	}{
+		{
+			name:   "documented location",
+			method: "POST", path: "/v1/users",
+			status: 201, location: "/v1/users/alice",
+		},
+		{
+			name:   "relative location",
+			method: "POST", path: "/v1/users",
+			status: 201, location: "users/alice",
+		},
+		{
+			name:   "absolute location",
+			method: "POST", path: "/v1/users",
+			status: 201, location: "http://example.com/v1/users/alice",
+		},
+		{
+			name:   "missing location",
+			method: "POST", path: "/v1/users",
+			status:  201,
+			wantErr: "Location header is required",
+		},
+		{
+			name:   "undocumented path",
+			method: "POST", path: "/v1/users",
+			status: 201, location: "/v1/accounts/alice",
+			wantErr: "no path template matches Location /v1/accounts/alice",
+		},
+		{
+			name:   "other documented path",
+			method: "POST", path: "/v1/users",
+			status: 201, location: "/v1/teams/admins",
+			wantErr: "Location /v1/teams/admins doesn't match /users/{username}",
+		},
+		{
+			name:   "invalid location",
+			method: "POST", path: "/v1/users",
+			status: 201, location: "%",
+			wantErr: "Location is not a valid URI: %",
+		},
+		{
+			name:   "redirect",
+			method: "GET", path: "/v1/teams/admins",
+			status: 301, location: "/v1/users/admin",
+		},
+		{
+			name:   "redirect to other host",
+			method: "GET", path: "/v1/teams/admins",
+			status: 301, location: "https://teams.example.org/admins",
+		},
+		{
+			name:   "not modified",
+			method: "GET", path: "/v1/teams/admins",
+			status: 304,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"location.yaml", CheckLocation)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			if tt.location != "" {
+				rec.Header().Set("Location", tt.location)
+			}
+			rec.WriteHeader(tt.status)
+			err = v.verifier().verifyLocation(req, rec.Result())
+			wantCode := ""
+			if tt.wantErr != "" {
+				wantCode = CodeInvalidLocation
+			}
+			assertVerifyErr(t, err, tt.wantErr, wantCode)
+		})
+	}
+}
+
+func TestCheckLocation_Warning(t *testing.T) {
+	var warnings []string
+	_, err := New(testdata+"location.yaml", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "POST /users: x-location is not enforced")
+}
+
+func TestCheckLocation_VerifyResponse(t *testing.T) {
+	v, err := New(testdata+"location.yaml", CheckLocation)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(201)
+	err = v.VerifyResponse(rec.Result(), httptest.NewRequest("POST", "/v1/users", nil))
+	assertVerifyErr(t, err, "Location header is required", CodeInvalidLocation)
+}
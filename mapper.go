@@ -1,43 +1,157 @@
 package revisor
 
 import (
+	"context"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/gorilla/mux"
 )
 
 func newSimpleMapper(basePath string, templateMap map[string][]string) *simpleMapper {
+	return newBasePathsMapper([]string{basePath}, templateMap)
+}
 
-	router := mux.NewRouter().StrictSlash(true).PathPrefix(basePath).Subrouter()
-	mapper := &simpleMapper{router: router, basePath: basePath}
-	for k, v := range templateMap {
-		for _, tmpl := range v {
-			router.Methods(k).Path(tmpl)
+// newBasePathsMapper returns mapper matching templates prefixed with any of
+// base paths, which may contain variables, e.g. "/api/{version}"
+func newBasePathsMapper(basePaths []string, templateMap map[string][]string) *simpleMapper {
 
+	mapper := &simpleMapper{
+		router:    mux.NewRouter().StrictSlash(true),
+		templates: make(map[*mux.Route]string),
+	}
+	for _, basePath := range basePaths {
+		router := mapper.router.PathPrefix(basePath).Subrouter()
+		for k, v := range templateMap {
+			for _, tmpl := range v {
+				mapper.templates[router.Methods(k).Path(tmpl)] = tmpl
+			}
 		}
 	}
 	return mapper
 }
 
 type simpleMapper struct {
-	router   *mux.Router
-	basePath string
+	router *mux.Router
+	// templates maps routes to path templates they were configured for
+	templates map[*mux.Route]string
+	// cache is nil unless WithRoutingCache is set
+	cache *routeCache
+}
+
+// pinnedTemplateKey is a context key of the template match request is bound to
+const pinnedTemplateKey = contextKey("revisor.template")
+
+// templateMatch is a configured template matching the request
+type templateMatch struct {
+	tmpl string
+	vars map[string]string
+	// unmatched is set if no template matches the request
+	unmatched bool
+}
+
+// pinTemplate returns a shallow copy of the request bound to the template match,
+// so that mapRequest returns it instead of the first matching template
+func pinTemplate(r *http.Request, m templateMatch) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), pinnedTemplateKey, m))
 }
 
 // mapRequest returns configured template that matches HTTP
-// method and actual path.
+// method and actual path. If several templates match, the one
+// with the highest score is returned, see templateScore.
 // vars return parameter is a map of all variables set in
 // the path according to the matched template
 // isSet return parameter indicates if template was configured at all
 func (s *simpleMapper) mapRequest(r *http.Request) (tmpl string, vars map[string]string, isSet bool) {
+	if m, ok := r.Context().Value(pinnedTemplateKey).(templateMatch); ok {
+		return m.tmpl, m.vars, !m.unmatched
+	}
+	if s.cache != nil {
+		if m, ok, cached := s.cache.get(r); cached {
+			return m.tmpl, m.vars, ok
+		}
+	}
+	matches := s.candidates(r)
+	if len(matches) == 0 {
+		if s.cache != nil {
+			s.cache.put(r, templateMatch{}, false)
+		}
+		return "", nil, false
+	}
+	if s.cache != nil {
+		s.cache.put(r, matches[0], true)
+	}
+	return matches[0].tmpl, matches[0].vars, true
+}
+
+// methodMismatch reports if actual path matches some configured
+// template while HTTP method doesn't
+func (s *simpleMapper) methodMismatch(r *http.Request) bool {
 	match := mux.RouteMatch{}
-	if s.router.Match(r, &match) {
-		tmpl, err := match.Route.GetPathTemplate()
-		if err != nil {
-			return "", nil, false
+	return !s.router.Match(r, &match) && match.MatchErr == mux.ErrMethodMismatch
+}
+
+// candidates returns all configured templates matching HTTP method
+// and actual path ordered by score, so that the best match is the first
+func (s *simpleMapper) candidates(r *http.Request) []templateMatch {
+	var matches []templateMatch
+	_ = s.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		tmpl, ok := s.templates[route]
+		if !ok {
+			return nil
+		}
+		match := mux.RouteMatch{}
+		if route.Match(r, &match) {
+			matches = append(matches, templateMatch{tmpl: tmpl, vars: match.Vars})
+		}
+		return nil
+	})
+	sort.SliceStable(matches, func(i, j int) bool {
+		return compareTemplates(matches[i].tmpl, matches[j].tmpl) > 0
+	})
+	return matches
+}
+
+// Segment scores, literal segments are preferred over templated ones
+const (
+	variableSegmentScore = iota
+	mixedSegmentScore
+	literalSegmentScore
+)
+
+// templateScore scores every segment of the path template: literal segments,
+// like "mine", score higher than segments mixing literals with variables, like
+// "{id}.json", which in turn score higher than variable segments, like "{id}"
+func templateScore(tmpl string) []int {
+	segments := strings.Split(strings.Trim(tmpl, "/"), "/")
+	scores := make([]int, len(segments))
+	for i, segment := range segments {
+		switch {
+		case !strings.Contains(segment, "{"):
+			scores[i] = literalSegmentScore
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && strings.Count(segment, "{") == 1:
+			scores[i] = variableSegmentScore
+		default:
+			scores[i] = mixedSegmentScore
 		}
-		return strings.TrimPrefix(tmpl, s.basePath), match.Vars, true
 	}
-	return "", nil, false
+	return scores
+}
+
+// compareTemplates returns a positive number if template a is a better match than b,
+// a negative number if b is better and 0 if they are equal. Segment scores are compared
+// from left to right, so that the first more literal segment wins. Templates of the
+// same score are ordered alphabetically to keep matching deterministic.
+func compareTemplates(a, b string) int {
+	scoresA, scoresB := templateScore(a), templateScore(b)
+	for i := 0; i < len(scoresA) && i < len(scoresB); i++ {
+		if scoresA[i] != scoresB[i] {
+			return scoresA[i] - scoresB[i]
+		}
+	}
+	if len(scoresA) != len(scoresB) {
+		return len(scoresA) - len(scoresB)
+	}
+	return strings.Compare(b, a)
 }
@@ -42,3 +42,115 @@ func TestSimpleMapper_MapRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestSimpleMapper_MethodMismatch(t *testing.T) {
+
+	mapper := newSimpleMapper("", map[string][]string{
+		"GET": []string{"/path", "/path/{id}"},
+	})
+
+	tests := []struct {
+		name     string
+		request  *http.Request
+		mismatch bool
+	}{
+		{"tmpl found", httptest.NewRequest("GET", "/path", nil), false},
+		{"tmpl not configured", httptest.NewRequest("GET", "/", nil), false},
+		{"method not configured", httptest.NewRequest("POST", "/path/resource-id", nil), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.mismatch, mapper.methodMismatch(test.request))
+		})
+	}
+}
+
+func TestSimpleMapper_Candidates(t *testing.T) {
+
+	mapper := newSimpleMapper("/v1", map[string][]string{
+		"GET": []string{"/pets/{id}", "/pets/mine", "/owners"},
+	})
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		tmpls   []string
+	}{
+		{"overlapping templates", httptest.NewRequest("GET", "/v1/pets/mine", nil), []string{"/pets/mine", "/pets/{id}"}},
+		{"single template", httptest.NewRequest("GET", "/v1/pets/1", nil), []string{"/pets/{id}"}},
+		{"base path mismatch", httptest.NewRequest("GET", "/pets/1", nil), nil},
+		{"method mismatch", httptest.NewRequest("POST", "/v1/owners", nil), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var tmpls []string
+			for _, m := range mapper.candidates(test.request) {
+				tmpls = append(tmpls, m.tmpl)
+			}
+			assert.ElementsMatch(t, test.tmpls, tmpls)
+		})
+	}
+
+	pinned := pinTemplate(httptest.NewRequest("GET", "/v1/pets/mine", nil), templateMatch{tmpl: "/pets/{id}", vars: map[string]string{"id": "mine"}})
+	tmpl, vars, ok := mapper.mapRequest(pinned)
+	assert.True(t, ok)
+	assert.Equal(t, "/pets/{id}", tmpl)
+	assert.Equal(t, map[string]string{"id": "mine"}, vars)
+}
+
+func TestSimpleMapper_BestMatch(t *testing.T) {
+
+	templates := map[string][]string{
+		"GET": []string{"/pets/{id}", "/pets/mine", "/pets/{id}.json", "/{kind}/mine", "/pets/{id}/photos", "/pets/mine/photos"},
+	}
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		tmpl    string
+	}{
+		{"literal segment wins", httptest.NewRequest("GET", "/pets/mine", nil), "/pets/mine"},
+		{"mixed segment wins over variable", httptest.NewRequest("GET", "/pets/1.json", nil), "/pets/{id}.json"},
+		{"leftmost literal segment wins", httptest.NewRequest("GET", "/owners/mine", nil), "/{kind}/mine"},
+		{"nested literal segment wins", httptest.NewRequest("GET", "/pets/mine/photos", nil), "/pets/mine/photos"},
+		{"variable segment", httptest.NewRequest("GET", "/pets/1/photos", nil), "/pets/{id}/photos"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// routes are registered in random map order, so mapper is rebuilt
+			for i := 0; i < 10; i++ {
+				tmpl, _, ok := newSimpleMapper("", templates).mapRequest(test.request)
+				assert.True(t, ok)
+				assert.Equal(t, test.tmpl, tmpl)
+			}
+		})
+	}
+}
+
+func TestCompareTemplates(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"/pets/mine", "/pets/{id}", 1},
+		{"/pets/{id}", "/pets/mine", -1},
+		{"/pets/{id}.json", "/pets/{id}", 1},
+		{"/pets/{id}", "/{kind}/mine", 1},
+		{"/pets/{id}", "/pets/{petId}", 1},
+		{"/pets/{id}", "/pets/{id}", 0},
+	}
+	for _, test := range tests {
+		got := compareTemplates(test.a, test.b)
+		switch {
+		case test.want > 0:
+			assert.True(t, got > 0, "%s vs %s", test.a, test.b)
+		case test.want < 0:
+			assert.True(t, got < 0, "%s vs %s", test.a, test.b)
+		default:
+			assert.Equal(t, 0, got, "%s vs %s", test.a, test.b)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package revisor
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Metric names reported to MetricsSink
+const (
+	// MetricVerifications counts verified requests and responses
+	MetricVerifications = "verifications"
+	// MetricViolations counts requests and responses which failed verification
+	MetricViolations = "violations"
+	// MetricVerificationTime measures time spent on verification
+	MetricVerificationTime = "verification.time"
+	// MetricTransportErrors counts requests and responses which bodies
+	// couldn't be read, they are not counted as violations
+	MetricTransportErrors = "transport_errors"
+	// MetricDeprecatedCalls counts requests to operations marked as deprecated,
+	// so that progress of their sunset can be measured
+	MetricDeprecatedCalls = "deprecated_calls"
+)
+
+// codeInvalid tags violations which are not classified with a Violation code
+const codeInvalid = "invalid"
+
+// MetricsSink receives metrics of verification. Metrics are tagged with
+// "operation", which is operation ID or method and path template, and "kind",
+// which is either "request" or "response". Violations are tagged with "code",
+// which is a Violation code or "invalid" for generic validation errors.
+type MetricsSink interface {
+	// IncrCounter increments the counter by value
+	IncrCounter(name string, tags map[string]string, value int64)
+	// Timing records duration of an event
+	Timing(name string, tags map[string]string, d time.Duration)
+}
+
+// WithMetrics reports counters and timers of verification to sink
+func WithMetrics(sink MetricsSink) option {
+	return func(a *apiVerifier) {
+		a.opts.metrics = sink
+	}
+}
+
+// recordMetrics reports verification of the request or response started at
+// started, which failed with err unless it is nil
+func (a *apiVerifier) recordMetrics(kind string, req *http.Request, started time.Time, err error) {
+	if a.opts.metrics == nil {
+		return
+	}
+	operation := "unknown"
+	deprecated := false
+	if _, op, opErr := a.getOperationDef(req); opErr == nil {
+		operation = a.operationKey(req, op)
+		deprecated = op.Deprecated
+	}
+	tags := map[string]string{"operation": operation, "kind": kind}
+	a.opts.metrics.Timing(MetricVerificationTime, tags, a.opts.clock.Now().Sub(started))
+	a.opts.metrics.IncrCounter(MetricVerifications, tags, 1)
+	if deprecated && kind == "request" {
+		a.opts.metrics.IncrCounter(MetricDeprecatedCalls, tags, 1)
+	}
+	if err == nil {
+		return
+	}
+	code := codeInvalid
+	switch cause := errors.Cause(err).(type) {
+	case *TransportError:
+		a.opts.metrics.IncrCounter(MetricTransportErrors, tags, 1)
+		return
+	case *Violation:
+		code = cause.Code
+	}
+	a.opts.metrics.IncrCounter(MetricViolations, map[string]string{"operation": operation, "kind": kind, "code": code}, 1)
+}
+
+// StatsDSink is MetricsSink sending metrics over UDP in StatsD format with
+// DogStatsD tags, which is understood by Datadog agents
+type StatsDSink struct {
+	conn   net.Conn
+	prefix string
+}
+
+// NewStatsDSink returns StatsDSink sending metrics to StatsD agent listening
+// on addr, e.g. "127.0.0.1:8125". Metric names are prefixed with prefix followed
+// by a dot, unless it is empty.
+func NewStatsDSink(addr, prefix string) (*StatsDSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to statsd")
+	}
+	if prefix != "" {
+		prefix += "."
+	}
+	return &StatsDSink{conn: conn, prefix: prefix}, nil
+}
+
+// IncrCounter sends counter increment
+func (s *StatsDSink) IncrCounter(name string, tags map[string]string, value int64) {
+	s.send(name, strconv.FormatInt(value, 10)+"|c", tags)
+}
+
+// Timing sends timer value in milliseconds
+func (s *StatsDSink) Timing(name string, tags map[string]string, d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+	s.send(name, strconv.FormatFloat(ms, 'f', -1, 64)+"|ms", tags)
+}
+
+// Close closes connection to StatsD agent
+func (s *StatsDSink) Close() error {
+	return s.conn.Close()
+}
+
+// send writes a single metric, errors are ignored as metrics are sent
+// on best effort basis and must not affect verification
+func (s *StatsDSink) send(name, value string, tags map[string]string) {
+	line := s.prefix + name + ":" + value
+	if len(tags) != 0 {
+		names := make([]string, 0, len(tags))
+		for tag := range tags {
+			names = append(names, tag)
+		}
+		sort.Strings(names)
+		pairs := make([]string, len(names))
+		for i, tag := range names {
+			pairs[i] = tag + ":" + statsdTagReplacer.Replace(tags[tag])
+		}
+		line += "|#" + strings.Join(pairs, ",")
+	}
+	_, _ = s.conn.Write([]byte(line))
+}
+
+// statsdTagReplacer replaces characters separating parts of StatsD lines
+var statsdTagReplacer = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
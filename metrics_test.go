@@ -0,0 +1,98 @@
+package revisor
+
+import (
+	"net"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	mu       sync.Mutex
+	counters []string
+	timings  []string
+}
+
+func (s *recordingSink) IncrCounter(name string, tags map[string]string, value int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters = append(s.counters, metricLine(name, tags))
+}
+
+func (s *recordingSink) Timing(name string, tags map[string]string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timings = append(s.timings, metricLine(name, tags))
+}
+
+func metricLine(name string, tags map[string]string) string {
+	var pairs []string
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return name + " " + strings.Join(pairs, " ")
+}
+
+func TestWithMetrics(t *testing.T) {
+	sink := &recordingSink{}
+	verify, err := NewVerifier(testdata+sampleV2YAML, WithMetrics(sink))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/v2/pet/1", nil)
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(200)
+	_, _ = rec.WriteString(`{"name": 1}`)
+	assert.Error(t, verify(rec.Result(), req))
+
+	verifyRequest, err := NewRequestVerifier(testdata+sampleV2YAML, WithMetrics(sink))
+	require.NoError(t, err)
+	assert.Error(t, verifyRequest(httptest.NewRequest("GET", "/v2/unknown", nil)))
+
+	assert.Equal(t, []string{
+		"verifications kind=request operation=getPetById",
+		"verifications kind=response operation=getPetById",
+		"violations code=invalid kind=response operation=getPetById",
+		"verifications kind=request operation=unknown",
+		"violations code=undocumented_path kind=request operation=unknown",
+	}, sink.counters)
+	assert.Equal(t, []string{
+		"verification.time kind=request operation=getPetById",
+		"verification.time kind=response operation=getPetById",
+		"verification.time kind=request operation=unknown",
+	}, sink.timings)
+}
+
+func TestStatsDSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewStatsDSink(conn.LocalAddr().String(), "revisor")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	sink.IncrCounter(MetricViolations, map[string]string{"operation": "getPetById", "code": "a|b"}, 2)
+	sink.Timing(MetricVerificationTime, nil, 1500*time.Microsecond)
+
+	buf := make([]byte, 512)
+	for _, want := range []string{
+		"revisor.violations:2|c|#code:a_b,operation:getPetById",
+		"revisor.verification.time:1.5|ms",
+	} {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(buf[:n]))
+	}
+
+	_, err = NewStatsDSink("invalid address", "")
+	assert.Error(t, err)
+}
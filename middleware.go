@@ -0,0 +1,135 @@
+package revisor
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Environment variables FromEnv reads configuration from
+const (
+	// SpecEnv is the path of OpenAPI definition, it is required
+	SpecEnv = "REVISOR_SPEC"
+	// ModeEnv is either enforce, see DevelopmentMode, or shadow
+	ModeEnv = "REVISOR_MODE"
+	// SampleRateEnv is the fraction of verified responses, see WithSampleRate
+	SampleRateEnv = "REVISOR_SAMPLE_RATE"
+)
+
+// Modes of middleware configured with REVISOR_MODE environment variable
+const (
+	ModeEnforce = "enforce"
+	ModeShadow  = "shadow"
+)
+
+// NewMiddleware returns middleware which verifies requests before they are
+// passed to the handler and writes responses of the handler with
+// ValidatedResponseWriter. Valid requests are passed with decoded body and
+// parameters, see DecodedBody and RequestParams. Violations are logged, and
+// in development mode requests which violate OpenAPI definition are rejected,
+// see requestStatus, and responses which violate it are replaced. Requests
+// over rate limits, see EnforceRateLimits, are rejected in every mode.
+func NewMiddleware(definitionPath string, options ...option) (func(http.Handler) http.Handler, error) {
+	a, err := newInitializedVerifier(definitionPath, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create middleware")
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// the template is looked up once for both the request and the response
+			req = a.pinSatisfiedTemplate(req)
+			// the handler gets decoded body and parameters of valid requests,
+			// see DecodedBody and RequestParams
+			verified, err := a.verifyRequestWithContext(req)
+			if err == nil {
+				req = verified
+			}
+			if err != nil {
+				a.opts.logf("revisor: %s %s: invalid request: %v", req.Method, req.URL.Path, err)
+				if a.opts.developmentMode || a.opts.denyUndocumented && undocumented(err) {
+					http.Error(w, "request violates API definition: "+err.Error(), requestStatus(err))
+					return
+				}
+			}
+			// rate limits are enforced in every mode, only requests which
+			// are served are counted
+			if err := a.verifyRateLimit(req); err != nil && !undocumented(err) {
+				a.opts.logf("revisor: %s %s: rate limit: %v", req.Method, req.URL.Path, err)
+				if cause, ok := errors.Cause(err).(*Violation); ok && cause.Code == CodeRateLimitExceeded {
+					http.Error(w, "request exceeds rate limit: "+err.Error(), requestStatus(err))
+					return
+				}
+			}
+			vw := newValidatedResponseWriter(w, req, a)
+			next.ServeHTTP(vw, req)
+			if vw.committed {
+				return
+			}
+			if err := vw.Commit(); err != nil && !vw.logged {
+				a.opts.logf("revisor: %s %s: invalid response: %v", req.Method, req.URL.Path, err)
+			}
+		})
+	}, nil
+}
+
+// DenyUndocumented makes middleware reject requests to undocumented paths and
+// methods with 404 Not Found and 405 Method Not Allowed correspondingly, also
+// in shadow mode, where other requests which violate OpenAPI definition are
+// only logged.
+func DenyUndocumented(a *apiVerifier) {
+	a.opts.denyUndocumented = true
+}
+
+// undocumented reports if the request failed verification with err
+// as its path or method is not documented
+func undocumented(err error) bool {
+	cause, ok := errors.Cause(err).(*Violation)
+	return ok && (cause.Code == CodeUndocumentedPath || cause.Code == CodeUndocumentedMethod)
+}
+
+// requestStatus returns status code of the response rejecting the request
+// which failed verification with err
+func requestStatus(err error) int {
+	if cause, ok := errors.Cause(err).(*Violation); ok {
+		switch cause.Code {
+		case CodeUndocumentedPath:
+			return http.StatusNotFound
+		case CodeUndocumentedMethod:
+			return http.StatusMethodNotAllowed
+		case CodeRateLimitExceeded:
+			return http.StatusTooManyRequests
+		}
+	}
+	return http.StatusBadRequest
+}
+
+// FromEnv returns middleware configured with REVISOR_SPEC, REVISOR_MODE,
+// REVISOR_SAMPLE_RATE and REVISOR_PROFILE environment variables, so that
+// services share configuration templates. Variables override the profile,
+// and options override variables.
+func FromEnv(options ...option) (func(http.Handler) http.Handler, error) {
+	definitionPath := os.Getenv(SpecEnv)
+	if definitionPath == "" {
+		return nil, errors.New("failed to configure middleware: " + SpecEnv + " is not set")
+	}
+	envOptions := []option{WithProfile("")}
+	switch mode := os.Getenv(ModeEnv); mode {
+	case "":
+	case ModeEnforce:
+		envOptions = append(envOptions, DevelopmentMode)
+	case ModeShadow:
+		envOptions = append(envOptions, func(a *apiVerifier) { a.opts.developmentMode = false })
+	default:
+		return nil, errors.New("failed to configure middleware: unknown " + ModeEnv + " " + mode)
+	}
+	if s := os.Getenv(SampleRateEnv); s != "" {
+		rate, err := strconv.ParseFloat(s, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, errors.New("failed to configure middleware: " + SampleRateEnv + " must be a number from 0 to 1")
+		}
+		envOptions = append(envOptions, WithSampleRate(rate))
+	}
+	return NewMiddleware(definitionPath, append(envOptions, options...)...)
+}
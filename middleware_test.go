@@ -0,0 +1,205 @@
+package revisor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	var logged []string
+	logf := func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"username":"test-user"}`))
+	})
+
+	tests := []struct {
+		name   string
+		env    map[string]string
+		status int
+		logged bool
+		err    string
+	}{
+		{"spec is required", map[string]string{}, 0, false, "REVISOR_SPEC is not set"},
+		{
+			"unknown mode",
+			map[string]string{SpecEnv: testdata + sampleV2YAML, ModeEnv: "strict"},
+			0, false, "unknown REVISOR_MODE strict",
+		},
+		{
+			"invalid sample rate",
+			map[string]string{SpecEnv: testdata + sampleV2YAML, SampleRateEnv: "2"},
+			0, false, "REVISOR_SAMPLE_RATE must be a number from 0 to 1",
+		},
+		{
+			"shadow mode logs violations",
+			map[string]string{SpecEnv: testdata + sampleV2YAML, ModeEnv: ModeShadow},
+			http.StatusOK, true, "",
+		},
+		{
+			"enforce mode replaces responses",
+			map[string]string{SpecEnv: testdata + sampleV2YAML, ModeEnv: ModeEnforce},
+			http.StatusInternalServerError, true, "",
+		},
+		{
+			"mode overrides profile",
+			map[string]string{SpecEnv: testdata + sampleV2YAML, ModeEnv: ModeShadow, ProfileEnv: ProfileDevelopment},
+			http.StatusOK, true, "",
+		},
+		{
+			"unsampled responses are not verified",
+			map[string]string{SpecEnv: testdata + sampleV2YAML, ModeEnv: ModeEnforce, SampleRateEnv: "0"},
+			http.StatusOK, false, "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range []string{SpecEnv, ModeEnv, SampleRateEnv, ProfileEnv} {
+				require.NoError(t, os.Setenv(name, test.env[name]))
+				defer os.Unsetenv(name)
+			}
+			middleware, err := FromEnv(WithLogger(logf))
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+				return
+			}
+			require.NoError(t, err)
+			logged = nil
+
+			rec := httptest.NewRecorder()
+			middleware(handler).ServeHTTP(rec, httptest.NewRequest("GET", "/v2/user/testuser", nil))
+			assert.Equal(t, test.status, rec.Code)
+			if test.logged {
+				require.Len(t, logged, 1)
+				assert.Contains(t, logged[0], "GET /v2/user/testuser: invalid response")
+			} else {
+				assert.Empty(t, logged)
+			}
+		})
+	}
+}
+
+func TestNewMiddleware(t *testing.T) {
+	var logged []string
+	logf := func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+
+	tests := []struct {
+		name    string
+		options []option
+		method  string
+		path    string
+		status  int
+		handled bool
+		logged  string
+	}{
+		{"valid request", []option{DevelopmentMode}, "GET", "/v2/store/inventory", http.StatusOK, true, ""},
+		{"undocumented path", []option{DevelopmentMode}, "GET", "/v2/unknown", http.StatusNotFound, false, "GET /v2/unknown: invalid request"},
+		{"undocumented method", []option{DevelopmentMode}, "DELETE", "/v2/store/inventory", http.StatusMethodNotAllowed, false, "DELETE /v2/store/inventory: invalid request"},
+		{"invalid request", []option{DevelopmentMode}, "POST", "/v2/pet", http.StatusBadRequest, false, "POST /v2/pet: invalid request"},
+		{"invalid request in shadow mode", nil, "GET", "/v2/unknown", http.StatusOK, true, "GET /v2/unknown: invalid request"},
+		{"undocumented path denied", []option{DenyUndocumented}, "GET", "/v2/unknown", http.StatusNotFound, false, "GET /v2/unknown: invalid request"},
+		{"undocumented method denied", []option{DenyUndocumented}, "DELETE", "/v2/store/inventory", http.StatusMethodNotAllowed, false, "DELETE /v2/store/inventory: invalid request"},
+		{"invalid request with undocumented denied", []option{DenyUndocumented}, "POST", "/v2/pet", http.StatusOK, true, "POST /v2/pet: invalid request"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			middleware, err := NewMiddleware(testdata+sampleV2YAML, append(test.options, WithLogger(logf))...)
+			require.NoError(t, err)
+			logged = nil
+
+			handled := false
+			handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				handled = true
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			})
+			rec := httptest.NewRecorder()
+			middleware(handler).ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, test.handled, handled)
+			if test.logged != "" {
+				require.NotEmpty(t, logged)
+				assert.Contains(t, logged[0], test.logged)
+			} else {
+				assert.Empty(t, logged)
+			}
+		})
+	}
+
+	t.Run("latency violations are logged in development mode", func(t *testing.T) {
+		clock := NewManualClock(clockEpoch)
+		middleware, err := NewMiddleware(testdata+"slo.yaml", DevelopmentMode, WithLogger(logf), WithClock(clock))
+		require.NoError(t, err)
+		logged = nil
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			clock.Advance(time.Second)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v1/orders", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, logged, 1)
+		assert.Contains(t, logged[0], CodeSLOExceeded)
+	})
+
+	t.Run("decoded request is passed to the handler", func(t *testing.T) {
+		middleware, err := NewMiddleware(testdata+sampleV2YAML, WithLogger(logf))
+		require.NoError(t, err)
+		var body interface{}
+		var params Params
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ = DecodedBody(req.Context())
+			params, _ = RequestParams(req.Context())
+			w.WriteHeader(http.StatusOK)
+		}))
+		req := httptest.NewRequest("PUT", "/v2/user/testuser", strings.NewReader(`{"id":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, map[string]interface{}{"id": float64(1)}, body)
+		assert.Equal(t, map[string]string{"username": "testuser"}, params.Path)
+	})
+
+	t.Run("rate limits are enforced", func(t *testing.T) {
+		for _, test := range []struct {
+			name     string
+			options  []option
+			bodies   []string
+			statuses []int
+		}{
+			{"shadow mode", nil, []string{"", "", ""},
+				[]int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}},
+			{"rejected requests are not counted", []option{DevelopmentMode}, []string{"{}", "", "", ""},
+				[]int{http.StatusBadRequest, http.StatusOK, http.StatusOK, http.StatusTooManyRequests}},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				middleware, err := NewMiddleware(testdata+sampleV2YAML,
+					append(test.options, WithLogger(logf), EnforceRateLimits(NewMemoryRateLimitStore(), nil))...)
+				require.NoError(t, err)
+				handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{}`))
+				}))
+				var statuses []int
+				for _, body := range test.bodies {
+					rec := httptest.NewRecorder()
+					handler.ServeHTTP(rec, httptest.NewRequest("GET", "/v2/store/inventory", strings.NewReader(body)))
+					statuses = append(statuses, rec.Code)
+				}
+				assert.Equal(t, test.statuses, statuses)
+			})
+		}
+	})
+}
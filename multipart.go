@@ -0,0 +1,220 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/pkg/errors"
+)
+
+// multipartPartSchemaExt is an operation extension which maps names of
+// multipart request parts to schemas part contents are validated against
+const multipartPartSchemaExt = "x-multipart-part-schema"
+
+// DefaultMultipartBufferLimit is the default number of bytes of multipart
+// request body kept in memory, see WithMultipartBufferLimit
+const DefaultMultipartBufferLimit = 1 << 20
+
+// WithMultipartBufferLimit sets the number of bytes of non-seekable multipart
+// request bodies kept in memory to make the body readable again after parts
+// are verified, DefaultMultipartBufferLimit is used by default. Parts beyond
+// the limit aren't verified.
+func WithMultipartBufferLimit(limit int64) option {
+	return func(a *apiVerifier) {
+		a.opts.multipartLimit = limit
+	}
+}
+
+// verifyMultipartRequest validates parts of a multipart request against
+// schemas configured with x-multipart-part-schema operation extension.
+// Parts are decoded and validated one by one as they are read from the body.
+// Seekable bodies, e.g. files, are rewound afterwards. Consumed bytes of other
+// bodies are kept in memory, so that upstream calls will be able to read
+// the body again, but no more than the limit set with WithMultipartBufferLimit.
+// Once the limit is reached verification stops and the rest of the parts
+// is left unread and unverified.
+// handled return parameter reports if request was verified as multipart request
+func (a *apiVerifier) verifyMultipartRequest(req *http.Request) (handled bool, err error) {
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return false, nil
+	}
+	pathDef, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return true, err
+	}
+	schemas := a.partSchemas[operation]
+	if len(schemas) == 0 {
+		return false, nil
+	}
+	if req.Body == nil {
+		return true, errors.New("multipart request body is empty")
+	}
+
+	stream, restore := multipartStream(req, a.opts.multipartLimit)
+	defer restore()
+
+	seen := make(map[string]bool)
+	reader := multipart.NewReader(stream, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if stream.exceeded {
+			return true, nil
+		}
+		if err != nil {
+			return true, errors.Wrap(err, "failed to read multipart request")
+		}
+		name := part.FormName()
+		seen[name] = true
+		schema, ok := schemas[name]
+		if !ok {
+			continue
+		}
+		decoded, err := decodePart(part)
+		if stream.exceeded {
+			return true, nil
+		}
+		if err != nil {
+			return true, errors.Wrap(err, "failed to decode part "+name)
+		}
+		err = validate.AgainstSchema(schema, decoded, strfmt.Default)
+		if err != nil {
+			return true, errors.Wrap(err, "part "+name+" is not valid")
+		}
+	}
+	for _, param := range operationParams(pathDef, operation) {
+		if param.In == "formData" && param.Required && !seen[param.Name] {
+			return true, errors.New("required part is missing: " + param.Name)
+		}
+	}
+	return true, nil
+}
+
+// cappedReader reads the body keeping read bytes in memory up to the limit,
+// reads fail once the limit is reached, so that every byte read from
+// the body is kept
+type cappedReader struct {
+	body     io.Reader
+	consumed *bytes.Buffer
+	limit    int64
+	// seekable reports if the body is rewound instead of being kept in memory
+	seekable bool
+	// exceeded reports if reading stopped at the limit
+	exceeded bool
+}
+
+var errMultipartLimit = errors.New("multipart buffer limit is reached")
+
+func (r *cappedReader) Read(p []byte) (int, error) {
+	if r.seekable {
+		return r.body.Read(p)
+	}
+	left := r.limit - int64(r.consumed.Len())
+	if left <= 0 {
+		r.exceeded = true
+		return 0, errMultipartLimit
+	}
+	if int64(len(p)) > left {
+		p = p[:left]
+	}
+	n, err := r.body.Read(p)
+	r.consumed.Write(p[:n])
+	return n, err
+}
+
+// multipartStream returns reader of the request body parts are read from
+// and a function making the body readable again once parts are read
+func multipartStream(req *http.Request, limit int64) (*cappedReader, func()) {
+	if seeker, ok := req.Body.(io.Seeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			stream := &cappedReader{body: req.Body, seekable: true}
+			return stream, func() { _, _ = seeker.Seek(offset, io.SeekStart) }
+		}
+	}
+	stream := &cappedReader{body: req.Body, consumed: &bytes.Buffer{}, limit: limit}
+	body := req.Body
+	return stream, func() {
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(stream.consumed.Bytes()), body), body}
+	}
+}
+
+// initPartSchemas resolves schemas configured with x-multipart-part-schema
+// extension of every operation once, when the definition is loaded
+func (a *apiVerifier) initPartSchemas() error {
+	a.partSchemas = make(map[*spec.Operation]map[string]*spec.Schema)
+	swagger := a.doc.Spec()
+	for _, path := range sortedPaths(swagger) {
+		pathDef := swagger.Paths.Paths[path]
+		for _, method := range httpMethods {
+			operation := pathOperation(method, &pathDef)
+			if operation == nil {
+				continue
+			}
+			schemas, err := a.operationPartSchemas(operation)
+			if err != nil {
+				return errors.Wrap(err, method+" "+path)
+			}
+			if schemas != nil {
+				a.partSchemas[operation] = schemas
+			}
+		}
+	}
+	return nil
+}
+
+// operationPartSchemas returns schemas configured with x-multipart-part-schema
+// extension with references resolved against the API document
+func (a *apiVerifier) operationPartSchemas(operation *spec.Operation) (map[string]*spec.Schema, error) {
+	ext, ok := operation.Extensions[multipartPartSchemaExt]
+	if !ok {
+		return nil, nil
+	}
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read "+multipartPartSchemaExt)
+	}
+	schemas := make(map[string]*spec.Schema)
+	err = json.Unmarshal(raw, &schemas)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse "+multipartPartSchemaExt)
+	}
+	for name, schema := range schemas {
+		err = spec.ExpandSchema(schema, a.doc.Spec(), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to expand schema of part "+name)
+		}
+	}
+	return schemas, nil
+}
+
+// decodePart decodes JSON parts directly from the stream,
+// contents of other parts are treated as plain strings
+func decodePart(part *multipart.Part) (decoded interface{}, err error) {
+	if strings.Contains(part.Header.Get("Content-Type"), "json") {
+		err = json.NewDecoder(part).Decode(&decoded)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode json")
+		}
+		return decoded, nil
+	}
+	b, err := ioutil.ReadAll(part)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading part")
+	}
+	return string(b), nil
+}
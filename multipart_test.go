@@ -0,0 +1,167 @@
+package revisor
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVerifierV2_VerifyMultipartRequest(t *testing.T) {
+
+	a, err := newAPIVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	err = a.initMapper(a.doc.Spec().BasePath)
+	require.NoError(t, err)
+	err = a.initPartSchemas()
+	require.NoError(t, err)
+
+	type part struct {
+		name        string
+		contentType string
+		content     string
+	}
+	tests := []struct {
+		name  string
+		parts []part
+		err   string
+	}{
+		{
+			"valid parts",
+			[]part{
+				{"additionalMetadata", "text/plain", "metadata"},
+				{"tag", "application/json", `{"id":1,"name":"cat"}`},
+				{"file", "image/png", "binary-payload"},
+			},
+			"",
+		},
+		{
+			"invalid json part",
+			[]part{{"tag", "application/json", `{"id":"one"}`}},
+			"part tag is not valid",
+		},
+		{
+			"invalid text part",
+			[]part{
+				{"additionalMetadata", "text/plain", "metadata-is-too-long"},
+				{"tag", "application/json", `{"id":1}`},
+			},
+			"part additionalMetadata is not valid",
+		},
+		{
+			"fails to decode part",
+			[]part{{"tag", "application/json", "invalid-json"}},
+			"failed to decode part tag",
+		},
+		{
+			"required part is missing",
+			[]part{{"file", "image/png", "binary-payload"}},
+			"required part is missing: tag",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			for _, p := range test.parts {
+				header := textproto.MIMEHeader{}
+				header.Set("Content-Disposition", `form-data; name="`+p.name+`"`)
+				header.Set("Content-Type", p.contentType)
+				w, err := writer.CreatePart(header)
+				require.NoError(t, err)
+				_, err = w.Write([]byte(p.content))
+				require.NoError(t, err)
+			}
+			require.NoError(t, writer.Close())
+			payload := body.Bytes()
+
+			req, err := http.NewRequest("POST", "/v2/pet/1/uploadImage", bytes.NewReader(payload))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			err = a.verifyRequest(req)
+
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			restored, err := ioutil.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, payload, restored)
+		})
+	}
+
+	t.Run("seekable body is rewound", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="tag"`)
+		header.Set("Content-Type", "application/json")
+		w, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(`{"id":1}`))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		f, err := ioutil.TempFile("", "revisor-multipart")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+		defer f.Close()
+		_, err = f.Write(body.Bytes())
+		require.NoError(t, err)
+		_, err = f.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/v2/pet/1/uploadImage", nil)
+		require.NoError(t, err)
+		req.Body = f
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		assert.NoError(t, a.verifyRequest(req))
+		assert.Equal(t, f, req.Body, "body isn't replaced")
+		restored, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body.Bytes(), restored)
+	})
+
+	t.Run("parts beyond buffer limit are not verified", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for _, p := range [][2]string{{"file", strings.Repeat("x", 256)}, {"tag", `{"id":"one"}`}} {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", `form-data; name="`+p[0]+`"`)
+			header.Set("Content-Type", "application/json")
+			w, err := writer.CreatePart(header)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(p[1]))
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+		payload := body.Bytes()
+
+		defer func(limit int64) { a.opts.multipartLimit = limit }(a.opts.multipartLimit)
+		WithMultipartBufferLimit(128)(a)
+		req, err := http.NewRequest("POST", "/v2/pet/1/uploadImage", bytes.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		assert.NoError(t, a.verifyRequest(req))
+		restored, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, payload, restored)
+	})
+
+	t.Run("fails to read multipart body", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/v2/pet/1/uploadImage", &brokenReader{})
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+		err = a.verifyRequest(req)
+		assert.Regexp(t, "failed to read multipart request", err)
+	})
+}
@@ -0,0 +1,254 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// NegativeKind is a class of invalid request bodies
+type NegativeKind string
+
+// Classes of invalid request bodies
+const (
+	MissingRequired NegativeKind = "missing required"
+	WrongType       NegativeKind = "wrong type"
+	OutOfRange      NegativeKind = "out of range"
+	BadEnum         NegativeKind = "bad enum"
+)
+
+// NegativeCase is an invalid variant of a valid request body
+type NegativeCase struct {
+	Kind NegativeKind
+	// Pointer is JSON pointer of the invalid or missing value in the body
+	Pointer string
+	Body    interface{}
+	// Status is the documented status code the request is rejected with,
+	// 0 if the operation doesn't document client errors
+	Status int
+}
+
+// NegativeCases returns invalid variants of the request body of the operation
+// identified by operationID: every required property is removed, every value
+// gets a value of wrong type, values out of range and out of enum. Variants
+// are derived from a valid sample, so that each of them has exactly one violation
+// and servers can be asserted to reject every class with the right status code.
+func (v *Verifier) NegativeCases(operationID string) ([]NegativeCase, error) {
+	schema, err := v.SchemaAt(operationID, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	_, _, operation, _ := v.verifier().operationByID(operationID)
+	status := rejectionStatus(operation)
+
+	valid, err := copyValue(sampleValue(schema))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sample request body")
+	}
+	var cases []NegativeCase
+	for _, m := range negativeMutations(schema, valid, nil, 0) {
+		body, _ := copyValue(valid)
+		cases = append(cases, NegativeCase{
+			Kind:    m.kind,
+			Pointer: m.pointer(),
+			Body:    replaceAt(body, m.path, m.value, m.remove),
+			Status:  status,
+		})
+	}
+	return cases, nil
+}
+
+// negativeMutation replaces or removes the value at the path
+type negativeMutation struct {
+	kind   NegativeKind
+	path   []string
+	value  interface{}
+	remove bool
+}
+
+func (m negativeMutation) pointer() string {
+	pointer := ""
+	for _, token := range m.path {
+		pointer += "/" + escapePointerToken(token)
+	}
+	return pointer
+}
+
+// negativeMutations returns mutations of the value and values nested in it
+// which violate the schema
+func negativeMutations(schema *spec.Schema, value interface{}, path []string, depth int) []negativeMutation {
+	var mutations []negativeMutation
+	at := func(kind NegativeKind, value interface{}) {
+		mutations = append(mutations, negativeMutation{kind: kind, path: path, value: value})
+	}
+	if wrong, ok := wrongTypeValue(schema); ok {
+		at(WrongType, wrong)
+	}
+	if bad, ok := badEnumValue(schema); ok {
+		at(BadEnum, bad)
+	}
+	for _, out := range outOfRangeValues(schema, value) {
+		at(OutOfRange, out)
+	}
+	if depth >= maxSampleDepth {
+		return mutations
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, _, _ := declaredProperties(schema)
+		for _, name := range requiredProperties(schema) {
+			if _, ok := v[name]; ok {
+				mutations = append(mutations, negativeMutation{kind: MissingRequired, path: appendPath(path, name), remove: true})
+			}
+		}
+		for _, name := range sortedSchemaNames(props) {
+			if prop, ok := v[name]; ok {
+				mutations = append(mutations, negativeMutations(props[name], prop, appendPath(path, name), depth+1)...)
+			}
+		}
+	case []interface{}:
+		if len(v) != 0 && schema.Items != nil && schema.Items.Schema != nil {
+			mutations = append(mutations, negativeMutations(schema.Items.Schema, v[0], appendPath(path, "0"), depth+1)...)
+		}
+	}
+	return mutations
+}
+
+// wrongTypeValue returns a value of type not allowed by the schema
+func wrongTypeValue(schema *spec.Schema) (interface{}, bool) {
+	if len(schema.Type) == 0 {
+		return nil, false
+	}
+	for _, candidate := range []interface{}{"string", true, map[string]interface{}{}} {
+		if !matchesType(schema, candidate) {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
+
+// badEnumValue returns a string out of enum of the string schema
+func badEnumValue(schema *spec.Schema) (interface{}, bool) {
+	if len(schema.Enum) == 0 || !schema.Type.Contains("string") {
+		return nil, false
+	}
+	bad := "invalid"
+	for containsValue(schema.Enum, bad) {
+		bad += "_"
+	}
+	return bad, true
+}
+
+// outOfRangeValues returns values breaking range, length or item count constraints
+// of the schema, arrays out of range are derived from the valid value
+func outOfRangeValues(schema *spec.Schema, value interface{}) []interface{} {
+	var values []interface{}
+	switch {
+	case schema.Type.Contains("integer") || schema.Type.Contains("number"):
+		if schema.Minimum != nil {
+			values = append(values, *schema.Minimum-rangeStep(schema, schema.ExclusiveMinimum))
+		}
+		if schema.Maximum != nil {
+			values = append(values, *schema.Maximum+rangeStep(schema, schema.ExclusiveMaximum))
+		}
+	case schema.Type.Contains("string") && len(schema.Enum) == 0 && schema.Format == "":
+		if schema.MinLength != nil && *schema.MinLength > 0 {
+			values = append(values, strings.Repeat("a", int(*schema.MinLength-1)))
+		}
+		if schema.MaxLength != nil {
+			values = append(values, strings.Repeat("a", int(*schema.MaxLength+1)))
+		}
+	case schema.Type.Contains("array"):
+		items, _ := value.([]interface{})
+		if schema.MinItems != nil && *schema.MinItems > 0 && len(items) != 0 {
+			values = append(values, items[:*schema.MinItems-1])
+		}
+		if schema.MaxItems != nil && len(items) != 0 && !schema.UniqueItems {
+			tooMany := make([]interface{}, *schema.MaxItems+1)
+			for i := range tooMany {
+				tooMany[i] = items[0]
+			}
+			values = append(values, tooMany)
+		}
+	}
+	return values
+}
+
+// rangeStep returns distance from the bound to the closest value out of range
+func rangeStep(schema *spec.Schema, exclusive bool) float64 {
+	if exclusive {
+		return 0
+	}
+	if schema.Type.Contains("integer") {
+		return 1
+	}
+	return 0.001
+}
+
+// rejectionStatus returns documented status code of invalid requests:
+// 400 or 422 if documented, otherwise the lowest documented client error
+func rejectionStatus(operation *spec.Operation) int {
+	if operation == nil || operation.Responses == nil {
+		return 0
+	}
+	responses := operation.Responses.StatusCodeResponses
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnprocessableEntity} {
+		if _, ok := responses[status]; ok {
+			return status
+		}
+	}
+	var clientErrors []int
+	for status := range responses {
+		if status >= 400 && status < 500 {
+			clientErrors = append(clientErrors, status)
+		}
+	}
+	if len(clientErrors) == 0 {
+		return 0
+	}
+	sort.Ints(clientErrors)
+	return clientErrors[0]
+}
+
+// replaceAt replaces or removes the value at the path and returns the root value
+func replaceAt(root interface{}, path []string, value interface{}, remove bool) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	parent, _ := valueAt(root, path[:len(path)-1])
+	token := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if remove {
+			delete(p, token)
+		} else {
+			p[token] = value
+		}
+	case []interface{}:
+		if i, err := strconv.Atoi(token); err == nil && i < len(p) {
+			p[i] = value
+		}
+	}
+	return root
+}
+
+// copyValue returns a deep copy of the value as decoded from JSON
+func copyValue(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	err = json.Unmarshal(raw, &decoded)
+	return decoded, err
+}
+
+func appendPath(path []string, token string) []string {
+	return append(append([]string{}, path...), token)
+}
@@ -0,0 +1,85 @@
+package revisor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_NegativeCases(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	cases, err := v.NegativeCases("addPet")
+	require.NoError(t, err)
+
+	schema, err := v.SchemaAt("addPet", 0, "")
+	require.NoError(t, err)
+	got := make(map[string]bool)
+	for _, c := range cases {
+		got[string(c.Kind)+" "+c.Pointer] = true
+		assert.Equal(t, 405, c.Status)
+		assert.Error(t, validate.AgainstSchema(schema, c.Body, strfmt.Default), "%s %s", c.Kind, c.Pointer)
+	}
+	for _, want := range []string{
+		"wrong type ",
+		"missing required /name",
+		"missing required /photoUrls",
+		"wrong type /name",
+		"wrong type /id",
+		"bad enum /status",
+		"wrong type /category/name",
+	} {
+		assert.True(t, got[want], want)
+	}
+
+	t.Run("unknown operation", func(t *testing.T) {
+		_, err := v.NegativeCases("unknown")
+		assert.EqualError(t, err, "operation is not defined: unknown")
+	})
+}
+
+func TestOutOfRangeValues(t *testing.T) {
+	min, max := 1.0, 10.0
+
+	tests := []struct {
+		name   string
+		schema string
+		value  interface{}
+		want   []interface{}
+	}{
+		{name: "integer", schema: `{"type": "integer", "minimum": 1, "maximum": 10}`, want: []interface{}{min - 1, max + 1}},
+		{name: "exclusive", schema: `{"type": "number", "minimum": 1, "exclusiveMinimum": true}`, want: []interface{}{min}},
+		{name: "string", schema: `{"type": "string", "minLength": 2, "maxLength": 3}`,
+			want: []interface{}{"a", "aaaa"}},
+		{name: "array", schema: `{"type": "array", "minItems": 1, "maxItems": 1, "items": {"type": "string"}}`,
+			value: []interface{}{"x"}, want: []interface{}{[]interface{}{}, []interface{}{"x", "x"}}},
+		{name: "no constraints", schema: `{"type": "boolean"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &spec.Schema{}
+			require.NoError(t, json.Unmarshal([]byte(tt.schema), schema))
+			assert.Equal(t, tt.want, outOfRangeValues(schema, tt.value))
+		})
+	}
+}
+
+func TestRejectionStatus(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	for operationID, want := range map[string]int{
+		"addPet":       405,
+		"getPetById":   400,
+		"getInventory": 0,
+	} {
+		_, _, operation, _ := v.verifier().operationByID(operationID)
+		assert.Equal(t, want, rejectionStatus(operation), operationID)
+	}
+}
@@ -0,0 +1,253 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+)
+
+// pactSpecificationVersion is a version of Pact specification of exported files
+const pactSpecificationVersion = "2.0.0"
+
+// pact is a consumer-driven contract file of Pact specification v2 or v3
+type pact struct {
+	Consumer     pactParticipant   `json:"consumer"`
+	Provider     pactParticipant   `json:"provider"`
+	Interactions []pactInteraction `json:"interactions"`
+	Metadata     struct {
+		PactSpecification struct {
+			Version string `json:"version"`
+		} `json:"pactSpecification"`
+	} `json:"metadata"`
+}
+
+type pactParticipant struct {
+	Name string `json:"name"`
+}
+
+type pactInteraction struct {
+	Description string `json:"description"`
+	Request     struct {
+		Method  string            `json:"method"`
+		Path    string            `json:"path"`
+		Query   json.RawMessage   `json:"query,omitempty"`
+		Headers map[string]string `json:"headers,omitempty"`
+		Body    json.RawMessage   `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status  int               `json:"status"`
+		Headers map[string]string `json:"headers,omitempty"`
+		Body    json.RawMessage   `json:"body,omitempty"`
+	} `json:"response"`
+}
+
+// ReplayPact loads Pact contract file and verifies every interaction with
+// verify function, e.g. returned from NewVerifier, so that consumer contracts
+// are checked against OpenAPI definition of the provider. Errors of interactions
+// which failed verification are returned, second return parameter is an error
+// of loading the file.
+func ReplayPact(pactPath string, verify func(*http.Response, *http.Request) error) ([]*InteractionError, error) {
+	raw, err := swag.LoadFromFileOrHTTP(pactPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load pact")
+	}
+	p := &pact{}
+	err = json.Unmarshal(raw, p)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse pact")
+	}
+	var failed []*InteractionError
+	for i, interaction := range p.Interactions {
+		req, res, err := interaction.exchange()
+		if err == nil {
+			err = verify(res, req)
+		}
+		if err != nil {
+			failed = append(failed, &InteractionError{
+				Index:  i,
+				Method: interaction.Request.Method,
+				URL:    interaction.Request.Path,
+				Err:    err,
+			})
+		}
+	}
+	return failed, nil
+}
+
+// exchange builds request and response of the interaction
+func (i *pactInteraction) exchange() (*http.Request, *http.Response, error) {
+	query, err := pactQuery(i.Request.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+	rawURL := i.Request.Path
+	if query != "" {
+		rawURL += "?" + query
+	}
+	reqHeader := pactHeader(i.Request.Headers)
+	req, err := newCapturedRequest(RequestMeta{
+		Method: strings.ToUpper(i.Request.Method),
+		URL:    rawURL,
+		Header: reqHeader,
+	}, pactBody(i.Request.Body, reqHeader))
+	if err != nil {
+		return nil, nil, err
+	}
+	resHeader := pactHeader(i.Response.Headers)
+	res := newCapturedResponse(req, ResponseMeta{
+		StatusCode: i.Response.Status,
+		Header:     resHeader,
+	}, pactBody(i.Response.Body, resHeader))
+	return req, res, nil
+}
+
+// pactQuery returns encoded query, which is a string in Pact v2
+// and a map of values in Pact v3
+func pactQuery(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var query string
+	if json.Unmarshal(raw, &query) == nil {
+		return query, nil
+	}
+	values := url.Values{}
+	err := json.Unmarshal(raw, &values)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse query")
+	}
+	return values.Encode(), nil
+}
+
+func pactHeader(headers map[string]string) http.Header {
+	header := make(http.Header)
+	for k, v := range headers {
+		header.Set(k, v)
+	}
+	return header
+}
+
+// pactBody returns body contents, JSON bodies are kept as they are,
+// while strings are unquoted for other content types
+func pactBody(raw json.RawMessage, header http.Header) []byte {
+	if len(raw) == 0 || strings.Contains(header.Get("Content-Type"), "json") {
+		return raw
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []byte(s)
+	}
+	return raw
+}
+
+// PactRecorder verifies exchanges and records valid ones as Pact interactions,
+// so that observed traffic can be exported to consumer-driven contract tooling
+type PactRecorder struct {
+	verify func(*http.Response, *http.Request) error
+
+	mu   sync.Mutex
+	pact pact
+}
+
+// NewPactRecorder returns PactRecorder which verifies exchanges with
+// verify function, e.g. returned from NewVerifier
+func NewPactRecorder(consumer, provider string, verify func(*http.Response, *http.Request) error) *PactRecorder {
+	r := &PactRecorder{verify: verify}
+	r.pact.Consumer.Name = consumer
+	r.pact.Provider.Name = provider
+	r.pact.Metadata.PactSpecification.Version = pactSpecificationVersion
+	return r
+}
+
+// Verify verifies the exchange and records it if it is valid.
+// Request and response bodies are restored, so that they can be read again.
+// Nil request or response is reported as ErrNilInput.
+func (r *PactRecorder) Verify(res *http.Response, req *http.Request) error {
+	if res == nil || req == nil {
+		return ErrNilInput
+	}
+	reqBody, resBody, restore, err := captureBodies(res, req)
+	if err != nil {
+		return err
+	}
+	err = r.verify(res, req)
+	restore()
+	if err != nil {
+		return err
+	}
+
+	interaction := pactInteraction{Description: req.Method + " " + req.URL.Path + " " + res.Status}
+	interaction.Request.Method = req.Method
+	interaction.Request.Path = req.URL.Path
+	if req.URL.RawQuery != "" {
+		interaction.Request.Query, _ = json.Marshal(req.URL.RawQuery)
+	}
+	interaction.Request.Headers = flattenHeader(req.Header)
+	interaction.Request.Body = pactRawBody(reqBody, req.Header)
+	interaction.Response.Status = res.StatusCode
+	interaction.Response.Headers = flattenHeader(res.Header)
+	interaction.Response.Body = pactRawBody(resBody, res.Header)
+
+	r.mu.Lock()
+	r.pact.Interactions = append(r.pact.Interactions, interaction)
+	r.mu.Unlock()
+	return nil
+}
+
+// captureBodies reads bodies of the exchange, returned restore function
+// resets them, so that they can be read again after verification
+func captureBodies(res *http.Response, req *http.Request) (reqBody, resBody []byte, restore func(), err error) {
+	reqBody, err = readRequestBody(req)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to record request")
+	}
+	resBody, err = readResponseBody(res)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to record response")
+	}
+	restore = func() {
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+		res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	}
+	return reqBody, resBody, restore, nil
+}
+
+// Write writes recorded interactions to w as Pact contract file
+func (r *PactRecorder) Write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Wrap(writeJSON(w, r.pact), "failed to write pact")
+}
+
+func flattenHeader(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	flat := make(map[string]string, len(header))
+	for k, values := range header {
+		flat[k] = strings.Join(values, ", ")
+	}
+	return flat
+}
+
+// pactRawBody returns body as JSON value, bodies of other
+// content types are encoded as strings
+func pactRawBody(body []byte, header http.Header) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	var decoded interface{}
+	if strings.Contains(header.Get("Content-Type"), "json") && json.Unmarshal(body, &decoded) == nil {
+		return body
+	}
+	raw, _ := json.Marshal(string(body))
+	return raw
+}
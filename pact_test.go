@@ -0,0 +1,75 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayPact(t *testing.T) {
+	verify, err := NewVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	failed, err := ReplayPact(testdata+"pact.json", verify)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 1, failed[0].Index)
+	assert.Regexp(t, "(?s)interaction 1 GET /v2/user/testuser: .*id in body must be of type integer", failed[0].Error())
+
+	_, err = ReplayPact(testdata+"invalid.yaml", verify)
+	assert.Regexp(t, "failed to parse pact", err)
+	_, err = ReplayPact(testdata+"non-existing.json", verify)
+	assert.Regexp(t, "failed to load pact", err)
+}
+
+func TestPactRecorder(t *testing.T) {
+	verify, err := NewVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	recorder := NewPactRecorder("web", "petstore", verify)
+
+	record := func(body string) error {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		_, err := rec.WriteString(body)
+		require.NoError(t, err)
+		res := rec.Result()
+		err = recorder.Verify(res, httptest.NewRequest("GET", "/v2/user/testuser?verbose=true", nil))
+		restored, readErr := ioutil.ReadAll(res.Body)
+		require.NoError(t, readErr)
+		assert.Equal(t, body, string(restored))
+		return err
+	}
+	require.NoError(t, record(`{"id":1}`))
+	assert.Error(t, record(`{"id":"1"}`))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, recorder.Write(buf))
+	p := &pact{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), p))
+	assert.Equal(t, "petstore", p.Provider.Name)
+	require.Len(t, p.Interactions, 1)
+	assert.Equal(t, `"verbose=true"`, string(p.Interactions[0].Request.Query))
+	assert.JSONEq(t, `{"id":1}`, string(p.Interactions[0].Response.Body))
+
+	t.Run("exported pact replays", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "revisor")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "pact.json")
+		require.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+		failed, err := ReplayPact(path, verify)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+	})
+
+	assert.Equal(t, "hello", string(pactBody(pactRawBody([]byte("hello"), nil), nil)))
+	assert.True(t, strings.HasPrefix(string(pactRawBody([]byte("{"), nil)), `"`))
+}
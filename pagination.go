@@ -0,0 +1,187 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// paginationExt is an operation extension which describes pagination of the
+// operation following platform-wide conventions, e.g.
+//
+//	x-pagination:
+//	  style: cursor
+//	  maxLimit: 100
+//	  items: /data
+//	  next: /meta/next
+//	  total: /meta/total
+//
+// Style is either offset, with limit and offset query parameters, or cursor,
+// with limit and cursor query parameters. Names of parameters may be changed
+// with limit, offset and cursor properties. Items, next and total are JSON
+// pointers of the page items and required response fields correspondingly.
+const paginationExt = "x-pagination"
+
+// Pagination styles of x-pagination extension
+const (
+	paginationOffset = "offset"
+	paginationCursor = "cursor"
+)
+
+type pagination struct {
+	Style    string `json:"style"`
+	Limit    string `json:"limit"`
+	Offset   string `json:"offset"`
+	Cursor   string `json:"cursor"`
+	MaxLimit int64  `json:"maxLimit"`
+	Items    string `json:"items"`
+	Next     string `json:"next"`
+	Total    string `json:"total"`
+}
+
+// CheckPagination enables checks of pagination conventions of operations
+// declared with x-pagination extension: limit must be a positive integer not
+// greater than maxLimit, offset must be a non-negative integer, cursor must not
+// be empty, and successful responses must have the required fields with page
+// not larger than limit. Broken conventions are reported as Violation with
+// CodePagination code.
+func CheckPagination(a *apiVerifier) {
+	a.opts.checkPagination = true
+}
+
+func operationPagination(operation *spec.Operation) (*pagination, bool, error) {
+	ext, ok := operation.Extensions[paginationExt]
+	if !ok {
+		return nil, false, nil
+	}
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to read "+paginationExt)
+	}
+	p := pagination{Limit: "limit", Offset: "offset", Cursor: "cursor"}
+	err = json.Unmarshal(raw, &p)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to parse "+paginationExt)
+	}
+	if p.Style != paginationOffset && p.Style != paginationCursor {
+		return nil, false, errors.New("failed to parse " + paginationExt + ": unknown style " + p.Style)
+	}
+	return &p, true, nil
+}
+
+// verifyPaginationRequest checks pagination parameters of the request
+func (a *apiVerifier) verifyPaginationRequest(req *http.Request) error {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	p, ok, err := operationPagination(operation)
+	if err != nil || !ok {
+		return err
+	}
+	if _, err := p.limit(req); err != nil {
+		return err
+	}
+	query := req.URL.Query()
+	switch p.Style {
+	case paginationOffset:
+		if _, ok := query[p.Cursor]; ok && p.Cursor != p.Offset {
+			return newViolation(CodePagination, p.Cursor+" is not supported with offset pagination")
+		}
+		if value, ok := query[p.Offset]; ok {
+			offset, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil || offset < 0 {
+				return newViolation(CodePagination, p.Offset+" must be a non-negative integer")
+			}
+		}
+	case paginationCursor:
+		if _, ok := query[p.Offset]; ok && p.Cursor != p.Offset {
+			return newViolation(CodePagination, p.Offset+" is not supported with cursor pagination")
+		}
+		if value, ok := query[p.Cursor]; ok && value[0] == "" {
+			return newViolation(CodePagination, p.Cursor+" must not be empty")
+		}
+	}
+	return nil
+}
+
+// limit returns page size requested with limit query parameter,
+// which is maxLimit or 0 if it isn't set
+func (p *pagination) limit(req *http.Request) (int64, error) {
+	value, ok := req.URL.Query()[p.Limit]
+	if !ok {
+		return p.MaxLimit, nil
+	}
+	limit, err := strconv.ParseInt(value[0], 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, newViolation(CodePagination, p.Limit+" must be a positive integer")
+	}
+	if p.MaxLimit > 0 && limit > p.MaxLimit {
+		return 0, newViolation(CodePagination, p.Limit+" must not be greater than "+strconv.FormatInt(p.MaxLimit, 10))
+	}
+	return limit, nil
+}
+
+// verifyPaginationResponse checks pagination fields of decoded body of
+// successful response to the request
+func (a *apiVerifier) verifyPaginationResponse(req *http.Request, res *http.Response, decoded interface{}) error {
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	p, ok, err := operationPagination(operation)
+	if err != nil || !ok {
+		return err
+	}
+	for _, field := range []string{p.Next, p.Total} {
+		if field == "" {
+			continue
+		}
+		tokens, ok := pointerTokens(field)
+		if ok {
+			_, ok = valueAt(decoded, tokens)
+		}
+		if !ok {
+			return newViolation(CodePagination, "required field "+field+" is missing")
+		}
+	}
+	if p.Total != "" {
+		tokens, _ := pointerTokens(p.Total)
+		total, _ := valueAt(decoded, tokens)
+		if total, ok := total.(float64); !ok || total < 0 || total != float64(int64(total)) {
+			return newViolation(CodePagination, p.Total+" must be a non-negative integer")
+		}
+	}
+	if p.Items == "" {
+		return nil
+	}
+	items := decoded
+	if p.Items != "/" {
+		tokens, ok := pointerTokens(p.Items)
+		if ok {
+			items, ok = valueAt(decoded, tokens)
+		}
+		if !ok {
+			return newViolation(CodePagination, "required field "+p.Items+" is missing")
+		}
+	}
+	list, ok := items.([]interface{})
+	if !ok {
+		return newViolation(CodePagination, p.Items+" must be an array")
+	}
+	limit, err := p.limit(req)
+	if err != nil || limit == 0 {
+		return nil
+	}
+	if int64(len(list)) > limit {
+		return newViolation(CodePagination, "page has "+strconv.Itoa(len(list))+
+			" items, which is more than limit of "+strconv.FormatInt(limit, 10))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPagination(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		body        string
+		wantReqErr  bool
+		wantRespErr bool
+	}{
+		{
+			name: "offset",
+			url:  "/v1/orders?limit=2&offset=4",
+			body: `{"data": [{}, {}], "meta": {"total": 6}}`,
+		},
+		{
+			name: "default limit",
+			url:  "/v1/orders",
+			body: `{"data": [{}], "meta": {"total": 1}}`,
+		},
+		{
+			name:       "limit is not positive",
+			url:        "/v1/orders?limit=0",
+			wantReqErr: true,
+		},
+		{
+			name:       "limit over maximum",
+			url:        "/v1/orders?limit=3",
+			wantReqErr: true,
+		},
+		{
+			name:       "negative offset",
+			url:        "/v1/orders?offset=-1",
+			wantReqErr: true,
+		},
+		{
+			name:       "cursor with offset pagination",
+			url:        "/v1/orders?cursor=abc",
+			wantReqErr: true,
+		},
+		{
+			name:        "missing total",
+			url:         "/v1/orders",
+			body:        `{"data": []}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "invalid total",
+			url:         "/v1/orders",
+			body:        `{"data": [], "meta": {"total": 1.5}}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "items are not an array",
+			url:         "/v1/orders",
+			body:        `{"data": {}, "meta": {"total": 0}}`,
+			wantRespErr: true,
+		},
+		{
+			name:        "page over limit",
+			url:         "/v1/orders?limit=1",
+			body:        `{"data": [{}, {}], "meta": {"total": 2}}`,
+			wantRespErr: true,
+		},
+		{
+			name: "cursor",
+			url:  "/v1/events?limit=10&after=abc",
+			body: `{"data": [{}], "next": "def"}`,
+		},
+		{
+			name: "last page",
+			url:  "/v1/events?after=def",
+			body: `{"data": [], "next": null}`,
+		},
+		{
+			name:        "missing next",
+			url:         "/v1/events",
+			body:        `{"data": []}`,
+			wantRespErr: true,
+		},
+		{
+			name:       "empty cursor",
+			url:        "/v1/events?after=",
+			wantReqErr: true,
+		},
+		{
+			name:       "offset with cursor pagination",
+			url:        "/v1/events?offset=10",
+			wantReqErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"pagination.yaml", CheckPagination)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			err = v.VerifyRequest(req)
+			if tt.wantReqErr {
+				assertVerifyErr(t, err, "", CodePagination)
+				return
+			}
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			rec.Body = bytes.NewBufferString(tt.body)
+			err = v.VerifyResponse(rec.Result(), req)
+			if tt.wantRespErr {
+				assertVerifyErr(t, err, "", CodePagination)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckPagination_FailedResponse(t *testing.T) {
+	v, err := New(testdata+"pagination.yaml", CheckPagination)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(400)
+	req := httptest.NewRequest("GET", "/v1/orders", nil)
+	err = v.verifier().verifyPaginationResponse(req, rec.Result(), map[string]interface{}{})
+	assert.NoError(t, err, "pagination of failed responses is not checked")
+}
+
+func TestCheckPagination_Warning(t *testing.T) {
+	var warnings []string
+	_, err := New(testdata+"pagination.yaml", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "GET /orders: x-pagination is not enforced")
+}
@@ -0,0 +1,66 @@
+package revisor
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// RequestMeta describes a request captured outside of net/http,
+// e.g. by load testing tools
+type RequestMeta struct {
+	Method string
+	URL    string
+	Header http.Header
+}
+
+// ResponseMeta describes a response captured outside of net/http
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+}
+
+// Bodies holds request and response bodies of a captured exchange
+type Bodies struct {
+	Request  []byte
+	Response []byte
+}
+
+// ValidatePair verifies captured request and response with verify function,
+// e.g. returned from NewVerifier. It is lightweight enough to be called from
+// load testing extensions, so that performance runs check contract conformance.
+func ValidatePair(verify func(*http.Response, *http.Request) error, reqMeta RequestMeta, resMeta ResponseMeta, bodies Bodies) error {
+	req, err := newCapturedRequest(reqMeta, bodies.Request)
+	if err != nil {
+		return err
+	}
+	return verify(newCapturedResponse(req, resMeta, bodies.Response), req)
+}
+
+func newCapturedRequest(meta RequestMeta, body []byte) (*http.Request, error) {
+	req, err := http.NewRequest(meta.Method, meta.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	for k, values := range meta.Header {
+		req.Header[http.CanonicalHeaderKey(k)] = values
+	}
+	return req, nil
+}
+
+func newCapturedResponse(req *http.Request, meta ResponseMeta, body []byte) *http.Response {
+	res := &http.Response{
+		Status:        http.StatusText(meta.StatusCode),
+		StatusCode:    meta.StatusCode,
+		Header:        make(http.Header),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	for k, values := range meta.Header {
+		res.Header[http.CanonicalHeaderKey(k)] = values
+	}
+	return res
+}
@@ -0,0 +1,29 @@
+package revisor
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePair(t *testing.T) {
+	verifier, err := NewVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	reqMeta := RequestMeta{Method: "GET", URL: "http://petstore.swagger.io/v2/user/testuser"}
+	resMeta := ResponseMeta{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"content-type": []string{"application/json"}},
+	}
+
+	err = ValidatePair(verifier, reqMeta, resMeta, Bodies{Response: []byte(`{"id":1}`)})
+	assert.NoError(t, err)
+
+	err = ValidatePair(verifier, reqMeta, resMeta, Bodies{Response: []byte(`{"username":"testuser"}`)})
+	assert.Regexp(t, "id in body is required", err)
+
+	err = ValidatePair(verifier, RequestMeta{Method: "GET", URL: ":invalid"}, resMeta, Bodies{})
+	assert.Regexp(t, "failed to build request", err)
+}
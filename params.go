@@ -0,0 +1,122 @@
+package revisor
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// parameterValues returns raw values of non-body parameter found in the request
+func parameterValues(param *spec.Parameter, req *http.Request, vars map[string]string) ([]string, bool) {
+	switch param.In {
+	case "path":
+		v, ok := vars[param.Name]
+		return []string{v}, ok
+	case "query":
+		v, ok := req.URL.Query()[param.Name]
+		return v, ok
+	case "header":
+		v, ok := req.Header[http.CanonicalHeaderKey(param.Name)]
+		return v, ok
+	}
+	return nil, false
+}
+
+// convertParameter converts raw values of the parameter to the declared type
+func convertParameter(param *spec.Parameter, values []string) (interface{}, error) {
+	if param.Type == "array" {
+		if param.CollectionFormat != "multi" && len(values) != 0 {
+			values = splitCollection(values[0], param.CollectionFormat)
+		}
+		return convertItems(param.Items, values)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return convertSimple(param.Type, values[0])
+}
+
+func convertItems(items *spec.Items, values []string) (interface{}, error) {
+	converted := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if items == nil {
+			converted = append(converted, v)
+			continue
+		}
+		var value interface{}
+		var err error
+		if items.Type == "array" {
+			value, err = convertItems(items.Items, splitCollection(v, items.CollectionFormat))
+		} else {
+			value, err = convertSimple(items.Type, v)
+		}
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, value)
+	}
+	return converted, nil
+}
+
+func convertSimple(typ, value string) (interface{}, error) {
+	switch typ {
+	case "integer":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.New(strconv.Quote(value) + " is not an integer")
+		}
+		return i, nil
+	case "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.New(strconv.Quote(value) + " is not a number")
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New(strconv.Quote(value) + " is not a boolean")
+		}
+		return b, nil
+	}
+	return value, nil
+}
+
+func splitCollection(value, format string) []string {
+	if value == "" {
+		return []string{}
+	}
+	switch format {
+	case "ssv":
+		return strings.Split(value, " ")
+	case "tsv":
+		return strings.Split(value, "\t")
+	case "pipes":
+		return strings.Split(value, "|")
+	}
+	return strings.Split(value, ",")
+}
+
+// joinCollection is the inverse of splitCollection
+func joinCollection(values []string, format string) string {
+	switch format {
+	case "ssv":
+		return strings.Join(values, " ")
+	case "tsv":
+		return strings.Join(values, "\t")
+	case "pipes":
+		return strings.Join(values, "|")
+	}
+	return strings.Join(values, ",")
+}
+
+// operationParams returns parameters of the path item followed by parameters
+// of the operation in a new slice, so that the shared definition isn't modified
+func operationParams(pathItem *spec.PathItem, operation *spec.Operation) []spec.Parameter {
+	params := make([]spec.Parameter, 0, len(pathItem.Parameters)+len(operation.Parameters))
+	params = append(params, pathItem.Parameters...)
+	return append(params, operation.Parameters...)
+}
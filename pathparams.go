@@ -0,0 +1,60 @@
+package revisor
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/pkg/errors"
+)
+
+// verifyPathParams validates variables of the request path against path
+// parameters of the operation, converted to their declared types
+func (a *apiVerifier) verifyPathParams(req *http.Request) error {
+	pathDef, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	params := pathParams(pathDef, operation)
+	if len(params) == 0 {
+		return nil
+	}
+	_, vars, _ := a.mapper.mapRequest(req)
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		param := params[name]
+		value, ok := vars[name]
+		if !ok {
+			continue
+		}
+		converted, err := convertParameter(param, []string{value})
+		if err != nil {
+			return errors.Wrap(err, "failed to convert path parameter "+name)
+		}
+		err = validate.AgainstSchema(parameterSchema(&param.SimpleSchema, &param.CommonValidations), converted, strfmt.Default)
+		if err != nil {
+			return errors.Wrap(err, "path parameter "+name+" is not valid")
+		}
+	}
+	return nil
+}
+
+// pathParams returns path parameters of the operation by name, parameters
+// of the operation override parameters of the path item
+func pathParams(pathDef *spec.PathItem, operation *spec.Operation) map[string]*spec.Parameter {
+	params := make(map[string]*spec.Parameter)
+	for _, list := range [][]spec.Parameter{pathDef.Parameters, operation.Parameters} {
+		for i := range list {
+			if list[i].In == "path" {
+				params[list[i].Name] = &list[i]
+			}
+		}
+	}
+	return params
+}
@@ -0,0 +1,43 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyPathParams(t *testing.T) {
+	verify, err := NewRequestVerifier(testdata + "pathparams.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		err    string
+	}{
+		{"valid parameters", "GET", "/v1/users/42/posts/draft", ""},
+		{"zero is valid", "GET", "/v1/users/0/posts/draft", ""},
+		{"not an integer", "GET", "/v1/users/abc/posts/draft", `failed to convert path parameter id: "abc" is not an integer`},
+		{"exceeds maximum", "GET", "/v1/users/1000/posts/draft", "(?s)path parameter id is not valid: .*less than or equal to 999"},
+		{"not in enum", "GET", "/v1/users/42/posts/deleted", "(?s)path parameter status is not valid: .*should be one of"},
+		{"path item parameter", "GET", "/v1/tags/ab", ""},
+		{"pattern of path item parameter", "GET", "/v1/tags/AB", "(?s)path parameter tag is not valid: .*should match"},
+		{"operation overrides path item parameter", "DELETE", "/v1/tags/AB", ""},
+		{"array parameter", "GET", "/v1/points/1,2,3", ""},
+		{"array item of wrong type", "GET", "/v1/points/1,a", `failed to convert path parameter ids: "a" is not an integer`},
+		{"too many array items", "GET", "/v1/points/1,2,3,4", "(?s)path parameter ids is not valid: .*at most 3 items"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := verify(httptest.NewRequest(test.method, test.url, nil))
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package revisor
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// piiExt is an extension of schemas, parameters and headers which classifies
+// personal data they hold, either with a category or true, e.g.
+//
+//	birthDate:
+//	  type: string
+//	  format: date
+//	  x-pii: birth date
+const piiExt = "x-pii"
+
+// maxPIIDepth limits how deep nested schemas are classified,
+// so that recursive definitions are not followed forever
+const maxPIIDepth = 32
+
+// sensitiveFormats are formats classifying values as sensitive
+var sensitiveFormats = map[string]bool{"password": true, "email": true}
+
+// SensitiveField describes a field of an operation which accepts or returns
+// personal or otherwise sensitive data
+type SensitiveField struct {
+	Method string
+	Path   string
+	// Response is the status code of the response, or default,
+	// and is empty for request fields
+	Response string
+	// In is body, a parameter location, or header for response headers
+	In string
+	// Name is the name of parameter or header, or JSON pointer of the body
+	// field, in which * stands for any array item or additional property
+	Name string
+	// Category is x-pii category, pii if it's not given, format of the value
+	// for password and email formats, or sensitive for x-sensitive values
+	Category string
+}
+
+func (f SensitiveField) String() string {
+	s := f.Method + " " + f.Path + " "
+	if f.Response == "" {
+		s += "request "
+	} else {
+		s += "response " + f.Response + " "
+	}
+	return s + f.In + " " + f.Name + ": " + f.Category
+}
+
+// PIIReport loads OpenAPI definition and returns fields of its operations
+// classified as sensitive by formats, x-pii and x-sensitive extensions,
+// ordered by path, method and response
+func PIIReport(definitionPath string) ([]SensitiveField, error) {
+	a, err := newAPIVerifier(definitionPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build PII report")
+	}
+	return a.sensitiveFields(), nil
+}
+
+// SensitiveFields returns fields of operations of the definition classified
+// as sensitive the same way PIIReport does
+func (v *Verifier) SensitiveFields() []SensitiveField {
+	return v.verifier().sensitiveFields()
+}
+
+func (a *apiVerifier) sensitiveFields() []SensitiveField {
+	var fields []SensitiveField
+	swagger := a.doc.Spec()
+	for _, path := range sortedPaths(swagger) {
+		pathItem := swagger.Paths.Paths[path]
+		for _, method := range httpMethods {
+			operation := pathOperation(method, &pathItem)
+			if operation == nil {
+				continue
+			}
+			add := func(response, in, name, category string) {
+				fields = append(fields, SensitiveField{
+					Method: method, Path: path, Response: response, In: in, Name: name, Category: category,
+				})
+			}
+			for _, param := range operationParams(&pathItem, operation) {
+				if param.In == "body" {
+					classifySchema(param.Schema, "", 0, func(pointer, category string) {
+						add("", "body", bodyField(pointer), category)
+					})
+				} else if category, ok := classify(param.Format, param.Extensions); ok {
+					add("", param.In, param.Name, category)
+				}
+			}
+			for _, r := range operationResponses(operation) {
+				for _, name := range sortedHeaders(r.response.Headers) {
+					header := r.response.Headers[name]
+					if category, ok := classify(header.Format, header.Extensions); ok {
+						add(r.status, "header", name, category)
+					}
+				}
+				classifySchema(r.response.Schema, "", 0, func(pointer, category string) {
+					add(r.status, "body", bodyField(pointer), category)
+				})
+			}
+		}
+	}
+	return fields
+}
+
+type statusResponse struct {
+	status   string
+	response spec.Response
+}
+
+// operationResponses returns responses of the operation ordered by status
+// code, followed by default response
+func operationResponses(operation *spec.Operation) []statusResponse {
+	if operation.Responses == nil {
+		return nil
+	}
+	statuses := make([]int, 0, len(operation.Responses.StatusCodeResponses))
+	for status := range operation.Responses.StatusCodeResponses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	var responses []statusResponse
+	for _, status := range statuses {
+		responses = append(responses, statusResponse{strconv.Itoa(status), operation.Responses.StatusCodeResponses[status]})
+	}
+	if operation.Responses.Default != nil {
+		responses = append(responses, statusResponse{"default", *operation.Responses.Default})
+	}
+	return responses
+}
+
+// classifySchema calls found for the schema and its nested schemas
+// classified as sensitive with their JSON pointers
+func classifySchema(schema *spec.Schema, pointer string, depth int, found func(pointer, category string)) {
+	if schema == nil || depth > maxPIIDepth {
+		return
+	}
+	if category, ok := classify(schema.Format, schema.Extensions); ok {
+		found(pointer, category)
+	} else if isSensitive(schema) {
+		found(pointer, "sensitive")
+	}
+	for i := range schema.AllOf {
+		classifySchema(&schema.AllOf[i], pointer, depth+1, found)
+	}
+	for _, name := range sortedSchemaProperties(schema.Properties) {
+		prop := schema.Properties[name]
+		classifySchema(&prop, pointer+"/"+escapePointerToken(name), depth+1, found)
+	}
+	if schema.AdditionalProperties != nil {
+		classifySchema(schema.AdditionalProperties.Schema, pointer+"/*", depth+1, found)
+	}
+	if schema.Items != nil {
+		classifySchema(schema.Items.Schema, pointer+"/*", depth+1, found)
+		for i := range schema.Items.Schemas {
+			classifySchema(&schema.Items.Schemas[i], pointer+"/"+strconv.Itoa(i), depth+1, found)
+		}
+	}
+}
+
+// classify returns category of a value of the format with the extensions
+func classify(format string, extensions spec.Extensions) (string, bool) {
+	switch pii := extensions[piiExt].(type) {
+	case string:
+		if pii != "" {
+			return pii, true
+		}
+	case bool:
+		if pii {
+			return "pii", true
+		}
+	}
+	if sensitiveFormats[format] {
+		return format, true
+	}
+	return "", false
+}
+
+func sortedSchemaProperties(properties map[string]spec.Schema) []string {
+	names := make(map[string]bool, len(properties))
+	for name := range properties {
+		names[name] = true
+	}
+	return sortedKeys(names)
+}
+
+func sortedHeaders(headers map[string]spec.Header) []string {
+	names := make(map[string]bool, len(headers))
+	for name := range headers {
+		names[name] = true
+	}
+	return sortedKeys(names)
+}
@@ -0,0 +1,51 @@
+package revisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPIIReport(t *testing.T) {
+	fields, err := PIIReport(testdata + "pii.yaml")
+	require.NoError(t, err)
+
+	var report []string
+	for _, field := range fields {
+		report = append(report, field.String())
+	}
+	assert.Equal(t, []string{
+		"GET /customers request query email: email",
+		"GET /customers response 200 header X-Session: session",
+		"GET /customers response 200 body /*/birthDate: birth date",
+		"GET /customers response 200 body /*/contacts/*: email",
+		"GET /customers response 200 body /*/name: pii",
+		"GET /customers response 200 body /*/token: sensitive",
+		"POST /customers request body /birthDate: birth date",
+		"POST /customers request body /contacts/*: email",
+		"POST /customers request body /name: pii",
+		"POST /customers request body /token: sensitive",
+		"POST /customers request body /password: password",
+		"POST /customers response 201 body /birthDate: birth date",
+		"POST /customers response 201 body /contacts/*: email",
+		"POST /customers response 201 body /name: pii",
+		"POST /customers response 201 body /token: sensitive",
+	}, report)
+}
+
+func TestPIIReport_InvalidDefinition(t *testing.T) {
+	_, err := PIIReport(testdata + "missing.yaml")
+	assert.Error(t, err)
+}
+
+func TestVerifier_SensitiveFields(t *testing.T) {
+	v, err := New(testdata + "pii.yaml")
+	require.NoError(t, err)
+
+	fields := v.SensitiveFields()
+	require.NotEmpty(t, fields)
+	assert.Equal(t, SensitiveField{
+		Method: "GET", Path: "/customers", In: "query", Name: "email", Category: "email",
+	}, fields[0])
+}
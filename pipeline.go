@@ -0,0 +1,112 @@
+package revisor
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// StageContext describes the exchange which body passes through the pipeline
+type StageContext struct {
+	Request *http.Request
+	// Response is nil while the request is verified
+	Response *http.Response
+	// Path is the path template matching the request
+	Path      string
+	Operation *spec.Operation
+}
+
+// Stage is a custom step of the verification pipeline: request and response
+// are matched to the operation, their bodies are read, decoded, validated
+// against schema and the result is reported. Every function of the stage
+// is optional, so that stages can hook into any step, e.g. decrypt payload
+// before decoding or strip envelope before validation.
+type Stage struct {
+	// Name identifies the stage in errors
+	Name string
+	// Raw transforms raw body before it is decoded
+	Raw func(ctx *StageContext, body []byte) ([]byte, error)
+	// Decoded transforms decoded body before it is validated
+	Decoded func(ctx *StageContext, body interface{}) (interface{}, error)
+	// Report inspects the result of verification, which is nil if it
+	// succeeded, and returns the result to report instead
+	Report func(ctx *StageContext, err error) error
+}
+
+// WithStages appends stages to the verification pipeline,
+// stages run in the order they are added
+func WithStages(stages ...Stage) option {
+	return func(a *apiVerifier) {
+		a.opts.stages = append(a.opts.stages, stages...)
+	}
+}
+
+// stageContext returns context of the exchange, res is nil for requests
+func (a *apiVerifier) stageContext(req *http.Request, res *http.Response) *StageContext {
+	ctx := &StageContext{Request: req, Response: res}
+	ctx.Path, _, _ = a.mapper.mapRequest(req)
+	if _, operation, err := a.getOperationDef(req); err == nil {
+		ctx.Operation = operation
+	}
+	return ctx
+}
+
+// rawStages passes raw body through stages
+func (a *apiVerifier) rawStages(req *http.Request, res *http.Response, body []byte) ([]byte, error) {
+	var ctx *StageContext
+	for _, stage := range a.opts.stages {
+		if stage.Raw == nil {
+			continue
+		}
+		if err := checkBudget(req); err != nil {
+			return nil, err
+		}
+		if ctx == nil {
+			ctx = a.stageContext(req, res)
+		}
+		var err error
+		body, err = stage.Raw(ctx, body)
+		if err != nil {
+			return nil, errors.Wrap(err, "stage "+stage.Name+" failed")
+		}
+	}
+	return body, nil
+}
+
+// decodedStages passes decoded body through stages
+func (a *apiVerifier) decodedStages(req *http.Request, res *http.Response, decoded interface{}) (interface{}, error) {
+	var ctx *StageContext
+	for _, stage := range a.opts.stages {
+		if stage.Decoded == nil {
+			continue
+		}
+		if err := checkBudget(req); err != nil {
+			return nil, err
+		}
+		if ctx == nil {
+			ctx = a.stageContext(req, res)
+		}
+		var err error
+		decoded, err = stage.Decoded(ctx, decoded)
+		if err != nil {
+			return nil, errors.Wrap(err, "stage "+stage.Name+" failed")
+		}
+	}
+	return decoded, nil
+}
+
+// reportStages passes result of verification through stages
+func (a *apiVerifier) reportStages(req *http.Request, res *http.Response, err error) error {
+	var ctx *StageContext
+	for _, stage := range a.opts.stages {
+		if stage.Report == nil {
+			continue
+		}
+		if ctx == nil {
+			ctx = a.stageContext(req, res)
+		}
+		err = stage.Report(ctx, err)
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	base64Stage = Stage{
+		Name: "base64",
+		Raw: func(ctx *StageContext, body []byte) ([]byte, error) {
+			return base64.StdEncoding.DecodeString(string(body))
+		},
+	}
+	envelopeStage = Stage{
+		Name: "envelope",
+		Decoded: func(ctx *StageContext, body interface{}) (interface{}, error) {
+			envelope, ok := body.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("body is not an envelope")
+			}
+			return envelope["data"], nil
+		},
+	}
+)
+
+func TestStages(t *testing.T) {
+	pet := `{"name": "doggie", "photoUrls": []}`
+	tests := []struct {
+		name    string
+		stages  []Stage
+		body    string
+		wantErr string
+	}{
+		{
+			name: "no stages",
+			body: pet,
+		},
+		{
+			name:   "raw",
+			stages: []Stage{base64Stage},
+			body:   base64.StdEncoding.EncodeToString([]byte(pet)),
+		},
+		{
+			name:    "raw failed",
+			stages:  []Stage{base64Stage},
+			body:    pet,
+			wantErr: "stage base64 failed",
+		},
+		{
+			name:   "decoded",
+			stages: []Stage{envelopeStage},
+			body:   `{"data": ` + pet + `}`,
+		},
+		{
+			name:    "decoded failed",
+			stages:  []Stage{envelopeStage},
+			body:    `[]`,
+			wantErr: "stage envelope failed: body is not an envelope",
+		},
+		{
+			name:    "decoded is validated",
+			stages:  []Stage{envelopeStage},
+			body:    pet,
+			wantErr: "must be of type object",
+		},
+		{
+			name:   "in order",
+			stages: []Stage{base64Stage, envelopeStage},
+			body:   base64.StdEncoding.EncodeToString([]byte(`{"data": ` + pet + `}`)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+sampleV2YAML, WithStages(tt.stages...))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "/v2/pet", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			err = v.VerifyRequest(req)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			rec.Body = bytes.NewBufferString(tt.body)
+			err = v.VerifyResponse(rec.Result(), httptest.NewRequest("GET", "/v2/pet/1", nil))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStages_Report(t *testing.T) {
+	var contexts []*StageContext
+	v, err := New(testdata+sampleV2YAML, WithStages(Stage{
+		Name: "ignore",
+		Report: func(ctx *StageContext, err error) error {
+			contexts = append(contexts, ctx)
+			if ctx.Response != nil && ctx.Response.StatusCode == 404 {
+				return nil
+			}
+			return err
+		},
+	}))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/v2/pet", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	assert.Error(t, v.VerifyRequest(req))
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(404)
+	rec.Body = bytes.NewBufferString(`{"unexpected": true}`)
+	assert.NoError(t, v.VerifyResponse(rec.Result(), httptest.NewRequest("GET", "/v2/pet/1", nil)))
+
+	require.Len(t, contexts, 2)
+	assert.Equal(t, "/pet", contexts[0].Path)
+	assert.Equal(t, "addPet", contexts[0].Operation.ID)
+	assert.Nil(t, contexts[0].Response)
+	assert.Equal(t, "/pet/{petId}", contexts[1].Path)
+	assert.Equal(t, "getPetById", contexts[1].Operation.ID)
+}
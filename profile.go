@@ -0,0 +1,65 @@
+package revisor
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Names of option profiles selected with WithProfile
+const (
+	// ProfileDevelopment enforces the definition, see DevelopmentMode,
+	// and reports curl commands of failed requests
+	ProfileDevelopment = "dev"
+	// ProfileStaging enforces the definition, see DevelopmentMode
+	ProfileStaging = "staging"
+	// ProfileProduction only reports violations of a sample of responses
+	// written with ValidatedResponseWriter, which are not replaced
+	ProfileProduction = "prod"
+)
+
+// ProfileEnv is the environment variable WithProfile reads profile name from
+const ProfileEnv = "REVISOR_PROFILE"
+
+// productionSampleRate is the fraction of responses verified in production
+const productionSampleRate = 0.1
+
+// WithProfile applies options of the named profile, so that one binary
+// behaves appropriately across environments. If name is empty, it is read
+// from REVISOR_PROFILE environment variable, and no options are applied
+// if the variable is not set either. Options following WithProfile override
+// options of the profile. Verifier constructors fail for unknown profiles.
+func WithProfile(name string) option {
+	return func(a *apiVerifier) {
+		if name == "" {
+			name = os.Getenv(ProfileEnv)
+		}
+		switch name {
+		case "":
+		case ProfileDevelopment:
+			a.opts.developmentMode = true
+			a.opts.reportCurl = true
+		case ProfileStaging:
+			a.opts.developmentMode = true
+		case ProfileProduction:
+			a.opts.developmentMode = false
+			a.opts.sampleRate = productionSampleRate
+		default:
+			a.opts.optionErr = errors.New("unknown profile " + name)
+		}
+	}
+}
+
+// WithSampleRate sets the fraction of responses verified with
+// ValidatedResponseWriter, other responses are written unverified.
+// All responses are verified by default.
+func WithSampleRate(rate float64) option {
+	return func(a *apiVerifier) {
+		a.opts.sampleRate = rate
+	}
+}
+
+// sampled decides if the response is verified
+func (a *apiVerifier) sampled() bool {
+	return a.opts.sampleRate >= 1 || a.opts.random() < a.opts.sampleRate
+}
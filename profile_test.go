@@ -0,0 +1,94 @@
+package revisor
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithProfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		profile    string
+		env        string
+		options    []option
+		enforce    bool
+		curl       bool
+		sampleRate float64
+		err        string
+	}{
+		{"no profile", "", "", nil, false, false, 1, ""},
+		{"dev", ProfileDevelopment, "", nil, true, true, 1, ""},
+		{"staging", ProfileStaging, "", nil, true, false, 1, ""},
+		{"prod", ProfileProduction, "", nil, false, false, productionSampleRate, ""},
+		{"profile from environment", "", ProfileStaging, nil, true, false, 1, ""},
+		{"profile overrides environment", ProfileDevelopment, ProfileProduction, nil, true, true, 1, ""},
+		{"options override profile", ProfileProduction, "", []option{WithSampleRate(0.5)}, false, false, 0.5, ""},
+		{"unknown profile", "qa", "", nil, false, false, 0, "unknown profile qa"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NoError(t, os.Setenv(ProfileEnv, test.env))
+			defer os.Unsetenv(ProfileEnv)
+
+			v, err := New(testdata+sampleV2YAML, append([]option{WithProfile(test.profile)}, test.options...)...)
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+				return
+			}
+			require.NoError(t, err)
+			opts := v.verifier().opts
+			assert.Equal(t, test.enforce, opts.developmentMode)
+			assert.Equal(t, test.curl, opts.reportCurl)
+			assert.Equal(t, test.sampleRate, opts.sampleRate)
+		})
+	}
+}
+
+func TestWithSampleRate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		rate   float64
+		status int
+	}{
+		{"sampled response is verified", 1, http.StatusInternalServerError},
+		{"unsampled response is written", 0, http.StatusOK},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			newWriter, err := NewResponseWriterFactory(testdata+sampleV2YAML,
+				DevelopmentMode, WithLogger(func(string, ...interface{}) {}), WithSampleRate(test.rate))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			w := newWriter(rec, httptest.NewRequest("GET", "/v2/user/testuser", nil))
+			w.Header().Set("Content-Type", "application/json")
+			_, err = w.Write([]byte(`{"username":"test-user"}`))
+			require.NoError(t, err)
+			_ = w.Commit()
+			assert.Equal(t, test.status, rec.Code)
+		})
+	}
+}
+
+func TestWithRandomSource(t *testing.T) {
+	sample := func() []bool {
+		a, err := newAPIVerifier(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		a.setOptions(WithSampleRate(0.5), WithRandomSource(rand.NewSource(1)))
+		var sampled []bool
+		for i := 0; i < 20; i++ {
+			sampled = append(sampled, a.sampled())
+		}
+		return sampled
+	}
+
+	sampled := sample()
+	assert.Contains(t, sampled, true)
+	assert.Contains(t, sampled, false)
+	assert.Equal(t, sampled, sample(), "responses are sampled deterministically")
+}
@@ -0,0 +1,151 @@
+package revisor
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// acceptRangesExt is an operation extension which declares that the operation
+// supports byte-range requests, e.g.
+//
+//	x-accept-ranges: true
+//
+// Range header of requests to such operations must be a valid byte range,
+// and 206 Partial Content and 416 Range Not Satisfiable responses are
+// accepted even if they are not documented. Their bodies are not validated
+// against schema, as they are only parts of full representation, but their
+// Content-Range header must be valid. Invalid ranges are reported as Violation
+// with CodeInvalidRange code.
+const acceptRangesExt = "x-accept-ranges"
+
+// acceptsRanges checks if the operation is declared with x-accept-ranges extension
+func acceptsRanges(operation *spec.Operation) bool {
+	accepts, _ := operation.Extensions[acceptRangesExt].(bool)
+	return accepts
+}
+
+// verifyRangeRequest checks Range header of the request
+func (a *apiVerifier) verifyRangeRequest(req *http.Request) error {
+	header := req.Header.Get("Range")
+	if header == "" {
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !acceptsRanges(operation) {
+		return err
+	}
+	if !validRange(header) {
+		return newViolation(CodeInvalidRange, "Range is not a valid byte range: "+header)
+	}
+	return nil
+}
+
+// verifyRangeResponse checks Content-Range header of partial response,
+// handled return parameter reports if response is a partial response,
+// which body is not validated against schema
+func (a *apiVerifier) verifyRangeResponse(req *http.Request, res *http.Response) (handled bool, err error) {
+	if res.StatusCode != http.StatusPartialContent && res.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		return false, nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !acceptsRanges(operation) {
+		return false, nil
+	}
+	header := res.Header.Get("Content-Range")
+	if res.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		if header != "" && !validContentRange(header, true) {
+			return true, newViolation(CodeInvalidRange, "Content-Range is not valid: "+header)
+		}
+		return true, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if header == "" && mediaType == "multipart/byteranges" {
+		return true, nil
+	}
+	if header == "" {
+		return true, newViolation(CodeInvalidRange, "Content-Range header of partial response is missing")
+	}
+	if !validContentRange(header, false) {
+		return true, newViolation(CodeInvalidRange, "Content-Range is not valid: "+header)
+	}
+	return true, nil
+}
+
+// validRange checks if header is a list of byte ranges,
+// e.g. bytes=0-499, bytes=500-, bytes=-500
+func validRange(header string) bool {
+	if !strings.HasPrefix(header, "bytes=") {
+		return false
+	}
+	for _, r := range strings.Split(header[len("bytes="):], ",") {
+		r = strings.TrimSpace(r)
+		i := strings.Index(r, "-")
+		if i < 0 {
+			return false
+		}
+		first, last := r[:i], r[i+1:]
+		if first == "" {
+			if _, ok := parseBytePos(last); !ok {
+				return false
+			}
+			continue
+		}
+		start, ok := parseBytePos(first)
+		if !ok {
+			return false
+		}
+		if last == "" {
+			continue
+		}
+		end, ok := parseBytePos(last)
+		if !ok || end < start {
+			return false
+		}
+	}
+	return true
+}
+
+// validContentRange checks if header is a byte range of complete length,
+// e.g. bytes 0-499/1234, bytes 0-499/*, or unsatisfied range, e.g. bytes */1234
+func validContentRange(header string, unsatisfied bool) bool {
+	if !strings.HasPrefix(header, "bytes ") {
+		return false
+	}
+	parts := strings.SplitN(header[len("bytes "):], "/", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	length, knownLength := parseBytePos(parts[1])
+	if !knownLength && (parts[1] != "*" || unsatisfied) {
+		return false
+	}
+	if unsatisfied {
+		return parts[0] == "*"
+	}
+	i := strings.Index(parts[0], "-")
+	if i < 0 {
+		return false
+	}
+	first, ok := parseBytePos(parts[0][:i])
+	if !ok {
+		return false
+	}
+	last, ok := parseBytePos(parts[0][i+1:])
+	if !ok || last < first {
+		return false
+	}
+	return !knownLength || last < length
+}
+
+// parseBytePos parses non-negative decimal byte position
+func parseBytePos(s string) (int64, bool) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, false
+	}
+	pos, err := strconv.ParseInt(s, 10, 64)
+	return pos, err == nil
+}
@@ -0,0 +1,148 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptRanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		rangeHeader  string
+		status       int
+		contentType  string
+		contentRange string
+		body         string
+		wantReqCode  string
+		wantRespCode string
+		wantRespErr  string
+	}{
+		{
+			name:        "partial content",
+			rangeHeader: "bytes=0-4",
+			status:      206, contentRange: "bytes 0-4/20", body: `{"nam`,
+		},
+		{
+			name:        "unknown length",
+			rangeHeader: "bytes=-5, 10-",
+			status:      206, contentRange: "bytes 15-19/*", body: `ata"}`,
+		},
+		{
+			name:        "multipart byteranges",
+			rangeHeader: "bytes=0-1,5-6",
+			status:      206, contentType: "multipart/byteranges; boundary=xyz", body: `--xyz--`,
+		},
+		{
+			name:        "full content",
+			rangeHeader: "bytes=0-",
+			status:      200, body: `{"name": "data"}`,
+		},
+		{
+			name:        "full content is validated",
+			rangeHeader: "bytes=0-",
+			status:      200, body: `{}`,
+			wantRespErr: "name in body is required",
+		},
+		{
+			name:        "not satisfiable",
+			rangeHeader: "bytes=100-",
+			status:      416, contentRange: "bytes */20",
+		},
+		{
+			name:        "invalid range",
+			rangeHeader: "bytes=5-1",
+			wantReqCode: CodeInvalidRange,
+		},
+		{
+			name:        "invalid range unit",
+			rangeHeader: "items=0-4",
+			wantReqCode: CodeInvalidRange,
+		},
+		{
+			name:        "missing content range",
+			rangeHeader: "bytes=0-4",
+			status:      206, body: `{"nam`,
+			wantRespCode: CodeInvalidRange,
+		},
+		{
+			name:        "content range over length",
+			rangeHeader: "bytes=0-4",
+			status:      206, contentRange: "bytes 0-20/20", body: `{"nam`,
+			wantRespCode: CodeInvalidRange,
+		},
+		{
+			name:        "range of unsatisfied content range",
+			rangeHeader: "bytes=100-",
+			status:      416, contentRange: "bytes 0-4/20",
+			wantRespCode: CodeInvalidRange,
+		},
+		{
+			name:        "not range capable",
+			path:        "/v1/reports/1",
+			rangeHeader: "items",
+			status:      206, contentRange: "bytes 0-4/20", body: `{"nam`,
+			wantRespErr: "neither default nor response schema for current status code is defined",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata + "ranges.yaml")
+			require.NoError(t, err)
+			path := tt.path
+			if path == "" {
+				path = "/v1/files/1"
+			}
+
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("Range", tt.rangeHeader)
+			err = v.VerifyRequest(req)
+			if tt.wantReqCode != "" {
+				assertVerifyErr(t, err, "", tt.wantReqCode)
+				return
+			}
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			rec.Header().Set("Content-Type", contentType)
+			if tt.contentRange != "" {
+				rec.Header().Set("Content-Range", tt.contentRange)
+			}
+			rec.WriteHeader(tt.status)
+			rec.Body = bytes.NewBufferString(tt.body)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), tt.wantRespErr, tt.wantRespCode)
+		})
+	}
+}
+
+func TestValidContentRange(t *testing.T) {
+	tests := []struct {
+		header      string
+		unsatisfied bool
+		want        bool
+	}{
+		{header: "bytes 0-499/1234", want: true},
+		{header: "bytes 0-499/*", want: true},
+		{header: "bytes */1234", unsatisfied: true, want: true},
+		{header: "bytes */*", unsatisfied: true},
+		{header: "bytes */1234"},
+		{header: "bytes 500-499/1234"},
+		{header: "bytes 0-1234/1234"},
+		{header: "bytes 0-499"},
+		{header: "bytes +0-499/1234"},
+		{header: "items 0-499/1234"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, validContentRange(tt.header, tt.unsatisfied))
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package revisor
+
+import (
+	"container/heap"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// rateLimitExt is an operation extension which configures the number of
+// requests a single client is allowed to make within a time window, e.g.
+//
+//	x-rate-limit:
+//	  limit: 100
+//	  window: 1m
+const rateLimitExt = "x-rate-limit"
+
+// RateLimitStore keeps request counters used to enforce rate limits.
+// Implementations backed by shared storage (e.g. redis) allow enforcing
+// limits across several instances of a service.
+type RateLimitStore interface {
+	// Incr increments the counter identified by key and returns its value.
+	// Counter is reset once window elapses since its first increment.
+	Incr(key string, window time.Duration) (int64, error)
+}
+
+// EnforceRateLimits enables enforcement of limits configured with x-rate-limit
+// operation extension. Counters are kept in store per operation and client,
+// clients are identified by clientKey which defaults to remote IP address.
+// Requests are counted when they are served through NewMiddleware or passed
+// to Verifier.CountRequest, other verifications, e.g. of recorded exchanges,
+// don't consume the quota. Requests over the limit are reported as Violation
+// with CodeRateLimitExceeded code.
+func EnforceRateLimits(store RateLimitStore, clientKey func(*http.Request) string) option {
+	return func(a *apiVerifier) {
+		if clientKey == nil {
+			clientKey = remoteIP
+		}
+		a.opts.rateLimitStore = store
+		a.opts.rateLimitClientKey = clientKey
+	}
+}
+
+type rateLimit struct {
+	Limit  int64  `json:"limit"`
+	Window string `json:"window"`
+}
+
+// CountRequest counts the request being served against the rate limit of
+// its operation, if limits are enforced, see EnforceRateLimits
+func (v *Verifier) CountRequest(req *http.Request) error {
+	if req == nil {
+		return ErrNilInput
+	}
+	return v.verifier().verifyRateLimit(req)
+}
+
+// verifyRateLimit increments counter of the client for the operation
+// and checks if it exceeds the configured limit
+func (a *apiVerifier) verifyRateLimit(req *http.Request) error {
+	if a.opts.rateLimitStore == nil {
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	limit, window, ok, err := operationRateLimit(operation)
+	if err != nil || !ok {
+		return err
+	}
+	key := a.operationKey(req, operation) + ":" + a.opts.rateLimitClientKey(req)
+	count, err := a.opts.rateLimitStore.Incr(key, window)
+	if err != nil {
+		return errors.Wrap(err, "failed to count request")
+	}
+	if count > limit {
+		return newViolation(CodeRateLimitExceeded, "more than "+strconv.FormatInt(limit, 10)+
+			" requests made within "+window.String())
+	}
+	return nil
+}
+
+// operationKey returns operation ID or, if it is not set,
+// HTTP method and path template identifying the operation
+func (a *apiVerifier) operationKey(req *http.Request, operation *spec.Operation) string {
+	if operation.ID != "" {
+		return operation.ID
+	}
+	tmpl, _, _ := a.mapper.mapRequest(req)
+	return req.Method + " " + tmpl
+}
+
+func operationRateLimit(operation *spec.Operation) (int64, time.Duration, bool, error) {
+	ext, ok := operation.Extensions[rateLimitExt]
+	if !ok {
+		return 0, 0, false, nil
+	}
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		return 0, 0, false, errors.Wrap(err, "failed to read "+rateLimitExt)
+	}
+	var limit rateLimit
+	err = json.Unmarshal(raw, &limit)
+	if err != nil {
+		return 0, 0, false, errors.Wrap(err, "failed to parse "+rateLimitExt)
+	}
+	window, err := time.ParseDuration(limit.Window)
+	if err != nil {
+		return 0, 0, false, errors.Wrap(err, "failed to parse "+rateLimitExt+" window")
+	}
+	return limit.Limit, window, true, nil
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// NewMemoryRateLimitStore returns RateLimitStore keeping counters in memory
+// of the current process
+func NewMemoryRateLimitStore() RateLimitStore {
+	return NewMemoryRateLimitStoreWithClock(systemClock{})
+}
+
+// NewMemoryRateLimitStoreWithClock returns RateLimitStore keeping counters in
+// memory of the current process, windows are measured with clock
+func NewMemoryRateLimitStoreWithClock(clock Clock) RateLimitStore {
+	return &memoryRateLimitStore{
+		counters: make(map[string]*windowCounter),
+		now:      clock.Now,
+	}
+}
+
+type windowCounter struct {
+	key     string
+	count   int64
+	expires time.Time
+}
+
+// counterHeap orders counters by expiration, so that expired ones are found
+// without scanning every counter
+type counterHeap []*windowCounter
+
+func (h counterHeap) Len() int            { return len(h) }
+func (h counterHeap) Less(i, j int) bool  { return h[i].expires.Before(h[j].expires) }
+func (h counterHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *counterHeap) Push(x interface{}) { *h = append(*h, x.(*windowCounter)) }
+func (h *counterHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+type memoryRateLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]*windowCounter
+	expiring counterHeap
+	now      func() time.Time
+}
+
+func (m *memoryRateLimitStore) Incr(key string, window time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for len(m.expiring) != 0 && !now.Before(m.expiring[0].expires) {
+		expired := heap.Pop(&m.expiring).(*windowCounter)
+		delete(m.counters, expired.key)
+	}
+	counter, ok := m.counters[key]
+	if !ok {
+		counter = &windowCounter{key: key, expires: now.Add(window)}
+		m.counters[key] = counter
+		heap.Push(&m.expiring, counter)
+	}
+	counter.count++
+	return counter.count, nil
+}
@@ -0,0 +1,80 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimitStore_Incr(t *testing.T) {
+	now := time.Now()
+	store := &memoryRateLimitStore{
+		counters: make(map[string]*windowCounter),
+		now:      func() time.Time { return now },
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		count, err := store.Incr("key", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+	count, err := store.Incr("another-key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	now = now.Add(time.Minute)
+	count, err = store.Incr("key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Len(t, store.counters, 1, "expired counters are removed")
+	assert.Len(t, store.expiring, 1)
+
+	count, err = store.Incr("short", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	now = now.Add(time.Second)
+	count, err = store.Incr("key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	assert.Len(t, store.counters, 1, "counters expire in order of expiration")
+}
+
+func TestAPIVerifierV2_VerifyRateLimit(t *testing.T) {
+
+	v, err := New(testdata+sampleV2YAML, EnforceRateLimits(NewMemoryRateLimitStore(), nil))
+	require.NoError(t, err)
+
+	getInventory := func(remoteAddr string) error {
+		req := httptest.NewRequest("GET", "/v2/store/inventory", nil)
+		req.RemoteAddr = remoteAddr
+		return v.CountRequest(req)
+	}
+
+	assert.NoError(t, getInventory("10.0.0.1:1234"))
+	assert.NoError(t, getInventory("10.0.0.1:4321"))
+	err = getInventory("10.0.0.1:1234")
+	violation, ok := errors.Cause(err).(*Violation)
+	require.True(t, ok)
+	assert.Equal(t, CodeRateLimitExceeded, violation.Code)
+
+	assert.NoError(t, getInventory("10.0.0.2:1234"))
+
+	t.Run("operations without limit are not counted", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, v.CountRequest(httptest.NewRequest("GET", "/v2/pet/1", nil)))
+		}
+	})
+
+	t.Run("verification doesn't consume quota", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest("GET", "/v2/store/inventory", nil)
+			req.RemoteAddr = "10.0.0.3:1234"
+			assert.NoError(t, v.VerifyRequest(req))
+		}
+		assert.NoError(t, getInventory("10.0.0.3:1234"))
+	})
+}
@@ -0,0 +1,39 @@
+package revisor
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+)
+
+// byteBody is a body backed by a buffer, e.g. *bytes.Buffer, which unread
+// contents are available without reading and copying them
+type byteBody interface {
+	io.Reader
+	Bytes() []byte
+}
+
+// recorderBody is a response body sharing the buffer of httptest.ResponseRecorder
+type recorderBody struct {
+	*bytes.Buffer
+}
+
+func (recorderBody) Close() error {
+	return nil
+}
+
+// VerifyRecorder verifies the request and the response recorded with rec.
+// Response body is validated directly from the buffer of the recorder, without
+// reading and copying it, which speeds up contract test suites with large bodies.
+// Nil recorder or request is reported as ErrNilInput.
+func (v *Verifier) VerifyRecorder(rec *httptest.ResponseRecorder, req *http.Request) error {
+	if rec == nil {
+		return ErrNilInput
+	}
+	res := rec.Result()
+	if rec.Body != nil {
+		res.Body = recorderBody{bytes.NewBuffer(rec.Body.Bytes())}
+	}
+	return v.Verify(res, req)
+}
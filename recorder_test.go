@@ -0,0 +1,59 @@
+package revisor
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordPet(body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(http.StatusOK)
+	_, _ = rec.WriteString(body)
+	return rec
+}
+
+func TestVerifier_VerifyRecorder(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "valid", body: `{"name": "doggie", "photoUrls": []}`},
+		{name: "invalid", body: `{"name": 1, "photoUrls": []}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := recordPet(tt.body)
+			err := v.VerifyRecorder(rec, httptest.NewRequest("GET", "/v2/pet/1", nil))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.body, rec.Body.String(), "recorded body is kept")
+		})
+	}
+}
+
+func TestReadResponseBody_Buffered(t *testing.T) {
+	buf := bytes.NewBufferString("abc")
+	res := &http.Response{Body: recorderBody{buf}}
+
+	body, err := readResponseBody(res)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(body))
+
+	read, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(read), "buffered body is not consumed")
+}
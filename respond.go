@@ -0,0 +1,80 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RespondExample writes response of the operation identified by operationID with
+// status. Body is the example documented for the response or, if there is none,
+// a sample generated from the response schema. Content-Type is set to the preferred
+// media type produced by the operation, documented header defaults are set as well.
+// It lets handler stubs of unimplemented endpoints pass verification.
+func (v *Verifier) RespondExample(w http.ResponseWriter, operationID string, status int) error {
+	a := v.verifier()
+	_, _, operation, _ := a.operationByID(operationID)
+	if operation == nil {
+		return errors.New("operation is not defined: " + operationID)
+	}
+	response, err := a.responseByStatus(status, operation)
+	if err != nil {
+		return err
+	}
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = a.doc.Spec().Produces
+	}
+
+	for name, header := range response.Headers {
+		if header.Default != nil {
+			w.Header().Set(name, toHeaderValue(header.Default))
+		}
+	}
+
+	var body []byte
+	mediaType := preferredMediaTypeOr(produces, "application/json")
+	example, ok := response.Examples[mediaType]
+	if !ok {
+		for _, m := range produces {
+			if example, ok = response.Examples[m]; ok {
+				mediaType = m
+				break
+			}
+		}
+	}
+	switch {
+	case ok && !strings.Contains(mediaType, "json"):
+		s, isString := example.(string)
+		if !isString {
+			return errors.New("example of " + mediaType + " is not a string")
+		}
+		body = []byte(s)
+	case ok:
+		body, err = json.Marshal(example)
+	case response.Schema != nil:
+		body, err = json.Marshal(sampleValue(response.Schema))
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to encode example")
+	}
+
+	if body != nil {
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
+
+func toHeaderValue(v interface{}) string {
+	if s := toString(v); s != "" {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,58 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_RespondExample(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		operationID string
+		status      int
+		request     string
+		wantBody    string
+		wantErr     string
+	}{
+		{name: "generated sample", operationID: "getUserByName", status: 200, request: "/v2/user/testuser",
+			wantBody: `{"birthday":"1970-01-01","email":"user@example.com","firstname":0,"id":0,"lastname":"","password":"","phone":"","user_status":0,"username":""}`},
+		{name: "sample with example property", operationID: "getPetById", status: 200, request: "/v2/pet/1"},
+		{name: "unknown operation", operationID: "unknown", wantErr: "operation is not defined: unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := v.RespondExample(rec, tt.operationID, tt.status)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+			assert.NoError(t, v.Verify(rec.Result(), httptest.NewRequest("GET", tt.request, nil)))
+		})
+	}
+}
+
+func TestRespondExample_DocumentedExample(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	_, _, operation, _ := v.verifier().operationByID("getInventory")
+	response := operation.Responses.StatusCodeResponses[200]
+	response.Examples = map[string]interface{}{"application/json": map[string]interface{}{"available": 3}}
+	operation.Responses.StatusCodeResponses[200] = response
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, v.RespondExample(rec, "getInventory", 200))
+	assert.JSONEq(t, `{"available":3}`, rec.Body.String())
+}
@@ -3,9 +3,15 @@ package revisor
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"io/ioutil"
+	"log"
+	"math/rand"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-openapi/loads"
 	"github.com/go-openapi/spec"
@@ -23,8 +29,65 @@ type option func(*apiVerifier)
 
 // options is a struct that holds all possible options
 type options struct {
-	strictContentType bool
-	ignoreBasePath    bool
+	strictContentType  bool
+	ignoreBasePath     bool
+	checkFraming       bool
+	checkConditional   bool
+	checkPagination    bool
+	checkIdempotency   bool
+	correlationHeader  string
+	warnDeprecated     bool
+	rejectDeprecated   bool
+	checkSunset        bool
+	fieldValidators    map[string]func(interface{}) error
+	semanticValidators map[string][]func(interface{}, Params) error
+	checkConsistency   bool
+	consistencyChecks  map[string][]func(DecodedExchange) error
+	checkLocation      bool
+	checkLinkHeaders   bool
+	paginationLinks    bool
+	checkDisposition   bool
+	checkCachePolicy   bool
+	checkSecurity      bool
+	requireNosniff     bool
+	rejectInsecure     bool
+	detectLeaks        bool
+	leakDetectors      []LeakDetector
+	checkExamples      bool
+	volatileFields     map[string]bool
+	sampleRate         float64
+	random             func() float64
+	routingCacheSize   int
+	streamingDecode    map[string]bool
+	multipartLimit     int64
+	optionErr          error
+	developmentMode    bool
+	denyUndocumented   bool
+	failOnLintIssues   bool
+	reportCurl         bool
+	tryAllTemplates    bool
+
+	basePaths []string
+
+	logf func(format string, args ...interface{})
+	// loggerSet reports if the logger is set with WithLogger, definition
+	// warnings are only logged with configured logger
+	loggerSet bool
+	warn      func(Warning)
+
+	lintRules []Rule
+
+	rateLimitStore     RateLimitStore
+	rateLimitClientKey func(*http.Request) string
+
+	metrics          MetricsSink
+	validationBudget time.Duration
+	clock            Clock
+
+	stages   []Stage
+	envelope string
+	jsonAPI  bool
+	hal      bool
 }
 
 // NoStrictContentType disables strict content-type validation which is enabled by default.
@@ -43,35 +106,94 @@ func IgnoreBasePath(a *apiVerifier) {
 	a.opts.ignoreBasePath = true
 }
 
+// WithBasePaths sets base paths request paths may be prefixed with, instead of
+// the single base path configured in API document. Base paths may be given as
+// server URLs and may contain variables, e.g. "https://{region}.example.com/api/{version}",
+// only the path is used and variables match any path segment. OpenAPI 3.0
+// definitions use paths of all their servers as base paths by default.
+func WithBasePaths(basePaths ...string) option {
+	return func(a *apiVerifier) {
+		a.opts.basePaths = nil
+		for _, basePath := range basePaths {
+			a.opts.basePaths = append(a.opts.basePaths, serverPath(basePath))
+		}
+	}
+}
+
+// serverPath returns path of the server URL without trailing slash
+func serverPath(serverURL string) string {
+	if i := strings.Index(serverURL, "://"); i != -1 {
+		serverURL = serverURL[i+len("://"):]
+		if j := strings.Index(serverURL, "/"); j != -1 {
+			serverURL = serverURL[j:]
+		} else {
+			serverURL = ""
+		}
+	}
+	return strings.TrimSuffix(serverURL, "/")
+}
+
+// CheckFraming enables request framing sanity checks suitable for gateways.
+// Requests with both Content-Length and Transfer-Encoding set, as well as requests
+// sending a body to operations declared without one, are reported as Violation
+// with CodeAmbiguousFraming and CodeUnexpectedBody codes correspondingly.
+// net/http resolves the framing of requests it reads, so ambiguous framing
+// is only reported for raw captured requests.
+func CheckFraming(a *apiVerifier) {
+	a.opts.checkFraming = true
+}
+
 // NewRequestVerifier returns a function that can be used to verify if request
 // satisfies OpenAPI definition constraints
 func NewRequestVerifier(definitionPath string, options ...option) (func(*http.Request) error, error) {
-	a, err := newAPIVerifier(definitionPath)
+	a, err := newInitializedVerifier(definitionPath, options...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create verifier function")
 	}
-	a.setOptions(options...)
-	err = a.initMapper(a.doc.Spec().BasePath)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create request mapper")
-	}
-	return a.verifyRequest, err
+	return a.verifyRequest, nil
 }
 
 // NewVerifier returns a function that can be used to verify both - a request
 // and the response made in the context of the request
 func NewVerifier(definitionPath string, options ...option) (func(*http.Response, *http.Request) error, error) {
-	a, err := newAPIVerifier(definitionPath)
+	a, err := newInitializedVerifier(definitionPath, options...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create verifier function")
 	}
-	a.setOptions(options...)
+	return a.verifyRequestAndReponse, nil
+}
 
-	err = a.initMapper(a.doc.Spec().BasePath)
+// newInitializedVerifier loads the definition, applies options and
+// initializes request mapper of the verifier
+func newInitializedVerifier(definitionPath string, options ...option) (*apiVerifier, error) {
+	a, err := newAPIVerifier(definitionPath)
+	if err != nil {
+		return nil, err
+	}
+	err = a.init(options...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create request mapper")
+		return nil, err
+	}
+	return a, nil
+}
+
+// init applies options, initializes request mapper and checks the loaded document
+func (a *apiVerifier) init(options ...option) error {
+	a.setOptions(options...)
+	if a.opts.optionErr != nil {
+		return a.opts.optionErr
+	}
+	err := a.initMapper(a.doc.Spec().BasePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request mapper")
+	}
+	err = a.initPartSchemas()
+	if err != nil {
+		return err
 	}
-	return a.verifyRequestAndReponse, err
+	a.warnUnconverted()
+	a.warnUnenforced()
+	return a.lintDefinition()
 }
 
 func newAPIVerifier(definitionPath string) (*apiVerifier, error) {
@@ -93,6 +215,18 @@ func newAPIVerifier(definitionPath string) (*apiVerifier, error) {
 func withDefaults(a *apiVerifier) *apiVerifier {
 	a.opts.strictContentType = true
 	a.opts.ignoreBasePath = false
+	a.opts.checkFraming = false
+	a.opts.developmentMode = false
+	a.opts.denyUndocumented = false
+	a.opts.failOnLintIssues = false
+	a.opts.reportCurl = false
+	a.opts.tryAllTemplates = false
+	a.opts.sampleRate = 1
+	a.opts.multipartLimit = DefaultMultipartBufferLimit
+	a.flatValidators = &sync.Map{}
+	a.opts.clock = systemClock{}
+	a.opts.random = rand.Float64
+	a.opts.logf = log.Printf
 	return a
 }
 
@@ -103,46 +237,225 @@ type apiVerifier struct {
 	opts           options
 	mapper         *simpleMapper
 	doc            *loads.Document
+	// flatValidators caches validators of flat schemas, see WithStreamingDecode
+	flatValidators *sync.Map
+	// unconverted lists OpenAPI 3.0 constructs dropped from the definition
+	unconverted []string
+	// openAPI3 reports if the definition is converted from OpenAPI 3.0
+	openAPI3 bool
+	// partSchemas maps operations to schemas of their multipart request parts
+	partSchemas map[*spec.Operation]map[string]*spec.Schema
 }
 
+// ErrNilInput is reported when request or response to verify is nil
+var ErrNilInput = errors.New("request or response is nil")
+
 // verifyRequest verifies if request is valid according to OpenAPI definition
 // and configured options
 func (a *apiVerifier) verifyRequest(req *http.Request) error {
+	_, err := a.verifyMeasuredRequest(req)
+	return err
+}
+
+// verifyMeasuredRequest verifies the request within validation budget, reports
+// metrics of verification and returns decoded body of the valid request
+func (a *apiVerifier) verifyMeasuredRequest(req *http.Request) (interface{}, error) {
+	if req == nil {
+		return nil, ErrNilInput
+	}
+	started := a.opts.clock.Now()
+	pinned := a.pinSatisfiedTemplate(req)
+	defer restoreBody(req, pinned)
+	req = pinned
+	var decoded interface{}
+	err := a.withinBudget(req, nil, func(req *http.Request, _ *http.Response) (err error) {
+		decoded, err = a.verifyAndDecodeRequest(req)
+		return err
+	})
+	err = a.reportStages(req, nil, err)
+	a.recordMetrics("request", req, started, err)
+	if err != nil {
+		if a.opts.reportCurl {
+			return nil, newCurlError(err, req)
+		}
+		return nil, err
+	}
+	// decoded body is only read once verification is done, as abandoned
+	// verification may still be running
+	return decoded, nil
+}
+
+// verifyAndDecodeRequest verifies the request and returns its decoded body,
+// which is nil if the operation doesn't declare a body
+func (a *apiVerifier) verifyAndDecodeRequest(req *http.Request) (decoded interface{}, err error) {
+	defer recoverInternalError(&err)
+	req = a.pinSatisfiedTemplate(req)
+	decoded, err = a.verifyRequestContract(req)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, a.verifySemantics(req, decoded)
+}
+
+// verifyRequestContract verifies the request against the definition
+// and returns its decoded body
+func (a *apiVerifier) verifyRequestContract(req *http.Request) (interface{}, error) {
 	requestDef, consumes, err := a.getRequestDef(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	body, err := readRequestBody(req)
+	err = a.verifyPathParams(req)
 	if err != nil {
-		return errors.Wrap(err, "failed to verify request")
+		return nil, err
+	}
+	if a.opts.checkFraming {
+		err = a.verifyFraming(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if a.opts.checkConditional {
+		err = a.verifyConditionalRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if a.opts.checkPagination {
+		err = a.verifyPaginationRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if a.opts.checkIdempotency {
+		err = a.verifyIdempotencyKey(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if a.opts.warnDeprecated || a.opts.rejectDeprecated {
+		err = a.verifyDeprecation(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = a.verifyRangeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if handled, err := a.verifyWebSocketRequest(req); handled {
+		return nil, err
+	}
+	if handled, err := a.verifyMultipartRequest(req); handled {
+		return nil, err
+	}
+	body := []byte{}
+	if !hasNoBody(req) {
+		body, err = readRequestBody(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to verify request")
+		}
+	}
+	body, err = a.rawStages(req, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	if handled, err := a.verifyXMLTolerance(req, nil, body); handled {
+		return nil, err
 	}
 	if requestDef != nil {
 		if requestDef.Required {
 			err = checkIfSchemaOrBodyIsEmpty(requestDef.Schema, len(body))
 			if err != nil {
-				return errors.Wrap(err, "either defined schema or request body is empty")
+				return nil, errors.Wrap(err, "either defined schema or request body is empty")
 			}
 		}
 		contentType, err := a.matchContentType(req.Header.Get("Content-Type"), consumes)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		decoded, err := decodeBody(contentType, body)
+		decoded, err := decodeRequestBody(req, contentType, body)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode request")
+		}
+		decoded, err = a.unwrapEnvelope(req, decoded)
+		if err != nil {
+			return nil, err
+		}
+		decoded, err = a.decodedStages(req, nil, decoded)
+		if err != nil {
+			return nil, err
+		}
+		err = checkBudget(req)
 		if err != nil {
-			return errors.Wrap(err, "failed to decode request")
+			return nil, err
 		}
-		return validate.AgainstSchema(requestDef.Schema, decoded, strfmt.Default)
+		err = validate.AgainstSchema(requestDef.Schema, decoded, strfmt.Default)
+		if err != nil {
+			schema, pointer := a.requestSchemaOrigin(req)
+			err = a.describeBodyError(err, decoded, schema, pointer)
+		} else if err = a.validateFields(requestDef.Schema, decoded); err == nil {
+			err = checkRules(requestDef.Schema, decoded)
+		}
+		return decoded, err
 	}
 	if requestDef == nil && len(body) != 0 {
-		return errors.New("failed to verify request: definition is not defined but body is not empty")
+		return nil, errors.New("failed to verify request: definition is not defined but body is not empty")
 	}
-	return nil
+	return nil, nil
 }
 
 // verifyResponse verifies if the response is valid according to OpenAPI definition
 // and configured options
-func (a *apiVerifier) verifyResponse(res *http.Response, req *http.Request) error {
+func (a *apiVerifier) verifyResponse(res *http.Response, req *http.Request) (err error) {
+	defer recoverInternalError(&err)
+	if a.opts.correlationHeader != "" {
+		err = a.verifyCorrelationHeader(req, res)
+		if err != nil {
+			return err
+		}
+	}
+	if a.opts.checkSunset {
+		err = a.verifySunsetHeaders(req, res)
+		if err != nil {
+			return err
+		}
+	}
+	if a.opts.checkLinkHeaders {
+		err = verifyLinkHeaders(res)
+		if err != nil {
+			return err
+		}
+	}
+	if a.opts.checkLocation {
+		err = a.verifyLocation(req, res)
+		if err != nil {
+			return err
+		}
+	}
+	if a.opts.checkDisposition {
+		err = a.verifyContentDisposition(req, res)
+		if err != nil {
+			return err
+		}
+	}
+	if a.opts.checkCachePolicy {
+		err = a.verifyCachePolicy(req, res)
+		if err != nil {
+			return err
+		}
+	}
+	if a.opts.checkConditional {
+		if handled, err := a.verifyConditionalResponse(req, res); handled || err != nil {
+			return err
+		}
+	}
+	if handled, err := a.verifyRangeResponse(req, res); handled {
+		return err
+	}
+	if handled, err := a.verifyWebSocketResponse(req, res); handled {
+		return err
+	}
 	response, produces, err := a.getResponseDef(req, res)
 	if err != nil {
 		return err
@@ -151,6 +464,23 @@ func (a *apiVerifier) verifyResponse(res *http.Response, req *http.Request) erro
 	if err != nil {
 		return errors.Wrap(err, "response not valid")
 	}
+	if a.opts.checkSecurity {
+		err = a.verifySecurityHeaders(req, res, body)
+		if err != nil {
+			return err
+		}
+	}
+	err = verifyTrailers(response, res)
+	if err != nil {
+		return err
+	}
+	body, err = a.rawStages(req, res, body)
+	if err != nil {
+		return err
+	}
+	if handled, err := a.verifyXMLTolerance(req, res, body); handled {
+		return err
+	}
 	err = checkIfSchemaOrBodyIsEmpty(response.Schema, len(body))
 	if err != nil {
 		return errors.Wrap(err, "either defined schema or response body is empty")
@@ -160,25 +490,69 @@ func (a *apiVerifier) verifyResponse(res *http.Response, req *http.Request) erro
 	if err != nil {
 		return err
 	}
+	if a.opts.streamingDecode != nil && a.verifiedStreaming(req, response.Schema, contentType, body) {
+		return nil
+	}
 	decoded, err := decodeBody(contentType, body)
 	if err != nil {
 		return errors.Wrap(err, "failed to decode response")
 	}
-	return validate.AgainstSchema(response.Schema, decoded, strfmt.Default)
+	if a.opts.checkPagination {
+		err = a.verifyPaginationResponse(req, res, decoded)
+		if err != nil {
+			return err
+		}
+	}
+	if a.opts.paginationLinks {
+		err = a.verifyPaginationLinks(req, res, decoded)
+		if err != nil {
+			return err
+		}
+	}
+	decoded, err = a.unwrapEnvelope(req, decoded)
+	if err != nil {
+		return err
+	}
+	decoded, err = a.decodedStages(req, res, decoded)
+	if err != nil {
+		return err
+	}
+	err = checkBudget(req)
+	if err != nil {
+		return err
+	}
+	err = validate.AgainstSchema(response.Schema, decoded, strfmt.Default)
+	if err != nil {
+		schema, pointer := a.responseSchemaOrigin(req, res)
+		err = a.describeBodyError(err, decoded, schema, pointer)
+	} else if err = a.validateFields(response.Schema, decoded); err == nil {
+		err = checkRules(response.Schema, decoded)
+	}
+	if err != nil {
+		return err
+	}
+	if a.opts.detectLeaks {
+		err = a.detectLeaks(response.Schema, decoded)
+		if err != nil {
+			return err
+		}
+	}
+	if a.opts.checkExamples {
+		err = a.verifyExample(response, contentType, decoded)
+		if err != nil {
+			return err
+		}
+	}
+	return a.verifyConsistency(req, res, response, decoded)
 }
 
 // getRequestDef checks parameters defined on both Path and Operation components
 // Second return parameter is a slice of mime types that can be consumed by operation
 // returns an error if no body parameters were found
 func (a *apiVerifier) getRequestDef(req *http.Request) (*spec.Parameter, []string, error) {
-	pathDef, err := a.getPathDef(req)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to get pathItem defintiion")
-	}
-	operation, err := a.operationByMethod(req.Method, pathDef)
-
+	pathDef, operation, err := a.getOperationDef(req)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to get operation defintiion")
+		return nil, nil, err
 	}
 	reqBodyParameter := getBodyParameter(operation.Parameters)
 	if reqBodyParameter == nil {
@@ -191,6 +565,19 @@ func (a *apiVerifier) getRequestDef(req *http.Request) (*spec.Parameter, []strin
 	return reqBodyParameter, consumes, nil
 }
 
+// getOperationDef returns path item and operation definitions matching the request
+func (a *apiVerifier) getOperationDef(req *http.Request) (*spec.PathItem, *spec.Operation, error) {
+	pathDef, err := a.getPathDef(req)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get pathItem defintiion")
+	}
+	operation, err := a.operationByMethod(req.Method, pathDef)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get operation defintiion")
+	}
+	return pathDef, operation, nil
+}
+
 func (a *apiVerifier) matchContentType(contentType string, allowed []string) (string, error) {
 	if len(allowed) == 0 && a.opts.strictContentType {
 		return "", errors.New("array of allowed content types is empty")
@@ -198,6 +585,9 @@ func (a *apiVerifier) matchContentType(contentType string, allowed []string) (st
 	matched := strings.Trim(contentType, " ")
 	for _, typeStr := range allowed {
 		target := strings.Trim(typeStr, " ")
+		if a.jsonMediaType(matched) && strings.HasPrefix(target, "application/json") {
+			return matched, nil
+		}
 		if a.opts.strictContentType {
 			if strings.Compare(matched, target) == 0 {
 				return matched, nil
@@ -226,7 +616,10 @@ func (a *apiVerifier) getPathDef(req *http.Request) (*spec.PathItem, error) {
 
 	pathTmpl, _, ok := a.mapper.mapRequest(req)
 	if !ok {
-		return nil, errors.New("no path template matches current request")
+		if a.mapper.methodMismatch(req) {
+			return nil, newViolation(CodeUndocumentedMethod, "no path template matches current request method")
+		}
+		return nil, newViolation(CodeUndocumentedPath, "no path template matches current request")
 	}
 	pathDef, ok := a.doc.Spec().Paths.Paths[pathTmpl]
 	if !ok {
@@ -268,7 +661,16 @@ func checkIfSchemaOrBodyIsEmpty(schema *spec.Schema, bodyLen int) error {
 	return nil
 }
 
+// verifyRequestAndReponse verifies the request and the response made in its context.
+// Request is verified even if response is nil, which is reported as ErrNilInput
+// unless the request fails verification.
 func (a *apiVerifier) verifyRequestAndReponse(res *http.Response, req *http.Request) error {
+	if req == nil {
+		return ErrNilInput
+	}
+	pinned := a.pinSatisfiedTemplate(req)
+	defer restoreBody(req, pinned)
+	req = pinned
 	var report error
 	err := a.verifyRequest(req)
 	if err != nil {
@@ -278,13 +680,34 @@ func (a *apiVerifier) verifyRequestAndReponse(res *http.Response, req *http.Requ
 		}
 	}
 
-	err = a.verifyResponse(res, req)
+	if res == nil && report != nil {
+		return report
+	}
+	err = a.verifyMeasuredResponse(res, req)
 	if err != nil {
 		report = errors.Wrap(err, "response validation failed")
 	}
 	return report
 }
 
+// verifyMeasuredResponse verifies the response within validation budget
+// and reports metrics of verification
+func (a *apiVerifier) verifyMeasuredResponse(res *http.Response, req *http.Request) error {
+	if res == nil || req == nil {
+		return ErrNilInput
+	}
+	started := a.opts.clock.Now()
+	pinned := a.pinSatisfiedTemplate(req)
+	defer restoreBody(req, pinned)
+	req = pinned
+	err := a.withinBudget(req, res, func(req *http.Request, res *http.Response) error {
+		return a.verifyResponse(res, req)
+	})
+	err = a.reportStages(req, res, err)
+	a.recordMetrics("response", req, started, err)
+	return err
+}
+
 func (a *apiVerifier) setOptions(options ...option) {
 	for _, opt := range options {
 		opt(a)
@@ -303,40 +726,65 @@ func (a *apiVerifier) responseByStatus(status int, operation *spec.Operation) (*
 }
 
 func (a *apiVerifier) operationByMethod(method string, pathDef *spec.PathItem) (*spec.Operation, error) {
-	var operation *spec.Operation
+	operation := pathOperation(method, pathDef)
+	if operation == nil {
+		return nil, errors.New("no operation configured for method: " + method)
+	}
+	return operation, nil
+}
+
+// httpMethods lists methods operations can be defined for in a path item
+var httpMethods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+}
+
+// pathOperation returns operation defined for the method in path item
+// or nil if it's not defined
+func pathOperation(method string, pathDef *spec.PathItem) *spec.Operation {
 	switch method {
 	case http.MethodGet:
-		operation = pathDef.Get
+		return pathDef.Get
 	case http.MethodPut:
-		operation = pathDef.Put
+		return pathDef.Put
 	case http.MethodPost:
-		operation = pathDef.Post
+		return pathDef.Post
 	case http.MethodDelete:
-		operation = pathDef.Delete
+		return pathDef.Delete
 	case http.MethodOptions:
-		operation = pathDef.Options
+		return pathDef.Options
 	case http.MethodHead:
-		operation = pathDef.Head
+		return pathDef.Head
 	case http.MethodPatch:
-		operation = pathDef.Patch
+		return pathDef.Patch
 	}
-	if operation == nil {
-		return nil, errors.New("no operation configured for method: " + method)
+	return nil
+}
+
+// sortedPaths returns path templates of the specification in lexical order
+func sortedPaths(swagger *spec.Swagger) []string {
+	paths := make([]string, 0, len(swagger.Paths.Paths))
+	for path := range swagger.Paths.Paths {
+		paths = append(paths, path)
 	}
-	return operation, nil
+	sort.Strings(paths)
+	return paths
 }
 
 func (a *apiVerifier) initDocument(raw []byte) error {
-	rawJSON := json.RawMessage(raw)
-	if swag.YAMLMatcher(a.definitionPath) {
-		yamlDoc, err := swag.BytesToYAMLDoc(raw)
-		if err != nil {
-			return errors.Wrap(err, "failed to parse yaml document")
-		}
-		rawJSON, err = swag.YAMLToJSON(yamlDoc)
-		if err != nil {
-			return errors.Wrap(err, "failed to convert doc to json")
-		}
+	rawJSON, err := definitionJSON(a.definitionPath, raw)
+	if err != nil {
+		return err
+	}
+	a.openAPI3 = openAPIVersion(rawJSON) != ""
+	rawJSON, a.unconverted, err = swagger2JSON(rawJSON)
+	if err != nil {
+		return err
 	}
 	doc, err := loads.Analyzed(rawJSON, ver2)
 	if err != nil {
@@ -349,6 +797,23 @@ func (a *apiVerifier) initDocument(raw []byte) error {
 	return nil
 }
 
+// definitionJSON returns raw definition as JSON, converting it from YAML
+// if definitionPath has YAML extension
+func definitionJSON(definitionPath string, raw []byte) (json.RawMessage, error) {
+	if !swag.YAMLMatcher(definitionPath) {
+		return json.RawMessage(raw), nil
+	}
+	yamlDoc, err := swag.BytesToYAMLDoc(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse yaml document")
+	}
+	rawJSON, err := swag.YAMLToJSON(yamlDoc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert doc to json")
+	}
+	return rawJSON, nil
+}
+
 func (a *apiVerifier) initMapper(basePath string) error {
 	requestsMap := make(map[string][]string)
 	for path, pathItem := range a.doc.Spec().Paths.Paths {
@@ -374,10 +839,20 @@ func (a *apiVerifier) initMapper(basePath string) error {
 			requestsMap[http.MethodPatch] = append(requestsMap[http.MethodPatch], path)
 		}
 	}
+	basePaths := []string{basePath}
+	if serverPaths, ok := a.doc.Spec().Extensions.GetStringSlice(basePathsExt); ok {
+		basePaths = serverPaths
+	}
+	if len(a.opts.basePaths) != 0 {
+		basePaths = a.opts.basePaths
+	}
 	if a.opts.ignoreBasePath {
-		basePath = ""
+		basePaths = []string{""}
+	}
+	a.mapper = newBasePathsMapper(basePaths, requestsMap)
+	if a.opts.routingCacheSize > 0 {
+		a.mapper.cache = newRouteCache(a.opts.routingCacheSize)
 	}
-	a.mapper = newSimpleMapper(basePath, requestsMap)
 	return nil
 }
 
@@ -390,6 +865,13 @@ func decodeBody(contentType string, body []byte) (interface{}, error) {
 	return decoded, nil
 }
 
+// hasNoBody reports if reading the request body can be skipped as it is
+// known to be empty. Content-Length isn't trusted, as bodies of requests
+// declaring none must be read to be rejected
+func hasNoBody(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody
+}
+
 // readRequestBody reads contents from the request body and returns slice of bytes
 // ReadCloser associated with request will be assigned a new buffer value,
 // so that upstream calls will be able to read the body again.
@@ -397,9 +879,15 @@ func readRequestBody(r *http.Request) ([]byte, error) {
 	if r.Body == nil {
 		return []byte{}, nil
 	}
+	if body, ok, err := readSeekableBody(r.Body); ok {
+		if err != nil {
+			return nil, &TransportError{Err: errors.Wrap(err, "error reading request body")}
+		}
+		return body, nil
+	}
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		return nil, errors.Wrap(err, "error reading request body")
+		return nil, &TransportError{Err: errors.Wrap(err, "error reading request body")}
 	}
 	r.Body = ioutil.NopCloser(bytes.NewReader(body))
 	return body, nil
@@ -412,14 +900,75 @@ func readResponseBody(r *http.Response) ([]byte, error) {
 	if r.Body == nil {
 		return []byte{}, nil
 	}
+	// buffered bodies are not consumed, so that they don't need to be reset
+	if b, ok := r.Body.(byteBody); ok {
+		return b.Bytes(), nil
+	}
+	if body, ok, err := readSeekableBody(r.Body); ok {
+		if err != nil {
+			return nil, &TransportError{Err: errors.Wrap(err, "error reading response body")}
+		}
+		return body, nil
+	}
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		return nil, errors.Wrap(err, "error reading response body")
+		return nil, &TransportError{Err: errors.Wrap(err, "error reading response body")}
 	}
 	r.Body = ioutil.NopCloser(bytes.NewReader(body))
 	return body, nil
 }
 
+// sizedReader is implemented by in-memory readers, e.g. *bytes.Reader and
+// *strings.Reader, which unread contents can be read without consuming them
+type sizedReader interface {
+	io.ReaderAt
+	Len() int
+	Size() int64
+}
+
+// readSeekableBody reads unread contents of body implementing io.ReaderAt
+// into a buffer of their exact size without consuming the body, so that it
+// doesn't need to be replaced with a copy. In-memory readers report their
+// size, while seekable ones, e.g. *os.File, are sized by seeking to the end
+// and rewound. Second return parameter reports if the body can be read so,
+// bodies which fail to report their offset aren't, e.g. pipes.
+func readSeekableBody(body io.Reader) ([]byte, bool, error) {
+	if r, ok := body.(sizedReader); ok {
+		b := make([]byte, r.Len())
+		_, err := r.ReadAt(b, r.Size()-int64(r.Len()))
+		if err == io.EOF {
+			err = nil
+		}
+		return b, true, err
+	}
+	seeker, ok := body.(io.Seeker)
+	if !ok {
+		return nil, false, nil
+	}
+	readerAt, ok := body.(io.ReaderAt)
+	if !ok {
+		return nil, false, nil
+	}
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, false, nil
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, true, err
+	}
+	_, err = seeker.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, true, err
+	}
+	b := make([]byte, end-offset)
+	_, err = readerAt.ReadAt(b, offset)
+	if err == io.EOF {
+		err = nil
+	}
+	return b, true, err
+}
+
 func getDecoder(contentType string) func([]byte) (interface{}, error) {
 	if strings.Contains(contentType, "json") {
 		return jsonDecoder
@@ -437,3 +986,19 @@ func jsonDecoder(b []byte) (decoded interface{}, err error) {
 	}
 	return
 }
+
+// jsonNumberDecoder decodes numbers as json.Number, so that documents which
+// are encoded again keep large integers and formatting of numbers
+func jsonNumberDecoder(b []byte) (decoded interface{}, err error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	err = d.Decode(&decoded)
+	if err == nil && d.More() {
+		err = errors.New("unexpected data after top-level value")
+	}
+	if err != nil {
+		err = errors.Wrap(err, "failed to decode json")
+		decoded = nil
+	}
+	return
+}
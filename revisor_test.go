@@ -3,12 +3,15 @@ package revisor
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,6 +36,21 @@ func TestVerifier(t *testing.T) {
 	assert.Regexp(t, "no path template matches current request", err)
 }
 
+func TestVerifier_UndocumentedRequest(t *testing.T) {
+	verifier, err := NewRequestVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	err = verifier(httptest.NewRequest("GET", "/v2/not-found", nil))
+	violation, ok := errors.Cause(err).(*Violation)
+	require.True(t, ok)
+	assert.Equal(t, CodeUndocumentedPath, violation.Code)
+
+	err = verifier(httptest.NewRequest("PATCH", "/v2/user/testuser", nil))
+	violation, ok = errors.Cause(err).(*Violation)
+	require.True(t, ok)
+	assert.Equal(t, CodeUndocumentedMethod, violation.Code)
+}
+
 func TestAPIVerifier_New(t *testing.T) {
 
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -385,3 +403,55 @@ func TestAPIVerifierV2_VerifyRequest(t *testing.T) {
 type brokenReader struct{}
 
 func (br *brokenReader) Read([]byte) (int, error) { return 0, assert.AnError }
+
+func TestAPIVerifier_VerifyRequestWithoutBody(t *testing.T) {
+	a, err := newInitializedVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/v2/user/testuser", nil)
+	assert.NoError(t, a.verifyRequest(req))
+	assert.Equal(t, http.NoBody, req.Body)
+
+	req = httptest.NewRequest("PUT", "/v2/user/testuser", nil)
+	assert.Regexp(t, "body is empty", a.verifyRequest(req))
+	assert.Equal(t, http.NoBody, req.Body)
+}
+
+func TestReadSeekableBody(t *testing.T) {
+	f, err := ioutil.TempFile("", "revisor-body")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	_, err = f.WriteString(`{"id":1}`)
+	require.NoError(t, err)
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("PUT", "/v2/user/testuser", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = f
+	req.ContentLength = -1
+	a, err := newInitializedVerifier(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	assert.NoError(t, a.verifyRequest(req))
+
+	assert.Equal(t, f, req.Body, "seekable body is rewound instead of replaced")
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, string(body))
+
+	t.Run("in-memory body", func(t *testing.T) {
+		reader := bytes.NewReader([]byte(`xx{"id":1}`))
+		_, err := reader.Seek(2, io.SeekStart)
+		require.NoError(t, err)
+		req := httptest.NewRequest("PUT", "/v2/user/testuser", nil)
+		req.Header.Set("Content-Type", "application/json")
+		req.Body = struct {
+			*bytes.Reader
+			io.Closer
+		}{reader, ioutil.NopCloser(nil)}
+		assert.NoError(t, a.verifyRequest(req))
+
+		assert.Equal(t, 8, reader.Len(), "in-memory body isn't consumed")
+	})
+}
@@ -0,0 +1,58 @@
+package revisortest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/krnkl/revisor"
+)
+
+// ContractCase is an entry of the contract test table, see RunContract
+type ContractCase struct {
+	Operation string
+	Method    string
+	Path      string
+	// Body is sent as JSON if it isn't empty
+	Body   string
+	Status int
+}
+
+// RunContract serves requests of cases with handler, each one in a subtest,
+// and verifies exchanges against OpenAPI definition. Cases which handler
+// responds to with other than the expected status are skipped.
+func RunContract(t *testing.T, definitionPath string, handler http.Handler, cases []ContractCase) {
+	verify, err := revisor.NewVerifier(definitionPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.Operation+" "+strconv.Itoa(c.Status), func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, contractRequest(c))
+			if rec.Code != c.Status {
+				t.Skipf("handler responded with %d", rec.Code)
+			}
+			err := verify(rec.Result(), contractRequest(c))
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func contractRequest(c ContractCase) *http.Request {
+	var body io.Reader
+	if c.Body != "" {
+		body = strings.NewReader(c.Body)
+	}
+	req := httptest.NewRequest(c.Method, c.Path, body)
+	if c.Body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
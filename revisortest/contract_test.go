@@ -0,0 +1,17 @@
+package revisortest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRunContract(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"username":"testuser"}`))
+	})
+	RunContract(t, "../internal/testdata/sample_open_api_v2.yaml", handler, []ContractCase{
+		{Operation: "getUserByName", Method: "GET", Path: "/v2/user/testuser", Status: 200},
+		{Operation: "getUserByName", Method: "GET", Path: "/v2/user/testuser", Status: 404},
+	})
+}
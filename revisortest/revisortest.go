@@ -0,0 +1,84 @@
+// Package revisortest provides fake verifiers for tests of applications
+// embedding revisor, so that their wiring can be tested without a definition,
+// and helpers running contract tests of handlers against a definition
+package revisortest
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/krnkl/revisor"
+)
+
+var (
+	_ revisor.RequestVerifier  = Noop{}
+	_ revisor.ResponseVerifier = Noop{}
+	_ revisor.PairVerifier     = Noop{}
+	_ revisor.RequestVerifier  = (*Recorder)(nil)
+	_ revisor.ResponseVerifier = (*Recorder)(nil)
+	_ revisor.PairVerifier     = (*Recorder)(nil)
+)
+
+// Noop is a verifier which accepts everything
+type Noop struct{}
+
+// VerifyRequest returns nil
+func (Noop) VerifyRequest(*http.Request) error {
+	return nil
+}
+
+// VerifyResponse returns nil
+func (Noop) VerifyResponse(*http.Response, *http.Request) error {
+	return nil
+}
+
+// Verify returns nil
+func (Noop) Verify(*http.Response, *http.Request) error {
+	return nil
+}
+
+// Call is a recorded call of a verifier method
+type Call struct {
+	// Method is a name of the called method, e.g. "VerifyRequest"
+	Method   string
+	Request  *http.Request
+	Response *http.Response
+}
+
+// Recorder is a verifier which records calls and returns Err from every one of them.
+// Recorder is safe for concurrent use.
+type Recorder struct {
+	Err error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// VerifyRequest records the call and returns Err
+func (r *Recorder) VerifyRequest(req *http.Request) error {
+	return r.record(Call{Method: "VerifyRequest", Request: req})
+}
+
+// VerifyResponse records the call and returns Err
+func (r *Recorder) VerifyResponse(res *http.Response, req *http.Request) error {
+	return r.record(Call{Method: "VerifyResponse", Request: req, Response: res})
+}
+
+// Verify records the call and returns Err
+func (r *Recorder) Verify(res *http.Response, req *http.Request) error {
+	return r.record(Call{Method: "Verify", Request: req, Response: res})
+}
+
+// Calls returns recorded calls in order they were made
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+func (r *Recorder) record(call Call) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+	return r.Err
+}
@@ -0,0 +1,34 @@
+package revisortest
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoop(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	res := httptest.NewRecorder().Result()
+	assert.NoError(t, Noop{}.VerifyRequest(req))
+	assert.NoError(t, Noop{}.VerifyResponse(res, req))
+	assert.NoError(t, Noop{}.Verify(res, req))
+}
+
+func TestRecorder(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	res := httptest.NewRecorder().Result()
+	errInvalid := errors.New("invalid")
+	r := &Recorder{Err: errInvalid}
+
+	assert.Equal(t, errInvalid, r.VerifyRequest(req))
+	assert.Equal(t, errInvalid, r.VerifyResponse(res, req))
+	assert.Equal(t, errInvalid, r.Verify(res, req))
+
+	assert.Equal(t, []Call{
+		{Method: "VerifyRequest", Request: req},
+		{Method: "VerifyResponse", Request: req, Response: res},
+		{Method: "Verify", Request: req, Response: res},
+	}, r.Calls())
+}
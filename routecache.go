@@ -0,0 +1,117 @@
+package revisor
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+)
+
+// WithRoutingCache caches templates matched by requests for up to size most
+// recently used pairs of method and path, so that repeated requests to the
+// same URLs aren't matched against every template again
+func WithRoutingCache(size int) option {
+	return func(a *apiVerifier) {
+		a.opts.routingCacheSize = size
+	}
+}
+
+// RoutingCacheStats describes usage of the cache enabled with WithRoutingCache
+type RoutingCacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Size is the number of cached pairs of method and path
+	Size int
+}
+
+// HitRate returns the fraction of lookups served from the cache
+func (s RoutingCacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// RoutingCacheStats returns usage of the routing cache, which is empty if
+// the cache isn't enabled. Reloading the definition starts a new cache.
+func (v *Verifier) RoutingCacheStats() RoutingCacheStats {
+	cache := v.verifier().mapper.cache
+	if cache == nil {
+		return RoutingCacheStats{}
+	}
+	return cache.stats()
+}
+
+// routeCache is LRU cache of templates matched by pairs of method and path
+type routeCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	hits    uint64
+	misses  uint64
+}
+
+type routeCacheEntry struct {
+	key   string
+	match templateMatch
+	ok    bool
+}
+
+func newRouteCache(size int) *routeCache {
+	return &routeCache{size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func routeCacheKey(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
+// get returns cached match of the request, second return parameter reports
+// if any template matches, the third one if the request is cached
+func (c *routeCache) get(r *http.Request) (templateMatch, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, cached := c.entries[routeCacheKey(r)]
+	if !cached {
+		c.misses++
+		return templateMatch{}, false, false
+	}
+	c.hits++
+	c.order.MoveToFront(e)
+	entry := e.Value.(*routeCacheEntry)
+	return entry.match.copy(), entry.ok, true
+}
+
+func (c *routeCache) put(r *http.Request, match templateMatch, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := routeCacheKey(r)
+	if e, cached := c.entries[key]; cached {
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&routeCacheEntry{key: key, match: match.copy(), ok: ok})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*routeCacheEntry).key)
+	}
+}
+
+func (c *routeCache) stats() RoutingCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return RoutingCacheStats{Hits: c.hits, Misses: c.misses, Size: c.order.Len()}
+}
+
+// copy returns the match with a copy of variables,
+// so that callers can't modify cached ones
+func (m templateMatch) copy() templateMatch {
+	if m.vars == nil {
+		return m
+	}
+	vars := make(map[string]string, len(m.vars))
+	for k, v := range m.vars {
+		vars[k] = v
+	}
+	return templateMatch{tmpl: m.tmpl, vars: vars}
+}
@@ -0,0 +1,56 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRoutingCache(t *testing.T) {
+	v, err := New(testdata+sampleV2YAML, WithRoutingCache(2))
+	require.NoError(t, err)
+	verify := func(method, url string) {
+		_ = v.VerifyRequest(httptest.NewRequest(method, url, nil))
+	}
+
+	verify("GET", "/v2/user/first")
+	assert.Equal(t, RoutingCacheStats{Misses: 1, Size: 1}, v.RoutingCacheStats(), "lookups are counted once per verification")
+
+	verify("GET", "/v2/user/first")
+	verify("GET", "/v2/not-found")
+	verify("GET", "/v2/not-found")
+	stats := v.RoutingCacheStats()
+	assert.Equal(t, RoutingCacheStats{Hits: 2, Misses: 2, Size: 2}, stats, "repeated paths are cached")
+	assert.Equal(t, 0.5, stats.HitRate())
+
+	verify("GET", "/v2/user/second")
+	verify("GET", "/v2/user/first")
+	assert.Equal(t, RoutingCacheStats{Hits: 2, Misses: 4, Size: 2}, v.RoutingCacheStats(), "least recently used path is evicted")
+}
+
+func TestRouteCache(t *testing.T) {
+	mapper := newSimpleMapper("/v2", map[string][]string{"GET": {"/user/{username}", "/user/login"}})
+	mapper.cache = newRouteCache(10)
+
+	for i := 0; i < 2; i++ {
+		tmpl, vars, ok := mapper.mapRequest(httptest.NewRequest("GET", "/v2/user/test", nil))
+		require.True(t, ok)
+		assert.Equal(t, "/user/{username}", tmpl)
+		assert.Equal(t, map[string]string{"username": "test"}, vars)
+		vars["username"] = "modified"
+
+		tmpl, _, ok = mapper.mapRequest(httptest.NewRequest("GET", "/v2/user/login", nil))
+		require.True(t, ok)
+		assert.Equal(t, "/user/login", tmpl)
+
+		_, _, ok = mapper.mapRequest(httptest.NewRequest("POST", "/v2/user/test", nil))
+		assert.False(t, ok)
+	}
+	assert.Equal(t, RoutingCacheStats{Hits: 3, Misses: 3, Size: 3}, mapper.cache.stats())
+
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	assert.Equal(t, RoutingCacheStats{}, v.RoutingCacheStats())
+}
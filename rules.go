@@ -0,0 +1,280 @@
+package revisor
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// requiresExt is a schema extension of objects which lists cross-field rules
+// checked after schema validation succeeds, e.g.
+//
+//	x-requires:
+//	  - end_date >= start_date
+//	  - if type == 'card' then card.number required
+//
+// A rule compares two operands with ==, !=, <, <=, > or >=, or is a
+// conditional rule, which requires a field or a comparison to hold when its
+// condition holds. Operands are dot-separated paths of fields of the object,
+// numbers, quoted strings, true, false and null. Comparisons of missing
+// fields are skipped, since presence of fields is checked by the schema.
+const requiresExt = "x-requires"
+
+// rule operators
+var ruleOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// checkRules checks rules declared with x-requires extensions
+// of the schema and its subschemas against decoded body
+func checkRules(schema *spec.Schema, decoded interface{}) error {
+	return walkBody(schema, decoded, "", checkSchemaRules)
+}
+
+func checkSchemaRules(schema *spec.Schema, value interface{}, pointer string) error {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, rule := range schemaRules(schema) {
+		ok, err := evalRule(rule, object)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse "+requiresExt+" rule "+strconv.Quote(rule))
+		}
+		if !ok {
+			return newViolation(CodeUnsatisfiedRule, bodyField(pointer)+" in body does not satisfy "+strconv.Quote(rule))
+		}
+	}
+	return nil
+}
+
+// schemaRules returns rules listed in x-requires extension
+func schemaRules(schema *spec.Schema) []string {
+	switch rules := schema.Extensions[requiresExt].(type) {
+	case string:
+		return []string{rules}
+	case []interface{}:
+		var list []string
+		for _, rule := range rules {
+			if rule, ok := rule.(string); ok {
+				list = append(list, rule)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// evalRule checks if the object satisfies the rule
+func evalRule(rule string, object map[string]interface{}) (bool, error) {
+	tokens, err := ruleTokens(rule)
+	if err != nil {
+		return false, err
+	}
+	if len(tokens) == 0 || tokens[0] != "if" {
+		holds, _, err := evalRuleTerm(tokens, object)
+		return holds, err
+	}
+	then := -1
+	for i, token := range tokens {
+		if token == "then" {
+			then = i
+			break
+		}
+	}
+	if then < 0 {
+		return false, errors.New("then is expected")
+	}
+	holds, present, err := evalRuleTerm(tokens[1:then], object)
+	if err != nil {
+		return false, err
+	}
+	if !present || !holds {
+		_, _, err = evalRuleTerm(tokens[then+1:], object)
+		return true, err
+	}
+	holds, _, err = evalRuleTerm(tokens[then+1:], object)
+	return holds, err
+}
+
+// evalRuleTerm evaluates either a comparison or a field followed by required
+// keyword. The second return value is false if compared fields are missing.
+func evalRuleTerm(tokens []string, object map[string]interface{}) (bool, bool, error) {
+	if len(tokens) == 2 && tokens[1] == "required" {
+		if !isRuleField(tokens[0]) {
+			return false, false, errors.New("field is expected before required")
+		}
+		_, ok := ruleOperand(tokens[0], object)
+		return ok, true, nil
+	}
+	if len(tokens) != 3 || !isRuleOperator(tokens[1]) {
+		return false, false, errors.New("comparison is expected")
+	}
+	left, ok := ruleOperand(tokens[0], object)
+	if !ok {
+		return true, false, nil
+	}
+	right, ok := ruleOperand(tokens[2], object)
+	if !ok {
+		return true, false, nil
+	}
+	return compareRuleOperands(left, tokens[1], right), true, nil
+}
+
+// compareRuleOperands compares numbers, strings and dates in order, and any
+// other values for equality only
+func compareRuleOperands(left interface{}, operator string, right interface{}) bool {
+	cmp, ordered := 0, false
+	left, right = ruleNumber(left), ruleNumber(right)
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			cmp, ordered = compareFloats(l, r), true
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			cmp, ordered = compareStrings(l, r), true
+		}
+	}
+	switch operator {
+	case "==":
+		return ordered && cmp == 0 || !ordered && reflect.DeepEqual(left, right)
+	case "!=":
+		return ordered && cmp != 0 || !ordered && !reflect.DeepEqual(left, right)
+	case ">=":
+		return ordered && cmp >= 0
+	case "<=":
+		return ordered && cmp <= 0
+	case ">":
+		return ordered && cmp > 0
+	case "<":
+		return ordered && cmp < 0
+	}
+	return false
+}
+
+// ruleNumber converts json.Number of bodies decoded by binders to float64
+func ruleNumber(value interface{}) interface{} {
+	if n, ok := value.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+func compareFloats(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+// compareStrings compares date-times and dates by time
+// and other strings lexicographically
+func compareStrings(l, r string) int {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		lt, lerr := time.Parse(layout, l)
+		rt, rerr := time.Parse(layout, r)
+		if lerr == nil && rerr == nil {
+			switch {
+			case lt.Before(rt):
+				return -1
+			case lt.After(rt):
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(l, r)
+}
+
+// ruleOperand returns value of the literal or the field of the object
+func ruleOperand(token string, object map[string]interface{}) (interface{}, bool) {
+	switch token {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+	if token[0] == '"' || token[0] == '\'' {
+		return token[1 : len(token)-1], true
+	}
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return number, true
+	}
+	return valueAt(object, strings.Split(token, "."))
+}
+
+func isRuleField(token string) bool {
+	switch token {
+	case "true", "false", "null":
+		return false
+	}
+	if token[0] == '"' || token[0] == '\'' || isRuleOperator(token) {
+		return false
+	}
+	_, err := strconv.ParseFloat(token, 64)
+	return err != nil
+}
+
+func isRuleOperator(token string) bool {
+	for _, operator := range ruleOperators {
+		if token == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleTokens splits the rule into operators, quoted strings and words
+func ruleTokens(rule string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(rule); {
+		c := rule[i]
+		if c == ' ' || c == '\t' {
+			i++
+			continue
+		}
+		if c == '"' || c == '\'' {
+			end := strings.IndexByte(rule[i+1:], c)
+			if end < 0 {
+				return nil, errors.New("string is not terminated")
+			}
+			tokens = append(tokens, rule[i:i+end+2])
+			i += end + 2
+			continue
+		}
+		if operator := ruleOperatorAt(rule[i:]); operator != "" {
+			tokens = append(tokens, operator)
+			i += len(operator)
+			continue
+		}
+		start := i
+		for i < len(rule) && !strings.ContainsRune(" \t\"'", rune(rule[i])) && ruleOperatorAt(rule[i:]) == "" {
+			i++
+		}
+		if start == i {
+			return nil, errors.New("unexpected " + string(c))
+		}
+		tokens = append(tokens, rule[start:i])
+	}
+	return tokens, nil
+}
+
+func ruleOperatorAt(s string) string {
+	for _, operator := range ruleOperators {
+		if strings.HasPrefix(s, operator) {
+			return operator
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			body: `{"start_date": "2018-01-01", "end_date": "2018-01-02", "payment": {"type": "card", "card_number": "4111"}}`,
+		},
+		{
+			name:    "end before start",
+			body:    `{"start_date": "2018-01-02", "end_date": "2018-01-01"}`,
+			wantErr: `/ in body does not satisfy "end_date >= start_date"`,
+		},
+		{
+			name: "missing operand",
+			body: `{"end_date": "2018-01-01"}`,
+		},
+		{
+			name:    "condition holds",
+			body:    `{"payment": {"type": "card"}}`,
+			wantErr: `/ in body does not satisfy "if payment.type == 'card' then payment.card_number required"`,
+		},
+		{
+			name: "condition doesn't hold",
+			body: `{"payment": {"type": "cash"}}`,
+		},
+		{
+			name:    "nested object",
+			body:    `{"guests": [{"age": 30}, {"age": 0}]}`,
+			wantErr: `/guests/1 in body does not satisfy "age > 0"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata + "rules.yaml")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "/v1/bookings", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			wantCode := ""
+			if tt.wantErr != "" {
+				wantCode = CodeUnsatisfiedRule
+			}
+			assertVerifyErr(t, v.VerifyRequest(req), tt.wantErr, wantCode)
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			rec.WriteHeader(201)
+			rec.Body = bytes.NewBufferString(tt.body)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), tt.wantErr, wantCode)
+		})
+	}
+}
+
+func TestEvalRule(t *testing.T) {
+	object := map[string]interface{}{
+		"count":   float64(2),
+		"total":   json.Number("3"),
+		"name":    "b",
+		"active":  true,
+		"created": "2018-01-01T12:00:00+02:00",
+		"updated": "2018-01-01T11:00:00Z",
+		"nested":  map[string]interface{}{"value": nil},
+	}
+	tests := []struct {
+		rule    string
+		want    bool
+		wantErr string
+	}{
+		{rule: "count==2", want: true},
+		{rule: "count != 2"},
+		{rule: "count < 10", want: true},
+		{rule: "count <= -1"},
+		{rule: "total > count", want: true},
+		{rule: `name > "a"`, want: true},
+		{rule: "active == true", want: true},
+		{rule: "nested.value == null", want: true},
+		{rule: "updated > created", want: true},
+		{rule: "name > 1"},
+		{rule: "name != 1", want: true},
+		{rule: "missing > 1", want: true},
+		{rule: "if active then name required", wantErr: "comparison is expected"},
+		{rule: "if active == true then missing required"},
+		{rule: "if active == false then missing required", want: true},
+		{rule: "if missing == 1 then count > 5", want: true},
+		{rule: "if count == 2 then count > 5"},
+		{rule: "if count == 2", wantErr: "then is expected"},
+		{rule: "1 required", wantErr: "field is expected before required"},
+		{rule: "name == 'a", wantErr: "string is not terminated"},
+		{rule: "count", wantErr: "comparison is expected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := evalRule(tt.rule, object)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
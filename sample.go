@@ -0,0 +1,99 @@
+package revisor
+
+import (
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// maxSampleDepth limits how deep nested samples are generated,
+// so that recursive schemas produce finite values
+const maxSampleDepth = 8
+
+// formatSamples are sample values of string formats
+var formatSamples = map[string]string{
+	"date":      "1970-01-01",
+	"date-time": "1970-01-01T00:00:00Z",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "127.0.0.1",
+	"ipv6":      "::1",
+	"uri":       "http://example.com",
+	"url":       "http://example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"byte":      "",
+	"password":  "password",
+}
+
+// sampleValue returns a deterministic value of the schema: documented example,
+// default or enum value if there is one, otherwise the smallest value
+// satisfying type, format and range constraints
+func sampleValue(schema *spec.Schema) interface{} {
+	return sampleValueDepth(schema, 0)
+}
+
+func sampleValueDepth(schema *spec.Schema, depth int) interface{} {
+	if sample, ok := documentedSample(schema); ok {
+		return sample
+	}
+	if len(schema.Enum) != 0 {
+		return schema.Enum[0]
+	}
+	switch {
+	case schema.Type.Contains("object") || len(schema.Properties) != 0 || len(schema.AllOf) != 0:
+		object := make(map[string]interface{})
+		if depth >= maxSampleDepth {
+			return object
+		}
+		props, _, _ := declaredProperties(schema)
+		for name, prop := range props {
+			object[name] = sampleValueDepth(prop, depth+1)
+		}
+		return object
+	case schema.Type.Contains("array"):
+		items := []interface{}{}
+		if schema.Items == nil || schema.Items.Schema == nil || depth >= maxSampleDepth {
+			return items
+		}
+		count := int64(0)
+		if schema.MinItems != nil {
+			count = *schema.MinItems
+		}
+		for i := int64(0); i < count; i++ {
+			items = append(items, sampleValueDepth(schema.Items.Schema, depth+1))
+		}
+		return items
+	case schema.Type.Contains("integer"), schema.Type.Contains("number"):
+		return sampleNumber(schema)
+	case schema.Type.Contains("boolean"):
+		return false
+	case schema.Type.Contains("string"):
+		s := formatSamples[schema.Format]
+		if schema.MinLength != nil && int64(len(s)) < *schema.MinLength {
+			s += strings.Repeat("x", int(*schema.MinLength)-len(s))
+		}
+		return s
+	}
+	return nil
+}
+
+// sampleNumber returns the number closest to 0 within schema range
+func sampleNumber(schema *spec.Schema) float64 {
+	n := 0.0
+	if schema.Minimum != nil && n <= *schema.Minimum {
+		n = *schema.Minimum
+		if schema.ExclusiveMinimum {
+			n++
+		}
+	}
+	if schema.Maximum != nil && n >= *schema.Maximum {
+		n = *schema.Maximum
+		if schema.ExclusiveMaximum {
+			n--
+		}
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf != 0 {
+		n = float64(int64(n / *schema.MultipleOf)) * *schema.MultipleOf
+	}
+	return n
+}
@@ -0,0 +1,167 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// SanitizeReport lists request parts removed by the sanitizer
+type SanitizeReport struct {
+	// Query holds names of removed query parameters
+	Query []string
+	// Body holds JSON pointers of removed body properties
+	Body []string
+}
+
+// Empty reports if nothing was removed from the request
+func (r *SanitizeReport) Empty() bool {
+	return len(r.Query) == 0 && len(r.Body) == 0
+}
+
+// NewRequestSanitizer returns a function that removes query parameters and
+// JSON body properties which are not declared in OpenAPI definition from the
+// request, instead of rejecting it. Returned report lists what was removed.
+func NewRequestSanitizer(definitionPath string, options ...option) (func(*http.Request) (*SanitizeReport, error), error) {
+	a, err := newInitializedVerifier(definitionPath, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create sanitizer function")
+	}
+	return a.sanitizeRequest, nil
+}
+
+// sanitizeRequest removes undeclared query parameters and body properties
+func (a *apiVerifier) sanitizeRequest(req *http.Request) (*SanitizeReport, error) {
+	if req == nil {
+		return nil, ErrNilInput
+	}
+	pathDef, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return nil, err
+	}
+	params := operationParams(pathDef, operation)
+	report := &SanitizeReport{}
+
+	declared := make(map[string]bool)
+	for _, param := range params {
+		if param.In == "query" {
+			declared[param.Name] = true
+		}
+	}
+	query := req.URL.Query()
+	for name := range query {
+		if !declared[name] {
+			query.Del(name)
+			report.Query = append(report.Query, name)
+		}
+	}
+	if len(report.Query) != 0 {
+		sort.Strings(report.Query)
+		req.URL.RawQuery = query.Encode()
+	}
+
+	bodyParam := getBodyParameter(params)
+	if bodyParam == nil || bodyParam.Schema == nil || !strings.Contains(req.Header.Get("Content-Type"), "json") {
+		return report, nil
+	}
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sanitize request")
+	}
+	if len(body) == 0 {
+		return report, nil
+	}
+	decoded, err := jsonNumberDecoder(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+	report.Body = stripUndeclared(bodyParam.Schema, decoded, "", nil)
+	if len(report.Body) == 0 {
+		return report, nil
+	}
+	sort.Strings(report.Body)
+	body, err = json.Marshal(decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode request")
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	if req.Header.Get("Content-Length") != "" {
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	return report, nil
+}
+
+// stripUndeclared removes object properties not declared by the schema
+// from the decoded value and returns JSON pointers of removed properties
+func stripUndeclared(schema *spec.Schema, value interface{}, pointer string, stripped []string) []string {
+	if schema == nil {
+		return stripped
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, additional, described := declaredProperties(schema)
+		for name, prop := range v {
+			propSchema, ok := props[name]
+			if !ok {
+				propSchema = additional
+			}
+			if propSchema != nil {
+				stripped = stripUndeclared(propSchema, prop, pointer+"/"+escapePointerToken(name), stripped)
+				continue
+			}
+			if described {
+				delete(v, name)
+				stripped = append(stripped, pointer+"/"+escapePointerToken(name))
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i, item := range v {
+				stripped = stripUndeclared(schema.Items.Schema, item, pointer+"/"+strconv.Itoa(i), stripped)
+			}
+		}
+	}
+	return stripped
+}
+
+// declaredProperties collects properties declared by the schema and its allOf members.
+// additional is a schema of additional properties if it is defined, described reports
+// if undeclared properties are not allowed by the schema
+func declaredProperties(schema *spec.Schema) (props map[string]*spec.Schema, additional *spec.Schema, described bool) {
+	props = make(map[string]*spec.Schema)
+	described = true
+	var collect func(s *spec.Schema)
+	collect = func(s *spec.Schema) {
+		for name := range s.Properties {
+			prop := s.Properties[name]
+			props[name] = &prop
+		}
+		if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.Schema != nil {
+				additional = s.AdditionalProperties.Schema
+			} else if s.AdditionalProperties.Allows {
+				described = false
+			}
+		}
+		for i := range s.AllOf {
+			collect(&s.AllOf[i])
+		}
+	}
+	collect(schema)
+	if len(props) == 0 && additional == nil {
+		described = false
+	}
+	return props, additional, described
+}
+
+func escapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
@@ -0,0 +1,85 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestSanitizer(t *testing.T) {
+	sanitizer, err := NewRequestSanitizer(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	t.Run("strips undeclared query parameters and properties", func(t *testing.T) {
+		payload := []byte(`{"id":1,"username":"test-user","additional_field":"value","nested":{"key":"value"}}`)
+		req, err := http.NewRequest("PUT", "/v2/user/testuser?debug=1&trace=true", bytes.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		report, err := sanitizer(req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"debug", "trace"}, report.Query)
+		assert.Equal(t, []string{"/additional_field", "/nested"}, report.Body)
+		assert.Empty(t, req.URL.RawQuery)
+
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &decoded))
+		assert.Equal(t, map[string]interface{}{"id": float64(1), "username": "test-user"}, decoded)
+		assert.Equal(t, int64(len(body)), req.ContentLength)
+	})
+
+	t.Run("keeps numbers as they are", func(t *testing.T) {
+		payload := []byte(`{"id":9007199254740993,"username":"test-user","score":1.50}`)
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", bytes.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		report, err := sanitizer(req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/score"}, report.Body)
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":9007199254740993,"username":"test-user"}`, string(body))
+	})
+
+	t.Run("keeps declared parts", func(t *testing.T) {
+		payload := []byte(`{"id":1,"username":"test-user"}`)
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", bytes.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		report, err := sanitizer(req)
+		require.NoError(t, err)
+		assert.True(t, report.Empty())
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, payload, body)
+	})
+
+	t.Run("keeps additional properties", func(t *testing.T) {
+		stripped := stripUndeclared(
+			spec.MapProperty(spec.Int32Property()),
+			map[string]interface{}{"sold": float64(1)},
+			"",
+			nil,
+		)
+		assert.Empty(t, stripped)
+	})
+
+	t.Run("fails to decode request body", func(t *testing.T) {
+		req, err := http.NewRequest("PUT", "/v2/user/testuser", bytes.NewReader([]byte("invalid-json")))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		_, err = sanitizer(req)
+		assert.Regexp(t, "failed to decode request", err)
+	})
+}
@@ -0,0 +1,69 @@
+package revisor
+
+import (
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// SchemaAt returns schema governing the body location at JSON pointer, e.g.
+// "/tags/0/name", of the response with status of the operation identified by
+// operationID. Request body schema is looked up if status is 0. Returned schema
+// has references resolved, so that its description and constraints can be shown
+// next to violations.
+func (v *Verifier) SchemaAt(operationID string, status int, pointer string) (*spec.Schema, error) {
+	a := v.verifier()
+	_, _, operation, params := a.operationByID(operationID)
+	if operation == nil {
+		return nil, errors.New("operation is not defined: " + operationID)
+	}
+
+	var schema *spec.Schema
+	if status == 0 {
+		if body := getBodyParameter(params); body != nil {
+			schema = body.Schema
+		}
+	} else {
+		response, err := a.responseByStatus(status, operation)
+		if err != nil {
+			return nil, err
+		}
+		schema = response.Schema
+	}
+	if schema == nil {
+		return nil, errors.New("body schema is not defined")
+	}
+
+	var tokens []string
+	if pointer != "" && pointer != "/" {
+		if !strings.HasPrefix(pointer, "/") {
+			return nil, errors.New("invalid JSON pointer: " + pointer)
+		}
+		for _, token := range strings.Split(pointer[1:], "/") {
+			tokens = append(tokens, strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1))
+		}
+	}
+	governing, _, ok := schemaAt(a.doc.Spec(), schema, "", tokens)
+	if !ok {
+		return nil, errors.New("no schema is defined at " + pointer)
+	}
+	return governing, nil
+}
+
+// operationByID returns path template, method and operation with the id
+// and parameters defined for it on both path item and operation
+func (a *apiVerifier) operationByID(operationID string) (string, string, *spec.Operation, []spec.Parameter) {
+	for _, path := range sortedPaths(a.doc.Spec()) {
+		pathItem := a.doc.Spec().Paths.Paths[path]
+		for _, method := range httpMethods {
+			operation := pathOperation(method, &pathItem)
+			if operation != nil && operation.ID == operationID {
+				params := make([]spec.Parameter, 0, len(operation.Parameters)+len(pathItem.Parameters))
+				params = append(params, operation.Parameters...)
+				return path, method, operation, append(params, pathItem.Parameters...)
+			}
+		}
+	}
+	return "", "", nil, nil
+}
@@ -0,0 +1,46 @@
+package revisor
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_SchemaAt(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		operationID string
+		status      int
+		pointer     string
+		wantType    spec.StringOrArray
+		wantFormat  string
+		wantErr     string
+	}{
+		{name: "request body property", operationID: "addPet", pointer: "/id", wantType: spec.StringOrArray{"integer"}, wantFormat: "int64"},
+		{name: "request array item", operationID: "addPet", pointer: "/photoUrls/0", wantType: spec.StringOrArray{"string"}},
+		{name: "nested property", operationID: "addPet", pointer: "/category/name", wantType: spec.StringOrArray{"string"}},
+		{name: "response property through allOf", operationID: "getUserByName", status: 200, pointer: "/email", wantType: spec.StringOrArray{"string"}, wantFormat: "email"},
+		{name: "whole body", operationID: "addPet", pointer: "", wantType: spec.StringOrArray{"object"}},
+		{name: "unknown operation", operationID: "unknown", wantErr: "operation is not defined: unknown"},
+		{name: "unknown property", operationID: "addPet", pointer: "/unknown", wantErr: "no schema is defined at /unknown"},
+		{name: "invalid pointer", operationID: "addPet", pointer: "id", wantErr: "invalid JSON pointer: id"},
+		{name: "no body", operationID: "getInventory", wantErr: "body schema is not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := v.SchemaAt(tt.operationID, tt.status, tt.pointer)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, schema.Type)
+			assert.Equal(t, tt.wantFormat, schema.Format)
+		})
+	}
+}
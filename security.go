@@ -0,0 +1,94 @@
+package revisor
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// tokenEndpointExt is an operation extension which marks the operation as
+// an endpoint issuing tokens, in addition to OAuth2 token URLs declared in
+// security definitions, e.g.
+//
+//	x-token-endpoint: true
+const tokenEndpointExt = "x-token-endpoint"
+
+// CheckSecurityHeaders enables security hygiene checks of responses: token
+// endpoints must not set cookies unless Set-Cookie header is declared, and
+// responses with a body must have Content-Type header. If nosniff is set,
+// responses must also have X-Content-Type-Options: nosniff header. Findings
+// are logged as warnings unless RejectInsecureResponses is set.
+func CheckSecurityHeaders(nosniff bool) option {
+	return func(a *apiVerifier) {
+		a.opts.checkSecurity = true
+		a.opts.requireNosniff = nosniff
+	}
+}
+
+// RejectInsecureResponses makes findings of security hygiene checks enabled
+// with CheckSecurityHeaders reported as Violation with CodeInsecureResponse
+// code instead of warnings.
+func RejectInsecureResponses(a *apiVerifier) {
+	a.opts.rejectInsecure = true
+}
+
+// verifySecurityHeaders warns about or rejects insecure response
+func (a *apiVerifier) verifySecurityHeaders(req *http.Request, res *http.Response, body []byte) error {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	finding := ""
+	switch {
+	case len(body) != 0 && res.Header.Get("Content-Type") == "":
+		finding = "Content-Type header is missing"
+	case a.opts.requireNosniff && !strings.EqualFold(res.Header.Get("X-Content-Type-Options"), "nosniff"):
+		finding = "X-Content-Type-Options header must be nosniff"
+	case len(res.Header[http.CanonicalHeaderKey("Set-Cookie")]) != 0 &&
+		a.isTokenEndpoint(req, operation) && !a.declaresHeader(operation, res.StatusCode, "Set-Cookie"):
+		finding = "token endpoint sets undeclared cookies"
+	}
+	if finding == "" {
+		return nil
+	}
+	if a.opts.rejectInsecure {
+		return newViolation(CodeInsecureResponse, finding)
+	}
+	a.opts.logf("revisor: warning: insecure response of %s: %s", a.operationKey(req, operation), finding)
+	return nil
+}
+
+// isTokenEndpoint checks if the operation is declared with x-token-endpoint
+// extension or the request is sent to token URL of OAuth2 security definition
+func (a *apiVerifier) isTokenEndpoint(req *http.Request, operation *spec.Operation) bool {
+	if token, _ := operation.Extensions[tokenEndpointExt].(bool); token {
+		return true
+	}
+	for _, scheme := range a.doc.Spec().SecurityDefinitions {
+		if scheme.Type != "oauth2" || scheme.TokenURL == "" {
+			continue
+		}
+		tokenURL, err := url.Parse(scheme.TokenURL)
+		if err == nil && tokenURL.Path == req.URL.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// declaresHeader checks if response of the operation with the status
+// declares the header
+func (a *apiVerifier) declaresHeader(operation *spec.Operation, status int, header string) bool {
+	response, err := a.responseByStatus(status, operation)
+	if err != nil {
+		return false
+	}
+	for name := range response.Headers {
+		if strings.EqualFold(name, header) {
+			return true
+		}
+	}
+	return false
+}
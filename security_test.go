@@ -0,0 +1,122 @@
+package revisor
+
+import (
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSecurityHeaders(t *testing.T) {
+	operationIDs := map[string]string{
+		"/v1/oauth/token": "issueToken",
+		"/v1/login":       "login",
+		"/v1/profile":     "getProfile",
+	}
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		headers     map[string]string
+		body        string
+		nosniff     bool
+		wantFinding string
+	}{
+		{
+			name:    "secure",
+			path:    "/v1/profile",
+			headers: map[string]string{"Content-Type": "application/json"},
+			body:    `{}`,
+		},
+		{
+			name: "missing Content-Type",
+			path: "/v1/profile",
+			body: `{}`, wantFinding: "Content-Type header is missing",
+		},
+		{
+			name:    "cookie of token URL",
+			path:    "/v1/oauth/token",
+			headers: map[string]string{"Content-Type": "application/json", "Set-Cookie": "sid=1"},
+			body:    `{}`, wantFinding: "token endpoint sets undeclared cookies",
+		},
+		{
+			name:    "cookie of token endpoint",
+			path:    "/v1/login",
+			headers: map[string]string{"Content-Type": "application/json", "Set-Cookie": "sid=1"},
+			body:    `{}`, wantFinding: "token endpoint sets undeclared cookies",
+		},
+		{
+			name:    "declared cookie",
+			path:    "/v1/sessions",
+			status:  201,
+			headers: map[string]string{"Content-Type": "application/json", "Set-Cookie": "sid=1"},
+			body:    `{}`,
+		},
+		{
+			name:    "cookie of other endpoint",
+			path:    "/v1/profile",
+			headers: map[string]string{"Content-Type": "application/json", "Set-Cookie": "theme=dark"},
+			body:    `{}`,
+		},
+		{
+			name:    "nosniff",
+			path:    "/v1/profile",
+			headers: map[string]string{"Content-Type": "application/json", "X-Content-Type-Options": "nosniff"},
+			body:    `{}`, nosniff: true,
+		},
+		{
+			name:    "missing nosniff",
+			path:    "/v1/profile",
+			headers: map[string]string{"Content-Type": "application/json"},
+			body:    `{}`, nosniff: true, wantFinding: "X-Content-Type-Options header must be nosniff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := "POST"
+			if tt.path == "/v1/profile" {
+				method = "GET"
+			}
+			status := tt.status
+			if status == 0 {
+				status = 200
+			}
+			newResponse := func() *httptest.ResponseRecorder {
+				rec := httptest.NewRecorder()
+				for k, v := range tt.headers {
+					rec.Header().Set(k, v)
+				}
+				rec.WriteHeader(status)
+				rec.Body = bytes.NewBufferString(tt.body)
+				return rec
+			}
+
+			var logs []string
+			logf := func(format string, args ...interface{}) {
+				logs = append(logs, fmt.Sprintf(format, args...))
+			}
+			v, err := New(testdata+"security.yaml", CheckSecurityHeaders(tt.nosniff), WithLogger(logf))
+			require.NoError(t, err)
+			logs = nil
+			err = v.verifier().verifySecurityHeaders(httptest.NewRequest(method, tt.path, nil), newResponse().Result(), []byte(tt.body))
+			assert.NoError(t, err, "findings are warnings by default")
+			if tt.wantFinding != "" {
+				assert.Equal(t, []string{"revisor: warning: insecure response of " + operationIDs[tt.path] + ": " + tt.wantFinding}, logs)
+			} else {
+				assert.Empty(t, logs)
+			}
+
+			v, err = New(testdata+"security.yaml", CheckSecurityHeaders(tt.nosniff), RejectInsecureResponses)
+			require.NoError(t, err)
+			err = v.VerifyResponse(newResponse().Result(), httptest.NewRequest(method, tt.path, nil))
+			if tt.wantFinding != "" {
+				assertVerifyErr(t, err, tt.wantFinding, CodeInsecureResponse)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package revisor
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// WithSemanticValidator attaches domain check to requests of the operation
+// identified by operationID, e.g. referential integrity or business
+// invariants. The check runs after the request is verified against the
+// definition, with decoded body, which is nil for operations without body,
+// and request parameters. Its error fails verification of the request, so
+// it passes through stages and metrics as any other verification error.
+// Several checks of the operation run in the order they are added.
+func WithSemanticValidator(operationID string, validate func(decoded interface{}, params Params) error) option {
+	return func(a *apiVerifier) {
+		if a.opts.semanticValidators == nil {
+			a.opts.semanticValidators = make(map[string][]func(interface{}, Params) error)
+		}
+		a.opts.semanticValidators[operationID] = append(a.opts.semanticValidators[operationID], validate)
+	}
+}
+
+// verifySemantics runs semantic validators of the operation of the request
+func (a *apiVerifier) verifySemantics(req *http.Request, decoded interface{}) error {
+	if len(a.opts.semanticValidators) == 0 {
+		return nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	validators := a.opts.semanticValidators[operation.ID]
+	if len(validators) == 0 {
+		return nil
+	}
+	params := a.requestParams(req)
+	for _, validate := range validators {
+		if err := validate(decoded, params); err != nil {
+			return errors.Wrap(err, "semantic validation of "+operation.ID+" failed")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSemanticValidator(t *testing.T) {
+	var calls []string
+	sameUser := func(decoded interface{}, params Params) error {
+		calls = append(calls, "sameUser")
+		body := decoded.(map[string]interface{})
+		if body["username"] != params.Path["username"] {
+			return newViolation("username_mismatch", "username of body doesn't match path")
+		}
+		return nil
+	}
+	notLocked := func(decoded interface{}, params Params) error {
+		calls = append(calls, "notLocked")
+		if params.Query.Get("locked") == "true" {
+			return errors.New("user is locked")
+		}
+		return nil
+	}
+	tests := []struct {
+		name      string
+		method    string
+		url       string
+		body      string
+		wantErr   string
+		wantCode  string
+		wantCalls []string
+	}{
+		{
+			name:   "valid",
+			method: "PUT", url: "/v2/user/testuser",
+			body:      `{"id": 1, "username": "testuser"}`,
+			wantCalls: []string{"sameUser", "notLocked"},
+		},
+		{
+			name:   "violation",
+			method: "PUT", url: "/v2/user/testuser",
+			body:      `{"id": 1, "username": "other"}`,
+			wantErr:   "semantic validation of updateUser failed: username_mismatch: username of body doesn't match path",
+			wantCode:  "username_mismatch",
+			wantCalls: []string{"sameUser"},
+		},
+		{
+			name:   "error",
+			method: "PUT", url: "/v2/user/testuser?locked=true",
+			body:      `{"id": 1, "username": "testuser"}`,
+			wantErr:   "semantic validation of updateUser failed: user is locked",
+			wantCalls: []string{"sameUser", "notLocked"},
+		},
+		{
+			name:   "invalid by definition",
+			method: "PUT", url: "/v2/user/testuser",
+			body:    `{"id": 1, "username": 1}`,
+			wantErr: "username in body must be of type string",
+		},
+		{
+			name:   "other operation",
+			method: "GET", url: "/v2/user/testuser",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			v, err := New(testdata+sampleV2YAML,
+				WithSemanticValidator("updateUser", sameUser),
+				WithSemanticValidator("updateUser", notLocked),
+			)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			assertVerifyErr(t, v.VerifyRequest(req), tt.wantErr, tt.wantCode)
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package revisor
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// sloLatencyExt is an operation extension which annotates the operation with
+// latency budget in milliseconds, e.g.
+//
+//	x-slo-latency-ms: 250
+const sloLatencyExt = "x-slo-latency-ms"
+
+// VerifyLatency checks if the request was handled within latency budget
+// annotated with x-slo-latency-ms extension of its operation. Exceeded budget
+// is reported as Violation with CodeSLOExceeded code. ValidatedResponseWriter
+// measures handling time and checks it on Commit.
+func (v *Verifier) VerifyLatency(req *http.Request, handled time.Duration) error {
+	if req == nil {
+		return ErrNilInput
+	}
+	return v.verifier().verifyLatency(req, handled)
+}
+
+func (a *apiVerifier) verifyLatency(req *http.Request, handled time.Duration) error {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return err
+	}
+	budget, ok, err := operationSLOLatency(operation)
+	if err != nil || !ok {
+		return err
+	}
+	if handled > budget {
+		return newViolation(CodeSLOExceeded, "handled in "+handled.String()+", which exceeds latency budget of "+budget.String())
+	}
+	return nil
+}
+
+func operationSLOLatency(operation *spec.Operation) (time.Duration, bool, error) {
+	ext, ok := operation.Extensions[sloLatencyExt]
+	if !ok {
+		return 0, false, nil
+	}
+	ms, ok := ext.(float64)
+	if !ok || ms <= 0 {
+		return 0, false, errors.New("failed to parse " + sloLatencyExt + ": must be a positive number")
+	}
+	return time.Duration(ms * float64(time.Millisecond)), true, nil
+}
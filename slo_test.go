@@ -0,0 +1,94 @@
+package revisor
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyLatency(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		handled  time.Duration
+		wantErr  string
+		wantCode string
+	}{
+		{
+			name:    "within budget",
+			path:    "/v1/orders",
+			handled: 250 * time.Millisecond,
+		},
+		{
+			name:     "over budget",
+			path:     "/v1/orders",
+			handled:  251 * time.Millisecond,
+			wantCode: CodeSLOExceeded,
+		},
+		{
+			name:    "not annotated",
+			path:    "/v1/reports",
+			handled: time.Minute,
+		},
+		{
+			name:    "invalid annotation",
+			path:    "/v1/invoices",
+			wantErr: "failed to parse x-slo-latency-ms",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata + "slo.yaml")
+			require.NoError(t, err)
+			err = v.VerifyLatency(httptest.NewRequest("GET", tt.path, nil), tt.handled)
+			assertVerifyErr(t, err, tt.wantErr, tt.wantCode)
+		})
+	}
+}
+
+func TestValidatedResponseWriter_Latency(t *testing.T) {
+	tests := []struct {
+		name     string
+		handled  time.Duration
+		body     string
+		wantErr  string
+		wantCode string
+	}{
+		{
+			name:    "within budget",
+			handled: 100 * time.Millisecond,
+			body:    `[]`,
+		},
+		{
+			name:     "over budget",
+			handled:  time.Second,
+			body:     `[]`,
+			wantCode: CodeSLOExceeded,
+		},
+		{
+			name:    "invalid response over budget",
+			handled: time.Second,
+			body:    `{}`,
+			wantErr: "must be of type array",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewManualClock(clockEpoch)
+			newWriter, err := NewResponseWriterFactory(testdata+"slo.yaml", WithClock(clock))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			w := newWriter(rec, httptest.NewRequest("GET", "/v1/orders", nil))
+			clock.Advance(tt.handled)
+			w.Header().Set("Content-Type", "application/json")
+			_, err = w.Write([]byte(tt.body))
+			require.NoError(t, err)
+			assertVerifyErr(t, w.Commit(), tt.wantErr, tt.wantCode)
+			assert.Equal(t, tt.body, rec.Body.String(), "response is written")
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package revisor
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// maxStatsDepth limits how deep nested schemas are measured,
+// so that recursive definitions are not followed forever
+const maxStatsDepth = 32
+
+// SpecStats describes size of OpenAPI definition, which determines
+// how long loading it takes and how much memory verifiers use
+type SpecStats struct {
+	Paths      int
+	Operations int
+	// Schemas is the number of schema definitions
+	Schemas int
+	// MaxDepth is the greatest nesting depth of schemas of parameters and
+	// responses, in which top-level schema has depth 1
+	MaxDepth int
+	// ExpandedSize is the size of JSON of the definition with references
+	// expanded in bytes
+	ExpandedSize int
+}
+
+func (s SpecStats) String() string {
+	return strconv.Itoa(s.Paths) + " paths, " + strconv.Itoa(s.Operations) + " operations, " +
+		strconv.Itoa(s.Schemas) + " schemas, max depth " + strconv.Itoa(s.MaxDepth) +
+		", expanded size " + strconv.Itoa(s.ExpandedSize) + " bytes"
+}
+
+// SpecStatistics loads OpenAPI definition and returns its size metrics
+func SpecStatistics(definitionPath string) (SpecStats, error) {
+	a, err := newAPIVerifier(definitionPath)
+	if err != nil {
+		return SpecStats{}, errors.Wrap(err, "failed to collect statistics")
+	}
+	return a.stats()
+}
+
+// Stats returns size metrics of the definition the same way SpecStatistics does
+func (v *Verifier) Stats() (SpecStats, error) {
+	return v.verifier().stats()
+}
+
+func (a *apiVerifier) stats() (SpecStats, error) {
+	swagger := a.doc.Spec()
+	raw, err := json.Marshal(swagger)
+	if err != nil {
+		return SpecStats{}, errors.Wrap(err, "failed to collect statistics")
+	}
+	stats := SpecStats{
+		Paths:        len(swagger.Paths.Paths),
+		Schemas:      len(swagger.Definitions),
+		ExpandedSize: len(raw),
+	}
+	measure := func(schema *spec.Schema) {
+		if depth := schemaDepth(schema, 0); depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+		}
+	}
+	for _, path := range sortedPaths(swagger) {
+		pathItem := swagger.Paths.Paths[path]
+		for _, method := range httpMethods {
+			operation := pathOperation(method, &pathItem)
+			if operation == nil {
+				continue
+			}
+			stats.Operations++
+			for _, param := range operationParams(&pathItem, operation) {
+				measure(param.Schema)
+			}
+			for _, r := range operationResponses(operation) {
+				measure(r.response.Schema)
+			}
+		}
+	}
+	return stats, nil
+}
+
+// schemaDepth returns nesting depth of the schema, which is 0 for nil schema
+func schemaDepth(schema *spec.Schema, depth int) int {
+	if schema == nil || depth >= maxStatsDepth {
+		return depth
+	}
+	deepest := depth + 1
+	nested := func(s *spec.Schema, depth int) {
+		if d := schemaDepth(s, depth); d > deepest {
+			deepest = d
+		}
+	}
+	for i := range schema.AllOf {
+		nested(&schema.AllOf[i], depth)
+	}
+	for name := range schema.Properties {
+		prop := schema.Properties[name]
+		nested(&prop, depth+1)
+	}
+	if schema.AdditionalProperties != nil {
+		nested(schema.AdditionalProperties.Schema, depth+1)
+	}
+	if schema.Items != nil {
+		nested(schema.Items.Schema, depth+1)
+		for i := range schema.Items.Schemas {
+			nested(&schema.Items.Schemas[i], depth+1)
+		}
+	}
+	return deepest
+}
@@ -0,0 +1,31 @@
+package revisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecStatistics(t *testing.T) {
+	stats, err := SpecStatistics(testdata + "pii.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Paths)
+	assert.Equal(t, 2, stats.Operations)
+	assert.Equal(t, 1, stats.Schemas)
+	assert.Equal(t, 4, stats.MaxDepth)
+	assert.NotZero(t, stats.ExpandedSize)
+	assert.Regexp(t, `^1 paths, 2 operations, 1 schemas, max depth 4, expanded size \d+ bytes$`, stats.String())
+
+	_, err = SpecStatistics(testdata + "missing.yaml")
+	assert.Regexp(t, "failed to collect statistics", err)
+}
+
+func TestVerifier_Stats(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	stats, err := v.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 20, stats.Operations)
+	assert.True(t, stats.MaxDepth > 1)
+}
@@ -0,0 +1,180 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+)
+
+// scalarTypes are types of properties of flat schemas
+var scalarTypes = map[string]bool{"string": true, "integer": true, "number": true, "boolean": true}
+
+// WithStreamingDecode enables low-allocation validation of JSON responses
+// of the operations, which validates properties of flat objects while they
+// are decoded instead of building and validating the whole body. Invalid
+// responses are validated again as usual to describe violations. Schemas of
+// flat objects have properties of scalar types only, no extensions and no
+// constraints of the object itself except for required properties. Other
+// responses, and all responses if checks of decoded bodies like pagination,
+// envelopes, decoded stages, leak detection, examples or consistency are
+// enabled, are validated as usual.
+func WithStreamingDecode(operationIDs ...string) option {
+	return func(a *apiVerifier) {
+		if a.opts.streamingDecode == nil {
+			a.opts.streamingDecode = make(map[string]bool)
+		}
+		for _, id := range operationIDs {
+			a.opts.streamingDecode[id] = true
+		}
+	}
+}
+
+// flatValidator validates flat objects property by property
+type flatValidator struct {
+	names      []string
+	index      map[string]int
+	validators []*validate.SchemaValidator
+	required   []string
+	defaults   map[string]bool
+}
+
+// flatValidator returns validator of flat schema, which is nil if the
+// schema is not flat. Validators are compiled once for every schema.
+func (a *apiVerifier) flatValidator(schema *spec.Schema) *flatValidator {
+	if cached, ok := a.flatValidators.Load(schema); ok {
+		return cached.(*flatValidator)
+	}
+	v := compileFlatValidator(schema)
+	a.flatValidators.Store(schema, v)
+	return v
+}
+
+func compileFlatValidator(schema *spec.Schema) *flatValidator {
+	if schema == nil || !isFlatObject(schema) {
+		return nil
+	}
+	v := &flatValidator{
+		names:    sortedSchemaProperties(schema.Properties),
+		index:    make(map[string]int, len(schema.Properties)),
+		required: schema.Required,
+		defaults: make(map[string]bool),
+	}
+	for i, name := range v.names {
+		prop := schema.Properties[name]
+		if !isScalar(&prop) {
+			return nil
+		}
+		v.index[name] = i
+		v.validators = append(v.validators, validate.NewSchemaValidator(&prop, nil, name, strfmt.Default))
+		if prop.Default != nil {
+			v.defaults[name] = true
+		}
+	}
+	for _, name := range v.required {
+		if _, ok := v.index[name]; !ok {
+			return nil
+		}
+	}
+	return v
+}
+
+// isFlatObject checks if the schema is an object, which only declares
+// properties, required ones and allows additional properties
+func isFlatObject(s *spec.Schema) bool {
+	if len(s.Type) != 1 || s.Type[0] != "object" || len(s.Properties) == 0 || len(s.Extensions) != 0 {
+		return false
+	}
+	stripped := *s
+	stripped.Type, stripped.Properties, stripped.Required = nil, nil, nil
+	stripped.Title, stripped.Description, stripped.Example, stripped.ExternalDocs = "", "", nil, nil
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Allows && s.AdditionalProperties.Schema == nil {
+		stripped.AdditionalProperties = nil
+	}
+	raw, err := json.Marshal(&stripped)
+	return err == nil && string(raw) == "{}"
+}
+
+// isScalar checks if the schema is of a single scalar type
+// and has no nested schemas or extensions
+func isScalar(s *spec.Schema) bool {
+	return len(s.Type) == 1 && scalarTypes[s.Type[0]] && len(s.Extensions) == 0 &&
+		s.Items == nil && len(s.Properties) == 0 && s.AdditionalProperties == nil &&
+		len(s.AllOf) == 0 && len(s.AnyOf) == 0 && len(s.OneOf) == 0 && s.Not == nil &&
+		len(s.PatternProperties) == 0 && s.Ref.String() == ""
+}
+
+// verifiedStreaming checks if JSON response of operation selected with
+// WithStreamingDecode is valid while decoding it. Responses which are not
+// verified this way, including invalid ones, are verified as usual, so that
+// violations are described the same way.
+func (a *apiVerifier) verifiedStreaming(req *http.Request, schema *spec.Schema, contentType string, body []byte) bool {
+	if !strings.Contains(contentType, "json") || !a.streamable(req) {
+		return false
+	}
+	v := a.flatValidator(schema)
+	return v != nil && v.valid(body)
+}
+
+// streamable checks if response of the request may skip building decoded body
+func (a *apiVerifier) streamable(req *http.Request) bool {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !a.opts.streamingDecode[operation.ID] {
+		return false
+	}
+	if _, ok := operationEnvelope(operation); ok || a.opts.envelope != "" {
+		return false
+	}
+	for _, stage := range a.opts.stages {
+		if stage.Decoded != nil {
+			return false
+		}
+	}
+	return !a.opts.checkPagination && !a.opts.paginationLinks && !a.opts.detectLeaks && !a.opts.checkExamples &&
+		!a.opts.checkConsistency && len(a.opts.consistencyChecks[operation.ID]) == 0
+}
+
+// valid checks if body is JSON object satisfying the schema
+func (v *flatValidator) valid(body []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return false
+	}
+	seen := make([]bool, len(v.names))
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		name, _ := t.(string)
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return false
+		}
+		i, ok := v.index[name]
+		if !ok {
+			continue
+		}
+		seen[i] = true
+		if v.validators[i].Validate(value).HasErrors() {
+			return false
+		}
+	}
+	if t, err := dec.Token(); err != nil || t != json.Delim('}') {
+		return false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return false
+	}
+	for _, name := range v.required {
+		if !seen[v.index[name]] && !v.defaults[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package revisor
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStreamingDecode(t *testing.T) {
+	streaming, err := newInitializedVerifier(testdata+"streaming.yaml", WithStreamingDecode("getProfile", "getTeam"))
+	require.NoError(t, err)
+	regular, err := newInitializedVerifier(testdata + "streaming.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		url   string
+		body  string
+		valid bool
+		err   string
+	}{
+		{"valid", "/v1/profiles/1", `{"id":1,"name":"Ann","age":30,"score":99.5,"active":true,"status":"active"}`, true, ""},
+		{"undeclared property", "/v1/profiles/1", `{"id":1,"name":"Ann","extra":{"a":[1]}}`, true, ""},
+		{"zero values", "/v1/profiles/1", `{"id":0,"name":"A","age":0,"active":false}`, true, ""},
+		{"missing required property", "/v1/profiles/1", `{"name":"Ann"}`, false, ".id in body is required"},
+		{"invalid type", "/v1/profiles/1", `{"id":"1","name":"Ann"}`, false, "id in body must be of type integer"},
+		{"fractional integer", "/v1/profiles/1", `{"id":1.5,"name":"Ann"}`, false, "id in body must be of type integer"},
+		{"nested value", "/v1/profiles/1", `{"id":{},"name":"Ann"}`, false, "id in body must be of type integer"},
+		{"null value", "/v1/profiles/1", `{"id":1,"name":null}`, false, "name in body must be of type string"},
+		{"too short", "/v1/profiles/1", `{"id":1,"name":""}`, false, "name in body"},
+		{"minimum", "/v1/profiles/1", `{"id":1,"name":"Ann","age":-1}`, false, "age in body should be greater than or equal to 0"},
+		{"maximum", "/v1/profiles/1", `{"id":1,"name":"Ann","score":101}`, false, "score in body should be less than or equal to 100"},
+		{"enum", "/v1/profiles/1", `{"id":1,"name":"Ann","status":"gone"}`, false, "status in body should be one of"},
+		{"format", "/v1/profiles/1", `{"id":1,"name":"Ann","email":"ann"}`, false, "email in body must be of type email"},
+		{"not an object", "/v1/profiles/1", `[1]`, false, "must be of type object"},
+		{"malformed", "/v1/profiles/1", `{"id":1,`, false, "failed to decode json"},
+		{"trailing data", "/v1/profiles/1", `{"id":1,"name":"Ann"} {}`, false, "failed to decode json"},
+		{"nested schema", "/v1/teams/1", `{"name":"A","members":[{"name":"Ann"}]}`, false, ".id in body is required"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", test.url, nil)
+			_, response, err := streaming.getOperationDef(req)
+			require.NoError(t, err)
+			valid := streaming.verifiedStreaming(req, response.Responses.StatusCodeResponses[200].Schema,
+				"application/json", []byte(test.body))
+			assert.Equal(t, test.valid, valid)
+
+			verify := func(a *apiVerifier) error {
+				rec := httptest.NewRecorder()
+				rec.Header().Set("Content-Type", "application/json")
+				rec.WriteString(test.body)
+				return a.verifyResponse(rec.Result(), req)
+			}
+			err = verify(streaming)
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, fmt.Sprint(verify(regular)), fmt.Sprint(err), "streaming and regular validation differ")
+		})
+	}
+
+	t.Run("checks of decoded bodies", func(t *testing.T) {
+		a, err := newInitializedVerifier(testdata+"streaming.yaml", WithStreamingDecode("getProfile"), CheckExamples())
+		require.NoError(t, err)
+		assert.False(t, a.streamable(httptest.NewRequest("GET", "/v1/profiles/1", nil)))
+		assert.False(t, streaming.streamable(httptest.NewRequest("GET", "/v1/not-found", nil)))
+		assert.False(t, regular.streamable(httptest.NewRequest("GET", "/v1/profiles/1", nil)))
+	})
+}
+
+// largeFlatBody returns a valid profile padded with undeclared properties
+func largeFlatBody() []byte {
+	buf := bytes.NewBufferString(`{"id":1,"name":"Ann","age":30,"score":99.5,"active":true,"status":"active"`)
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(buf, `,"field%d":"value %d"`, i, i)
+	}
+	buf.WriteString("}")
+	return buf.Bytes()
+}
+
+func benchmarkVerifyResponse(b *testing.B, options ...option) {
+	a, err := newInitializedVerifier(testdata+"streaming.yaml", options...)
+	require.NoError(b, err)
+	body := largeFlatBody()
+	req := httptest.NewRequest("GET", "/v1/profiles/1", nil)
+	header := http.Header{"Content-Type": []string{"application/json"}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res := newCapturedResponse(req, ResponseMeta{StatusCode: http.StatusOK, Header: header}, body)
+		if err := a.verifyResponse(res, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyResponse_FlatObject(b *testing.B) {
+	benchmarkVerifyResponse(b)
+}
+
+func BenchmarkVerifyResponse_FlatObjectStreaming(b *testing.B) {
+	benchmarkVerifyResponse(b, WithStreamingDecode("getProfile"))
+}
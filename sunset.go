@@ -0,0 +1,94 @@
+package revisor
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+// sunsetExt is an operation extension which declares that the operation is
+// going to be removed, optionally at the date given as HTTP date or RFC 3339
+// date-time, e.g.
+//
+//	x-sunset: 2027-06-30T00:00:00Z
+const sunsetExt = "x-sunset"
+
+// CheckSunsetHeaders enables checks of lifecycle headers of responses of
+// operations declared with x-sunset extension: responses must have Sunset
+// header with valid HTTP date, which must be the declared date if extension
+// has one, and responses of operations marked as deprecated must also have
+// Deprecation header, which is either a structured date, e.g. @1688169599,
+// HTTP date or "true". Missing or invalid headers are reported as Violation
+// with CodeSunsetHeader code.
+func CheckSunsetHeaders(a *apiVerifier) {
+	a.opts.checkSunset = true
+}
+
+// operationSunset returns sunset date declared with x-sunset extension,
+// which is zero if extension doesn't declare a date
+func operationSunset(operation *spec.Operation) (time.Time, bool) {
+	ext, ok := operation.Extensions[sunsetExt]
+	if !ok {
+		return time.Time{}, false
+	}
+	date, _ := ext.(string)
+	if sunset, err := time.Parse(time.RFC3339, date); err == nil {
+		return sunset, true
+	}
+	if sunset, err := http.ParseTime(date); err == nil {
+		return sunset, true
+	}
+	return time.Time{}, true
+}
+
+// verifySunsetHeaders checks lifecycle headers of the response
+func (a *apiVerifier) verifySunsetHeaders(req *http.Request, res *http.Response) error {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return nil
+	}
+	declared, ok := operationSunset(operation)
+	if !ok {
+		return nil
+	}
+	header := res.Header.Get("Sunset")
+	if header == "" {
+		return newViolation(CodeSunsetHeader, "Sunset header is missing")
+	}
+	sunset, err := http.ParseTime(header)
+	if err != nil {
+		return newViolation(CodeSunsetHeader, "Sunset is not an HTTP date: "+header)
+	}
+	if !declared.IsZero() && !sunset.Equal(declared) {
+		return newViolation(CodeSunsetHeader, "Sunset doesn't match the declared date: expected "+
+			declared.UTC().Format(http.TimeFormat)+", got "+header)
+	}
+	if !operation.Deprecated {
+		return nil
+	}
+	deprecation := res.Header.Get("Deprecation")
+	if deprecation == "" {
+		return newViolation(CodeSunsetHeader, "Deprecation header is missing")
+	}
+	if !validDeprecationDate(deprecation) {
+		return newViolation(CodeSunsetHeader, "Deprecation is not a date: "+deprecation)
+	}
+	return nil
+}
+
+// validDeprecationDate checks if Deprecation header is a structured date,
+// HTTP date or "true" used by earlier drafts of the standard
+func validDeprecationDate(deprecation string) bool {
+	if deprecation == "true" {
+		return true
+	}
+	if strings.HasPrefix(deprecation, "@") {
+		_, err := strconv.ParseInt(deprecation[1:], 10, 64)
+		return err == nil
+	}
+	_, err := http.ParseTime(deprecation)
+	return err == nil
+}
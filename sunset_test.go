@@ -0,0 +1,102 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSunsetHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		sunset      string
+		deprecation string
+		wantCode    string
+	}{
+		{
+			name:   "structured deprecation date",
+			path:   "/v1/orders",
+			sunset: "Wed, 30 Jun 2027 00:00:00 GMT", deprecation: "@1688169599",
+		},
+		{
+			name:   "http deprecation date",
+			path:   "/v1/orders",
+			sunset: "Wed, 30 Jun 2027 00:00:00 GMT", deprecation: "Fri, 30 Jun 2023 23:59:59 GMT",
+		},
+		{
+			name:        "missing sunset",
+			path:        "/v1/orders",
+			deprecation: "true",
+			wantCode:    CodeSunsetHeader,
+		},
+		{
+			name:   "invalid sunset",
+			path:   "/v1/orders",
+			sunset: "2027-06-30", deprecation: "true",
+			wantCode: CodeSunsetHeader,
+		},
+		{
+			name:   "sunset doesn't match",
+			path:   "/v1/orders",
+			sunset: "Thu, 01 Jul 2027 00:00:00 GMT", deprecation: "true",
+			wantCode: CodeSunsetHeader,
+		},
+		{
+			name:     "missing deprecation",
+			path:     "/v1/orders",
+			sunset:   "Wed, 30 Jun 2027 00:00:00 GMT",
+			wantCode: CodeSunsetHeader,
+		},
+		{
+			name:   "invalid deprecation",
+			path:   "/v1/orders",
+			sunset: "Wed, 30 Jun 2027 00:00:00 GMT", deprecation: "yes",
+			wantCode: CodeSunsetHeader,
+		},
+		{
+			name:   "any sunset date",
+			path:   "/v1/invoices",
+			sunset: "Thu, 01 Jul 2027 00:00:00 GMT",
+		},
+		{
+			name:     "missing sunset without date",
+			path:     "/v1/invoices",
+			wantCode: CodeSunsetHeader,
+		},
+		{
+			name: "no sunset",
+			path: "/v1/payments",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"sunset.yaml", CheckSunsetHeaders)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			if tt.sunset != "" {
+				rec.Header().Set("Sunset", tt.sunset)
+			}
+			if tt.deprecation != "" {
+				rec.Header().Set("Deprecation", tt.deprecation)
+			}
+			rec.Body = bytes.NewBufferString(`[]`)
+			err = v.VerifyResponse(rec.Result(), httptest.NewRequest("GET", tt.path, nil))
+			assertVerifyErr(t, err, "", tt.wantCode)
+		})
+	}
+}
+
+func TestCheckSunsetHeaders_Warning(t *testing.T) {
+	var warnings []string
+	_, err := New(testdata+"sunset.yaml", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w.String())
+	}))
+	require.NoError(t, err)
+	assert.Contains(t, warnings, "GET /orders: x-sunset is not enforced")
+}
@@ -0,0 +1,82 @@
+package revisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/pkg/errors"
+)
+
+// trailersExt is a response extension which declares trailers sent after the
+// body of streaming responses, e.g. gRPC-Web responses, with simple constraints
+// which are the same as for headers, e.g.
+//
+//	x-trailers:
+//	  grpc-status:
+//	    type: integer
+//	    required: true
+//	  grpc-message:
+//	    type: string
+//
+// Trailers are validated once the body is read, as they are only available
+// after that.
+const trailersExt = "x-trailers"
+
+type trailer struct {
+	Required bool `json:"required"`
+	spec.SimpleSchema
+	spec.CommonValidations
+}
+
+func responseTrailers(response *spec.Response) (map[string]trailer, error) {
+	ext, ok := response.Extensions[trailersExt]
+	if !ok {
+		return nil, nil
+	}
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read "+trailersExt)
+	}
+	var trailers map[string]trailer
+	err = json.Unmarshal(raw, &trailers)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse "+trailersExt)
+	}
+	return trailers, nil
+}
+
+// verifyTrailers validates trailers of the response, which body is already read
+func verifyTrailers(response *spec.Response, res *http.Response) error {
+	trailers, err := responseTrailers(response)
+	if err != nil || len(trailers) == 0 {
+		return err
+	}
+	names := make([]string, 0, len(trailers))
+	for name := range trailers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t := trailers[name]
+		values, ok := res.Trailer[http.CanonicalHeaderKey(name)]
+		if !ok || len(values) == 0 {
+			if t.Required {
+				return errors.New("trailer " + name + " is required")
+			}
+			continue
+		}
+		converted, err := convertSimple(t.Type, values[0])
+		if err != nil {
+			return errors.Wrap(err, "trailer "+name+" is not valid")
+		}
+		err = validate.AgainstSchema(parameterSchema(&t.SimpleSchema, &t.CommonValidations), converted, strfmt.Default)
+		if err != nil {
+			return errors.Wrap(err, "trailer "+name+" is not valid")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package revisor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		trailers map[string]string
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			trailers: map[string]string{"Grpc-Status": "0", "Grpc-Message": "OK"},
+		},
+		{
+			name:     "optional trailer is missing",
+			trailers: map[string]string{"Grpc-Status": "0"},
+		},
+		{
+			name:    "required trailer is missing",
+			wantErr: "trailer grpc-status is required",
+		},
+		{
+			name:     "invalid type",
+			trailers: map[string]string{"Grpc-Status": "OK"},
+			wantErr:  `trailer grpc-status is not valid: "OK" is not an integer`,
+		},
+		{
+			name:     "invalid value",
+			trailers: map[string]string{"Grpc-Status": "17"},
+			wantErr:  "trailer grpc-status is not valid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata + "trailers.yaml")
+			require.NoError(t, err)
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name := range tt.trailers {
+					w.Header().Add("Trailer", name)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[]`))
+				for name, value := range tt.trailers {
+					w.Header().Set(name, value)
+				}
+			})
+
+			t.Run("client", func(t *testing.T) {
+				server := httptest.NewServer(handler)
+				defer server.Close()
+				res, err := http.Get(server.URL + "/v1/greetings")
+				require.NoError(t, err)
+				defer res.Body.Close()
+				assertVerifyErr(t, v.VerifyResponse(res, res.Request), tt.wantErr, "")
+			})
+
+			t.Run("recorder", func(t *testing.T) {
+				req := httptest.NewRequest("GET", "/v1/greetings", nil)
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				assertVerifyErr(t, v.VerifyRecorder(rec, req), tt.wantErr, "")
+			})
+		})
+	}
+}
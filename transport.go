@@ -0,0 +1,12 @@
+package revisor
+
+// TransportError is reported when request or response body can't be read,
+// e.g. due to a broken connection or a timeout. It is caused by infrastructure
+// rather than by broken contract and can be told apart with errors.Cause.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return e.Err.Error()
+}
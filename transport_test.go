@@ -0,0 +1,38 @@
+package revisor
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportError(t *testing.T) {
+	sink := &recordingSink{}
+	v, err := New(testdata+sampleV2YAML, WithMetrics(sink))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/v2/pet", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = ioutil.NopCloser(&brokenReader{})
+	err = v.VerifyRequest(req)
+	transportErr, ok := errors.Cause(err).(*TransportError)
+	require.True(t, ok, "%v", err)
+	assert.Equal(t, assert.AnError, errors.Cause(transportErr.Err))
+
+	res := httptest.NewRecorder().Result()
+	res.Body = ioutil.NopCloser(&brokenReader{})
+	err = v.VerifyResponse(res, httptest.NewRequest("GET", "/v2/pet/1", nil))
+	_, ok = errors.Cause(err).(*TransportError)
+	assert.True(t, ok, "%v", err)
+
+	assert.Equal(t, []string{
+		"verifications kind=request operation=addPet",
+		"transport_errors kind=request operation=addPet",
+		"verifications kind=response operation=getPetById",
+		"transport_errors kind=response operation=getPetById",
+	}, sink.counters, "transport errors are not counted as violations")
+}
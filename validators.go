@@ -0,0 +1,129 @@
+package revisor
+
+import (
+	"strconv"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// validateExt is a schema extension which names custom validators, registered
+// with WithFieldValidator, which validate the value of the schema after
+// schema validation succeeds, e.g.
+//
+//	cardNumber:
+//	  type: string
+//	  x-validate: luhn
+//
+// Several validators may be listed, e.g. x-validate: [luhn, issuer].
+const validateExt = "x-validate"
+
+// WithFieldValidator registers custom validator with the name, which is run
+// against values of body fields, which schemas list the name in x-validate
+// extension. It enforces business rules too specific for JSON Schema, e.g.
+// checksums of card numbers. Values are decoded JSON values, errors are
+// reported with JSON pointer of the field.
+func WithFieldValidator(name string, validate func(value interface{}) error) option {
+	return func(a *apiVerifier) {
+		if a.opts.fieldValidators == nil {
+			a.opts.fieldValidators = make(map[string]func(interface{}) error)
+		}
+		a.opts.fieldValidators[name] = validate
+	}
+}
+
+// validateFields runs custom validators named in x-validate extensions
+// of the schema and its subschemas against decoded body
+func (a *apiVerifier) validateFields(schema *spec.Schema, decoded interface{}) error {
+	if len(a.opts.fieldValidators) == 0 {
+		return nil
+	}
+	return walkBody(schema, decoded, "", a.validateField)
+}
+
+func (a *apiVerifier) validateField(schema *spec.Schema, value interface{}, pointer string) error {
+	for _, name := range schemaValidators(schema) {
+		validate, ok := a.opts.fieldValidators[name]
+		if !ok {
+			return errors.New("validator " + name + " is not registered")
+		}
+		if err := validate(value); err != nil {
+			return errors.Wrap(err, bodyField(pointer)+" in body is not valid "+name)
+		}
+	}
+	return nil
+}
+
+// walkBody calls visit for the decoded value and each of its nested values
+// described by the schema, its allOf subschemas, properties, additional
+// properties and items, with JSON pointers of the values
+func walkBody(schema *spec.Schema, value interface{}, pointer string, visit func(*spec.Schema, interface{}, string) error) error {
+	if schema == nil {
+		return nil
+	}
+	if err := visit(schema, value, pointer); err != nil {
+		return err
+	}
+	for i := range schema.AllOf {
+		if err := walkBody(&schema.AllOf[i], value, pointer, visit); err != nil {
+			return err
+		}
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			property := pointer + "/" + escapePointerToken(key)
+			if prop, ok := schema.Properties[key]; ok {
+				if err := walkBody(&prop, v[key], property, visit); err != nil {
+					return err
+				}
+			} else if schema.AdditionalProperties != nil {
+				if err := walkBody(schema.AdditionalProperties.Schema, v[key], property, visit); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		if schema.Items == nil {
+			return nil
+		}
+		for i, item := range v {
+			itemSchema := schema.Items.Schema
+			if len(schema.Items.Schemas) > 0 {
+				if i >= len(schema.Items.Schemas) {
+					break
+				}
+				itemSchema = &schema.Items.Schemas[i]
+			}
+			if err := walkBody(itemSchema, item, pointer+"/"+strconv.Itoa(i), visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bodyField returns the pointer of body field, which is / for the body itself
+func bodyField(pointer string) string {
+	if pointer == "" {
+		return "/"
+	}
+	return pointer
+}
+
+// schemaValidators returns names of validators listed in x-validate extension
+func schemaValidators(schema *spec.Schema) []string {
+	switch names := schema.Extensions[validateExt].(type) {
+	case string:
+		return []string{names}
+	case []interface{}:
+		var validators []string
+		for _, name := range names {
+			if name, ok := name.(string); ok {
+				validators = append(validators, name)
+			}
+		}
+		return validators
+	}
+	return nil
+}
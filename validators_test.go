@@ -0,0 +1,96 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func luhn(value interface{}) error {
+	number, _ := value.(string)
+	sum := 0
+	for i := range number {
+		digit := int(number[len(number)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return errors.New("checksum mismatch")
+	}
+	return nil
+}
+
+func lowercase(value interface{}) error {
+	if s, _ := value.(string); s != strings.ToLower(s) {
+		return errors.New("must be lowercase")
+	}
+	return nil
+}
+
+func TestWithFieldValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []option
+		body    string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			options: []option{WithFieldValidator("luhn", luhn), WithFieldValidator("lowercase", lowercase)},
+			body:    `{"card": {"number": "4111111111111111"}, "tags": ["a"], "metadata": {"k": "v"}}`,
+		},
+		{
+			name:    "invalid property",
+			options: []option{WithFieldValidator("luhn", luhn), WithFieldValidator("lowercase", lowercase)},
+			body:    `{"card": {"number": "4111111111111112"}}`,
+			wantErr: "/card/number in body is not valid luhn: checksum mismatch",
+		},
+		{
+			name:    "invalid item",
+			options: []option{WithFieldValidator("luhn", luhn), WithFieldValidator("lowercase", lowercase)},
+			body:    `{"tags": ["a", "B"]}`,
+			wantErr: "/tags/1 in body is not valid lowercase: must be lowercase",
+		},
+		{
+			name:    "invalid additional property",
+			options: []option{WithFieldValidator("luhn", luhn), WithFieldValidator("lowercase", lowercase)},
+			body:    `{"metadata": {"a/b": "V"}}`,
+			wantErr: "/metadata/a~1b in body is not valid lowercase: must be lowercase",
+		},
+		{
+			name:    "not registered",
+			options: []option{WithFieldValidator("luhn", luhn)},
+			body:    `{"tags": ["a"]}`,
+			wantErr: "validator lowercase is not registered",
+		},
+		{
+			name: "no validators",
+			body: `{"card": {"number": "4111111111111112"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata+"validators.yaml", tt.options...)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "/v1/payments", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			assertVerifyErr(t, v.VerifyRequest(req), tt.wantErr, "")
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			rec.WriteHeader(201)
+			rec.Body = bytes.NewBufferString(tt.body)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), tt.wantErr, "")
+		})
+	}
+}
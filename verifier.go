@@ -0,0 +1,114 @@
+package revisor
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+// RequestVerifier verifies requests, it is satisfied by Verifier and can be
+// replaced with fakes from revisortest package in tests of applications
+type RequestVerifier interface {
+	VerifyRequest(req *http.Request) error
+}
+
+// ResponseVerifier verifies responses made in the context of requests
+type ResponseVerifier interface {
+	VerifyResponse(res *http.Response, req *http.Request) error
+}
+
+// PairVerifier verifies both requests and responses made in their context
+type PairVerifier interface {
+	Verify(res *http.Response, req *http.Request) error
+}
+
+var (
+	_ RequestVerifier  = (*Verifier)(nil)
+	_ ResponseVerifier = (*Verifier)(nil)
+	_ PairVerifier     = (*Verifier)(nil)
+)
+
+// Verifier verifies requests and responses against OpenAPI definition,
+// which can be replaced at runtime, e.g. by a control plane pushing new
+// contracts into running gateways
+type Verifier struct {
+	definitionPath string
+	options        []option
+	current        atomic.Value
+}
+
+// New returns Verifier of OpenAPI definition loaded from definitionPath
+func New(definitionPath string, options ...option) (*Verifier, error) {
+	a, err := newInitializedVerifier(definitionPath, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create verifier")
+	}
+	v := &Verifier{definitionPath: definitionPath, options: options}
+	v.current.Store(a)
+	return v, nil
+}
+
+// VerifyRequest verifies if request satisfies OpenAPI definition constraints,
+// nil request is reported as ErrNilInput
+func (v *Verifier) VerifyRequest(req *http.Request) error {
+	return v.verifier().verifyRequest(req)
+}
+
+// VerifyResponse verifies if the response made in the context of the request
+// satisfies OpenAPI definition constraints, the request itself is not verified.
+// Nil request or response is reported as ErrNilInput.
+func (v *Verifier) VerifyResponse(res *http.Response, req *http.Request) error {
+	if req == nil {
+		return ErrNilInput
+	}
+	return v.verifier().verifyMeasuredResponse(res, req)
+}
+
+// Verify verifies both - a request and the response made in the context of the request.
+// Request is verified even if response is nil, which is reported as ErrNilInput
+// unless the request fails verification.
+func (v *Verifier) Verify(res *http.Response, req *http.Request) error {
+	return v.verifier().verifyRequestAndReponse(res, req)
+}
+
+// ReloadFromBytes replaces OpenAPI definition with the one decoded from b,
+// which must be in the same format (YAML or JSON) as the original definition.
+// Relative references are resolved against the original definition path.
+// Definition is replaced atomically, so that every verification uses either
+// previous or new definition. Previous definition is kept if b can't be loaded.
+func (v *Verifier) ReloadFromBytes(b []byte) error {
+	a := withDefaults(&apiVerifier{definitionPath: v.definitionPath})
+	err := a.initDocument(b)
+	if err != nil {
+		return errors.Wrap(err, "failed to reload definition")
+	}
+	return v.reload(a)
+}
+
+// ReloadFromDocument replaces OpenAPI definition with doc the same way
+// ReloadFromBytes does
+func (v *Verifier) ReloadFromDocument(doc *loads.Document) error {
+	a := withDefaults(&apiVerifier{definitionPath: v.definitionPath})
+	expanded, err := doc.Expanded(&spec.ExpandOptions{RelativeBase: v.definitionPath})
+	if err != nil {
+		return errors.Wrap(err, "failed to reload definition: failed to expand document")
+	}
+	a.doc = expanded
+	return v.reload(a)
+}
+
+func (v *Verifier) reload(a *apiVerifier) error {
+	err := a.init(v.options...)
+	if err != nil {
+		return errors.Wrap(err, "failed to reload definition")
+	}
+	v.current.Store(a)
+	return nil
+}
+
+func (v *Verifier) verifier() *apiVerifier {
+	return v.current.Load().(*apiVerifier)
+}
@@ -0,0 +1,139 @@
+package revisor
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/loads"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_Reload(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	_, err = rec.WriteString(`{"id":1}`)
+	require.NoError(t, err)
+	assert.NoError(t, v.Verify(rec.Result(), httptest.NewRequest("GET", "/v2/user/testuser", nil)))
+
+	raw, err := ioutil.ReadFile(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	t.Run("reload from bytes", func(t *testing.T) {
+		err := v.ReloadFromBytes([]byte(strings.Replace(string(raw), "basePath: /v2", "basePath: /v3", 1)))
+		require.NoError(t, err)
+		assert.Regexp(t, "no path template matches current request",
+			v.VerifyRequest(httptest.NewRequest("GET", "/v2/user/testuser", nil)))
+		assert.NoError(t, v.VerifyRequest(httptest.NewRequest("GET", "/v3/user/testuser", nil)))
+	})
+
+	t.Run("previous definition is kept if new one is invalid", func(t *testing.T) {
+		err := v.ReloadFromBytes([]byte(":{invalid yaml}"))
+		assert.Regexp(t, "failed to reload definition", err)
+		assert.NoError(t, v.VerifyRequest(httptest.NewRequest("GET", "/v3/user/testuser", nil)))
+	})
+
+	t.Run("reload from document", func(t *testing.T) {
+		doc, err := loads.Spec(testdata + sampleV2JSON)
+		require.NoError(t, err)
+		require.NoError(t, v.ReloadFromDocument(doc))
+		assert.NoError(t, v.VerifyRequest(httptest.NewRequest("GET", "/v2/user/testuser", nil)))
+	})
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("./non-existing-file.yaml")
+	assert.Regexp(t, "failed to create verifier", err)
+}
+
+func TestVerifier_VerifyResponse(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "valid", body: `{"name": "doggie", "photoUrls": []}`},
+		{name: "invalid", body: `{"name": 1, "photoUrls": []}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// request body is not verified, though the operation doesn't declare one
+			req := httptest.NewRequest("GET", "/v2/pet/1", strings.NewReader("unexpected"))
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "application/json")
+			_, _ = rec.WriteString(tt.body)
+			err := v.VerifyResponse(rec.Result(), req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifier_NilInput(t *testing.T) {
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	res := httptest.NewRecorder().Result()
+
+	assert.Equal(t, ErrNilInput, v.VerifyRequest(nil))
+	assert.Equal(t, ErrNilInput, v.VerifyResponse(nil, httptest.NewRequest("GET", "/v2/pet/1", nil)))
+	assert.Equal(t, ErrNilInput, v.VerifyResponse(res, nil))
+	assert.Equal(t, ErrNilInput, v.Verify(res, nil))
+
+	err = v.Verify(nil, httptest.NewRequest("GET", "/v2/pet/1", nil))
+	assert.Equal(t, ErrNilInput, errors.Cause(err))
+	assert.EqualError(t, err, "response validation failed: request or response is nil")
+
+	err = v.Verify(nil, httptest.NewRequest("GET", "/v2/unknown", nil))
+	assert.Equal(t, CodeUndocumentedPath, errors.Cause(err).(*Violation).Code, "request error is reported")
+
+	t.Run("wrappers", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v2/pet/1", nil)
+		assert.Equal(t, ErrNilInput, NewHARRecorder(v.Verify, 1, 0).Verify(nil, req))
+		assert.Equal(t, ErrNilInput, NewHARRecorder(v.Verify, 1, 0).Verify(res, nil))
+		assert.Equal(t, ErrNilInput, NewPactRecorder("c", "p", v.Verify).Verify(nil, req))
+		assert.Equal(t, ErrNilInput, NewPactRecorder("c", "p", v.Verify).Verify(res, nil))
+		assert.Equal(t, ErrNilInput, NewWebhookNotifier("http://127.0.0.1", v.Verify, false).Verify(res, nil))
+		assert.Equal(t, ErrNilInput, v.VerifyRecorder(nil, req))
+		assert.Equal(t, ErrNilInput, v.VerifyRecorder(httptest.NewRecorder(), nil))
+		assert.Empty(t, v.SatisfiedTemplates(nil))
+		assert.Equal(t, ErrNilInput, ValidateAPIGatewayRequest(v.VerifyRequest, nil))
+		assert.Equal(t, ErrNilInput, ValidateAPIGatewayExchange(v.Verify, nil, &APIGatewayResponse{}))
+		assert.Equal(t, ErrNilInput, ValidateAPIGatewayExchange(v.Verify, &APIGatewayRequest{}, nil))
+
+		failed, err := v.VerifyBatch(context.Background(), []Exchange{{Response: res}}, 1)
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+		assert.Equal(t, ErrNilInput, failed[0].Err)
+
+		bind, err := NewBinder(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		assert.Equal(t, ErrNilInput, bind(nil, &struct{}{}))
+		verifyWithContext, err := NewContextVerifier(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		_, err = verifyWithContext(nil)
+		assert.Equal(t, ErrNilInput, err)
+		sanitize, err := NewRequestSanitizer(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		_, err = sanitize(nil)
+		assert.Equal(t, ErrNilInput, err)
+		fix, err := NewResponseFixer(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		_, err = fix(nil, req)
+		assert.Equal(t, ErrNilInput, err)
+		_, err = fix(res, nil)
+		assert.Equal(t, ErrNilInput, err)
+	})
+}
@@ -0,0 +1,108 @@
+package revisor
+
+// Violation codes distinguishing broken contract rules which are worth
+// handling separately from generic validation errors
+const (
+	// CodeAmbiguousFraming is reported for requests with both
+	// Content-Length and Transfer-Encoding set
+	CodeAmbiguousFraming = "ambiguous_framing"
+	// CodeUnexpectedBody is reported for requests sending a body to
+	// an operation which doesn't declare one
+	CodeUnexpectedBody = "unexpected_body"
+	// CodeUndocumentedPath is reported for requests which path doesn't
+	// match any template, gateways may respond with 404 Not Found
+	CodeUndocumentedPath = "undocumented_path"
+	// CodeUndocumentedMethod is reported for requests which path matches
+	// some template, but method is not documented for it, gateways
+	// may respond with 405 Method Not Allowed
+	CodeUndocumentedMethod = "undocumented_method"
+	// CodeRateLimitExceeded is reported for requests over the limit
+	// configured with x-rate-limit operation extension
+	CodeRateLimitExceeded = "rate_limit_exceeded"
+	// CodeBudgetExceeded is reported for requests and responses which
+	// validation was abandoned after exceeding configured budget
+	CodeBudgetExceeded = "budget_exceeded"
+	// CodeMissingEnvelope is reported for bodies which have no payload
+	// at JSON pointer of the envelope
+	CodeMissingEnvelope = "missing_envelope"
+	// CodeInvalidJSONAPI is reported for JSON:API documents which top
+	// level structure is invalid
+	CodeInvalidJSONAPI = "invalid_jsonapi"
+	// CodeInvalidLinks is reported for HAL documents with invalid
+	// hypermedia links
+	CodeInvalidLinks = "invalid_links"
+	// CodeConditionalRequest is reported for requests and responses of
+	// operations declared with x-supports-etag extension, which break
+	// conditional request semantics
+	CodeConditionalRequest = "conditional_request"
+	// CodePagination is reported for requests and responses of operations
+	// declared with x-pagination extension, which break pagination conventions
+	CodePagination = "pagination"
+	// CodeMissingIdempotencyKey is reported for requests to operations
+	// declared with x-requires-idempotency-key extension without
+	// Idempotency-Key header
+	CodeMissingIdempotencyKey = "missing_idempotency_key"
+	// CodeInvalidIdempotencyKey is reported for requests which
+	// Idempotency-Key header is not valid
+	CodeInvalidIdempotencyKey = "invalid_idempotency_key"
+	// CodeCorrelationHeader is reported for responses which don't echo
+	// correlation header of the request
+	CodeCorrelationHeader = "correlation_header"
+	// CodeDeprecatedOperation is reported for requests to deprecated
+	// operations if they are rejected
+	CodeDeprecatedOperation = "deprecated_operation"
+	// CodeSunsetHeader is reported for responses of operations declared
+	// with x-sunset extension without valid lifecycle headers
+	CodeSunsetHeader = "sunset_header"
+	// CodeSLOExceeded is reported for requests handled longer than latency
+	// budget annotated with x-slo-latency-ms extension
+	CodeSLOExceeded = "slo_exceeded"
+	// CodeInvalidRange is reported for requests and partial responses of
+	// operations declared with x-accept-ranges extension with invalid ranges
+	CodeInvalidRange = "invalid_range"
+	// CodeInvalidHandshake is reported for requests and responses of
+	// WebSocket endpoints which are not valid opening handshakes
+	CodeInvalidHandshake = "invalid_handshake"
+	// CodeUnsatisfiedRule is reported for bodies which don't satisfy
+	// cross-field rules declared with x-requires extension
+	CodeUnsatisfiedRule = "unsatisfied_rule"
+	// CodeInconsistentResponse is reported for responses which don't match
+	// requests they are made to
+	CodeInconsistentResponse = "inconsistent_response"
+	// CodeInvalidLocation is reported for 201 and 3xx responses without
+	// Location header or which Location doesn't match documented paths
+	CodeInvalidLocation = "invalid_location"
+	// CodeInvalidLinkHeader is reported for responses with invalid Link
+	// headers or without links to adjacent pages of paginated operations
+	CodeInvalidLinkHeader = "invalid_link_header"
+	// CodeInvalidContentDisposition is reported for responses without
+	// declared Content-Disposition header or with invalid one
+	CodeInvalidContentDisposition = "invalid_content_disposition"
+	// CodeCachePolicy is reported for responses of operations declared
+	// with x-cache-policy extension which caching headers break the policy
+	CodeCachePolicy = "cache_policy"
+	// CodeInsecureResponse is reported for responses failing security
+	// hygiene checks if RejectInsecureResponses is set
+	CodeInsecureResponse = "insecure_response"
+	// CodeSensitiveData is reported for responses which bodies leak
+	// sensitive data
+	CodeSensitiveData = "sensitive_data"
+	// CodeStaleExample is reported for responses which structure diverges
+	// from documented examples
+	CodeStaleExample = "stale_example"
+)
+
+// Violation is an error which classifies broken contract rule with a code.
+// Violations can be told apart from other errors with errors.Cause
+type Violation struct {
+	Code    string
+	Message string
+}
+
+func newViolation(code, message string) *Violation {
+	return &Violation{Code: code, Message: message}
+}
+
+func (v *Violation) Error() string {
+	return v.Code + ": " + v.Message
+}
@@ -0,0 +1,164 @@
+package revisor
+
+import (
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// Features of OpenAPI definition reported in warnings
+const (
+	FeatureQueryParameter    = "query parameter"
+	FeatureHeaderParameter   = "header parameter"
+	FeatureFormDataParameter = "formData parameter"
+	FeatureSecurity          = "security"
+	FeatureResponseHeader    = "response header"
+	FeatureRateLimit         = rateLimitExt
+	FeatureETag              = supportsETagExt
+	FeaturePagination        = paginationExt
+	FeatureIdempotencyKey    = requiresIdempotencyKeyExt
+	FeatureSunset            = sunsetExt
+	FeatureEchoes            = echoesExt
+	FeatureFieldsParam       = fieldsParamExt
+	FeatureLocation          = locationExt
+	FeatureAttachment        = attachmentExt
+	FeatureCachePolicy       = cachePolicyExt
+)
+
+// Warning describes a feature declared for an operation in OpenAPI definition,
+// which is not enforced by the verifier with configured options
+type Warning struct {
+	Method  string
+	Path    string
+	Feature string
+	Name    string
+}
+
+func (w Warning) String() string {
+	s := w.Method + " " + w.Path + ": " + w.Feature
+	if w.Name != "" {
+		s += " " + w.Name
+	}
+	return s + " is not enforced"
+}
+
+// WithWarningHandler sets a function which is called for every warning
+// reported during verifier initialization. By default warnings are logged
+// if the logger is set with WithLogger, and are not reported otherwise.
+func WithWarningHandler(handler func(Warning)) option {
+	return func(a *apiVerifier) {
+		a.opts.warn = handler
+	}
+}
+
+// warnUnenforced reports features of operations which are silently ignored
+// by the verifier with configured options
+func (a *apiVerifier) warnUnenforced() {
+	warn := a.opts.warn
+	if warn == nil {
+		if !a.opts.loggerSet {
+			return
+		}
+		warn = func(w Warning) { a.opts.logf("revisor: warning: %s", w) }
+	}
+	for _, w := range a.unenforced() {
+		warn(w)
+	}
+}
+
+func (a *apiVerifier) unenforced() []Warning {
+	var warnings []Warning
+	swagger := a.doc.Spec()
+	for _, path := range sortedPaths(swagger) {
+		pathDef := swagger.Paths.Paths[path]
+		for _, method := range httpMethods {
+			operation := pathOperation(method, &pathDef)
+			if operation == nil {
+				continue
+			}
+			add := func(feature, name string) {
+				warnings = append(warnings, Warning{Method: method, Path: path, Feature: feature, Name: name})
+			}
+			partSchemas := operation.Extensions[multipartPartSchemaExt] != nil
+			for _, param := range operationParams(&pathDef, operation) {
+				switch param.In {
+				case "query":
+					add(FeatureQueryParameter, param.Name)
+				case "header":
+					add(FeatureHeaderParameter, param.Name)
+				case "formData":
+					if !partSchemas {
+						add(FeatureFormDataParameter, param.Name)
+					}
+				}
+			}
+			if operation.Security != nil && len(operation.Security) != 0 ||
+				operation.Security == nil && len(swagger.Security) != 0 {
+				add(FeatureSecurity, "")
+			}
+			if _, ok := operation.Extensions[rateLimitExt]; ok && a.opts.rateLimitStore == nil {
+				add(FeatureRateLimit, "")
+			}
+			if supportsETag(operation) && !a.opts.checkConditional {
+				add(FeatureETag, "")
+			}
+			if _, ok := operation.Extensions[paginationExt]; ok && !a.opts.checkPagination {
+				add(FeaturePagination, "")
+			}
+			if requiresIdempotencyKey(operation) && !a.opts.checkIdempotency {
+				add(FeatureIdempotencyKey, "")
+			}
+			if _, ok := operation.Extensions[sunsetExt]; ok && !a.opts.checkSunset {
+				add(FeatureSunset, "")
+			}
+			if _, ok := operation.Extensions[cachePolicyExt]; ok && !a.opts.checkCachePolicy {
+				add(FeatureCachePolicy, "")
+			}
+			if _, ok := operation.Extensions[fieldsParamExt]; ok && !a.opts.checkConsistency {
+				add(FeatureFieldsParam, "")
+			}
+			if operation.Responses == nil {
+				continue
+			}
+			responses := make([]spec.Response, 0, len(operation.Responses.StatusCodeResponses)+1)
+			if operation.Responses.Default != nil {
+				responses = append(responses, *operation.Responses.Default)
+			}
+			for _, response := range operation.Responses.StatusCodeResponses {
+				responses = append(responses, response)
+			}
+			echoes, location, attachment := false, false, false
+			for _, response := range responses {
+				_, ok := response.Extensions[echoesExt]
+				echoes = echoes || ok
+				_, ok = responseLocation(&response)
+				location = location || ok
+				attachment = attachment || isAttachment(&response)
+			}
+			if echoes && !a.opts.checkConsistency {
+				add(FeatureEchoes, "")
+			}
+			if location && !a.opts.checkLocation {
+				add(FeatureLocation, "")
+			}
+			if attachment && !a.opts.checkDisposition {
+				add(FeatureAttachment, "")
+			}
+			names := make(map[string]bool)
+			for _, response := range responses {
+				for name := range response.Headers {
+					names[name] = true
+				}
+			}
+			headers := make([]string, 0, len(names))
+			for name := range names {
+				headers = append(headers, name)
+			}
+			sort.Strings(headers)
+			for _, name := range headers {
+				add(FeatureResponseHeader, name)
+			}
+		}
+	}
+	return warnings
+}
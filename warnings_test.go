@@ -0,0 +1,61 @@
+package revisor
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVerifierV2_Unenforced(t *testing.T) {
+	var warnings []Warning
+	_, err := NewRequestVerifier(testdata+sampleV2YAML, WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+	require.NoError(t, err)
+
+	assert.Contains(t, warnings, Warning{"GET", "/user/login", FeatureQueryParameter, "username"})
+	assert.Contains(t, warnings, Warning{"GET", "/user/login", FeatureResponseHeader, "X-Rate-Limit"})
+	assert.Contains(t, warnings, Warning{"DELETE", "/pet/{petId}", FeatureHeaderParameter, "api_key"})
+	assert.NotContains(t, warnings, Warning{"DELETE", "/pet/{petId}", "path parameter", "petId"})
+	assert.Contains(t, warnings, Warning{"DELETE", "/pet/{petId}", FeatureSecurity, ""})
+	assert.Contains(t, warnings, Warning{"POST", "/pet/{petId}", FeatureFormDataParameter, "name"})
+	assert.Contains(t, warnings, Warning{"GET", "/store/inventory", FeatureRateLimit, ""})
+	assert.NotContains(t, warnings, Warning{"POST", "/pet/{petId}/uploadImage", FeatureFormDataParameter, "tag"})
+	assert.NotContains(t, warnings, Warning{"GET", "/user/{username}", "path parameter", "username"})
+
+	t.Run("warnings depend on options", func(t *testing.T) {
+		warnings = nil
+		_, err := NewRequestVerifier(testdata+sampleV2YAML,
+			EnforceRateLimits(NewMemoryRateLimitStore(), nil),
+			WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }),
+		)
+		require.NoError(t, err)
+		assert.NotContains(t, warnings, Warning{"GET", "/store/inventory", FeatureRateLimit, ""})
+	})
+
+	t.Run("warnings are logged with configured logger", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log.SetOutput(buf)
+		defer log.SetOutput(os.Stderr)
+		_, err := NewRequestVerifier(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		assert.NotContains(t, buf.String(), "is not enforced", "warnings are not logged by default")
+
+		var logged []string
+		_, err = NewRequestVerifier(testdata+sampleV2YAML, WithLogger(func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		}))
+		require.NoError(t, err)
+		assert.Contains(t, logged, "revisor: warning: GET /user/login: query parameter username is not enforced")
+	})
+
+	t.Run("warning is formatted", func(t *testing.T) {
+		w := Warning{"GET", "/user/login", FeatureQueryParameter, "username"}
+		assert.Equal(t, "GET /user/login: query parameter username is not enforced", w.String())
+	})
+}
@@ -0,0 +1,227 @@
+package revisor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// webhookAttempts limits attempts to deliver a batch
+	webhookAttempts = 4
+	// webhookBackoff is a delay before the first retry, doubled for every next one
+	webhookBackoff = time.Second
+	// webhookMaxPending limits distinct violations queued for the next batch
+	webhookMaxPending = 100
+	// webhookTimeout limits time of a single delivery
+	webhookTimeout = 10 * time.Second
+)
+
+// webhookViolation is a summary of violations of the operation with the same code
+type webhookViolation struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// webhookBatch is a batch of violations which delivery failed
+type webhookBatch struct {
+	violations []*webhookViolation
+	dropped    int
+	attempts   int
+	retryAt    time.Time
+}
+
+// WebhookNotifier verifies exchanges and posts batched summaries of violations
+// to a webhook, so that findings reach humans without a metrics stack. Violations
+// of an operation with the same Violation code, or the same message if they have
+// no code, are reported once with a count.
+type WebhookNotifier struct {
+	url      string
+	verify   func(*http.Response, *http.Request) error
+	slack    bool
+	client   *http.Client
+	now      func() time.Time
+	template func(*http.Request) string
+
+	// flushMu serializes flushes, so that a batch is delivered once
+	flushMu sync.Mutex
+	mu      sync.Mutex
+	pending []*webhookViolation
+	dropped int
+	// retry is the batch which delivery failed, violations queued afterwards
+	// aren't merged into it and are posted with following batches
+	retry *webhookBatch
+}
+
+// NewWebhookNotifier returns WebhookNotifier which verifies exchanges with verify
+// function, e.g. returned from NewVerifier, and posts violations to url. Batches
+// are posted as JSON list of violations, or as Slack-compatible message if slack
+// is set. Violations are grouped by request paths, unless SetTemplates is used.
+// Deliveries time out after 10 seconds.
+func NewWebhookNotifier(url string, verify func(*http.Response, *http.Request) error, slack bool) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		verify: verify,
+		slack:  slack,
+		client: &http.Client{Timeout: webhookTimeout},
+		now:    time.Now,
+		template: func(req *http.Request) string {
+			return req.URL.Path
+		},
+	}
+}
+
+// SetClock sets a source of time used to back off retries to post violations
+func (n *WebhookNotifier) SetClock(clock Clock) {
+	n.now = clock.Now
+}
+
+// SetTemplates sets Verifier which path templates group violations instead of
+// request paths, e.g. /pets/{id} instead of /pets/1 and /pets/2
+func (n *WebhookNotifier) SetTemplates(v *Verifier) {
+	n.template = func(req *http.Request) string {
+		if tmpl, _, ok := v.verifier().mapper.mapRequest(req); ok {
+			return tmpl
+		}
+		return req.URL.Path
+	}
+}
+
+// Verify verifies the exchange and queues its violation to be posted with the next batch,
+// nil request is reported as ErrNilInput
+func (n *WebhookNotifier) Verify(res *http.Response, req *http.Request) error {
+	if req == nil {
+		return ErrNilInput
+	}
+	err := n.verify(res, req)
+	if err != nil {
+		code := codeInvalid
+		if violation, ok := errors.Cause(err).(*Violation); ok {
+			code = violation.Code
+		}
+		v := &webhookViolation{Method: req.Method, Path: n.template(req), Code: code, Message: err.Error(), Count: 1}
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.add(v)
+	}
+	return err
+}
+
+// add queues the violation, merging it with the same queued one, violations
+// over webhookMaxPending are counted as dropped. It must be called with mu held.
+func (n *WebhookNotifier) add(v *webhookViolation) {
+	for _, p := range n.pending {
+		if p.Method == v.Method && p.Path == v.Path && p.Code == v.Code &&
+			(v.Code != codeInvalid || p.Message == v.Message) {
+			p.Count += v.Count
+			return
+		}
+	}
+	if len(n.pending) == webhookMaxPending {
+		n.dropped += v.Count
+		return
+	}
+	n.pending = append(n.pending, v)
+}
+
+// Flush posts queued violations. Batches of failed deliveries are retried
+// by following flushes with exponential backoff, flushes before the backoff
+// elapses post nothing, so that Flush doesn't block on retries. A batch is
+// dropped once webhookAttempts deliveries of it fail, violations queued
+// in the meantime are posted with following batches.
+func (n *WebhookNotifier) Flush() error {
+	n.flushMu.Lock()
+	defer n.flushMu.Unlock()
+
+	n.mu.Lock()
+	batch := n.retry
+	if batch == nil {
+		batch = &webhookBatch{violations: n.pending, dropped: n.dropped}
+		n.pending, n.dropped = nil, 0
+	}
+	n.mu.Unlock()
+	if len(batch.violations) == 0 || n.now().Before(batch.retryAt) {
+		return nil
+	}
+
+	payload, err := n.payload(batch.violations, batch.dropped)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode violations")
+	}
+	err = n.post(payload)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err == nil {
+		n.retry = nil
+		return nil
+	}
+	batch.attempts++
+	if batch.attempts == webhookAttempts {
+		n.retry = nil
+		return errors.Wrap(err, "failed to post violations, "+strconv.Itoa(len(batch.violations))+" of them are dropped")
+	}
+	batch.retryAt = n.now().Add(webhookBackoff << uint(batch.attempts-1))
+	n.retry = batch
+	return errors.Wrap(err, "failed to post violations")
+}
+
+// FlushEvery flushes queued violations every interval until ctx is done,
+// remaining violations are flushed before returning
+func (n *WebhookNotifier) FlushEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = n.Flush()
+		case <-ctx.Done():
+			_ = n.Flush()
+			return
+		}
+	}
+}
+
+func (n *WebhookNotifier) payload(batch []*webhookViolation, dropped int) ([]byte, error) {
+	if !n.slack {
+		return json.Marshal(struct {
+			Violations []*webhookViolation `json:"violations"`
+			Dropped    int                 `json:"dropped,omitempty"`
+		}{batch, dropped})
+	}
+	lines := []string{fmt.Sprintf("revisor found %d violation(s):", len(batch))}
+	for _, v := range batch {
+		line := "• " + v.Method + " " + v.Path + ": " + v.Message
+		if v.Count > 1 {
+			line += " (×" + strconv.Itoa(v.Count) + ")"
+		}
+		lines = append(lines, line)
+	}
+	if dropped > 0 {
+		lines = append(lines, fmt.Sprintf("%d more violation(s) are dropped", dropped))
+	}
+	return json.Marshal(map[string]string{"text": strings.Join(lines, "\n")})
+}
+
+func (n *WebhookNotifier) post(payload []byte) error {
+	res, err := n.client.Post(n.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return errors.New("webhook responded with " + res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package revisor
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookServer struct {
+	mu       sync.Mutex
+	failures int
+	payloads []string
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	s.payloads = append(s.payloads, string(body))
+}
+
+func failingVerify(res *http.Response, req *http.Request) error {
+	if req.URL.Path == "/valid" {
+		return nil
+	}
+	return errors.New("invalid " + req.URL.Query().Get("field"))
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		slack    bool
+		failures int
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "json",
+			want: `{"violations":[{"method":"GET","path":"/pet","code":"invalid","message":"invalid name","count":2},` +
+				`{"method":"GET","path":"/pet","code":"invalid","message":"invalid id","count":1}]}`,
+		},
+		{
+			name:  "slack",
+			slack: true,
+			want:  `{"text":"revisor found 2 violation(s):\n• GET /pet: invalid name (×2)\n• GET /pet: invalid id"}`,
+		},
+		{
+			name:     "retried",
+			failures: 2,
+			want: `{"violations":[{"method":"GET","path":"/pet","code":"invalid","message":"invalid name","count":2},` +
+				`{"method":"GET","path":"/pet","code":"invalid","message":"invalid id","count":1}]}`,
+		},
+		{
+			name:     "gave up",
+			failures: webhookAttempts,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &webhookServer{failures: tt.failures}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			clock := NewManualClock(clockEpoch)
+			n := NewWebhookNotifier(ts.URL, failingVerify, tt.slack)
+			n.SetClock(clock)
+
+			for _, url := range []string{"/pet?field=name", "/valid", "/pet?field=id", "/pet?field=name"} {
+				_ = n.Verify(nil, httptest.NewRequest("GET", url, nil))
+			}
+			var err error
+			for attempt := 1; ; attempt++ {
+				err = n.Flush()
+				if err == nil || attempt == webhookAttempts {
+					break
+				}
+				require.NotNil(t, n.retry)
+				assert.Len(t, n.retry.violations, 2, "undelivered violations are retried")
+				assert.NoError(t, n.Flush())
+				assert.Len(t, server.payloads, 0, "retry is backed off")
+				clock.Advance(webhookBackoff << uint(attempt-1))
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, server.payloads)
+				assert.Nil(t, n.retry, "violations are dropped after the last attempt")
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, server.payloads, 1)
+			assert.Equal(t, tt.want, server.payloads[0])
+
+			require.NoError(t, n.Flush())
+			assert.Len(t, server.payloads, 1, "empty batch is not posted")
+		})
+	}
+}
+
+func TestWebhookNotifier_QueuedWhileRetried(t *testing.T) {
+	server := &webhookServer{failures: webhookAttempts}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	clock := NewManualClock(clockEpoch)
+	n := NewWebhookNotifier(ts.URL, failingVerify, false)
+	n.SetClock(clock)
+
+	_ = n.Verify(nil, httptest.NewRequest("GET", "/pet?field=name", nil))
+	for attempt := 1; attempt < webhookAttempts; attempt++ {
+		assert.Error(t, n.Flush())
+		_ = n.Verify(nil, httptest.NewRequest("GET", "/pet?field=name", nil))
+		clock.Advance(webhookBackoff << uint(attempt-1))
+	}
+	assert.Regexp(t, "1 of them are dropped", n.Flush())
+	require.Empty(t, server.payloads)
+
+	require.NoError(t, n.Flush())
+	require.Len(t, server.payloads, 1, "violations queued while the batch is retried are posted")
+	assert.Equal(t, `{"violations":[{"method":"GET","path":"/pet","code":"invalid","message":"invalid name","count":3}]}`,
+		server.payloads[0])
+}
+
+func TestWebhookNotifier_SetTemplates(t *testing.T) {
+	server := &webhookServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	v, err := New(testdata + sampleV2YAML)
+	require.NoError(t, err)
+	verify := func(res *http.Response, req *http.Request) error {
+		return newViolation(CodeInvalidRange, "invalid range of "+req.URL.Path)
+	}
+	n := NewWebhookNotifier(ts.URL, verify, false)
+	n.SetTemplates(v)
+	for _, url := range []string{"/v2/pet/1", "/v2/pet/2", "/v2/unknown"} {
+		_ = n.Verify(nil, httptest.NewRequest("GET", url, nil))
+	}
+	require.NoError(t, n.Flush())
+	require.Len(t, server.payloads, 1)
+	assert.Equal(t, `{"violations":[`+
+		`{"method":"GET","path":"/pet/{petId}","code":"invalid_range","message":"invalid_range: invalid range of /v2/pet/1","count":2},`+
+		`{"method":"GET","path":"/v2/unknown","code":"invalid_range","message":"invalid_range: invalid range of /v2/unknown","count":1}]}`,
+		server.payloads[0])
+}
+
+func TestWebhookNotifier_MaxPending(t *testing.T) {
+	server := &webhookServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	n := NewWebhookNotifier(ts.URL, failingVerify, false)
+	for i := 0; i <= webhookMaxPending; i++ {
+		_ = n.Verify(nil, httptest.NewRequest("GET", "/pet?field="+strconv.Itoa(i), nil))
+	}
+	assert.Len(t, n.pending, webhookMaxPending)
+	require.NoError(t, n.Flush())
+	require.Len(t, server.payloads, 1)
+	assert.Contains(t, server.payloads[0], `"dropped":1`)
+}
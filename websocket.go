@@ -0,0 +1,106 @@
+package revisor
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// websocketExt is an operation extension which declares that the operation is
+// a WebSocket endpoint, e.g.
+//
+//	x-websocket: true
+//
+// Operations documenting 101 Switching Protocols response are recognized as
+// WebSocket endpoints as well. Only opening handshake of such operations is
+// verified: requests must be valid handshake requests without body, and 101
+// responses, which are accepted even if they are not documented, must accept
+// the handshake. Bodies are not read, as connections are hijacked after the
+// handshake. Invalid handshakes are reported as Violation with
+// CodeInvalidHandshake code.
+const websocketExt = "x-websocket"
+
+// websocketGUID is appended to Sec-WebSocket-Key to compute Sec-WebSocket-Accept
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// isWebSocket checks if the operation is a WebSocket endpoint
+func isWebSocket(operation *spec.Operation) bool {
+	if ws, _ := operation.Extensions[websocketExt].(bool); ws {
+		return true
+	}
+	if operation.Responses == nil {
+		return false
+	}
+	_, ok := operation.Responses.StatusCodeResponses[http.StatusSwitchingProtocols]
+	return ok
+}
+
+// verifyWebSocketRequest checks opening handshake of requests to WebSocket endpoints,
+// handled return parameter reports if the request was verified as handshake
+func (a *apiVerifier) verifyWebSocketRequest(req *http.Request) (handled bool, err error) {
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !isWebSocket(operation) {
+		return false, nil
+	}
+	if req.Method != http.MethodGet {
+		return true, newViolation(CodeInvalidHandshake, "handshake method must be GET")
+	}
+	if !headerContainsToken(req.Header, "Upgrade", "websocket") ||
+		!headerContainsToken(req.Header, "Connection", "upgrade") {
+		return true, newViolation(CodeInvalidHandshake, "request is not a WebSocket upgrade")
+	}
+	key, err := base64.StdEncoding.DecodeString(req.Header.Get("Sec-WebSocket-Key"))
+	if err != nil || len(key) != 16 {
+		return true, newViolation(CodeInvalidHandshake, "Sec-WebSocket-Key must be base64 encoded 16 bytes")
+	}
+	if req.Header.Get("Sec-WebSocket-Version") != "13" {
+		return true, newViolation(CodeInvalidHandshake, "Sec-WebSocket-Version must be 13")
+	}
+	if req.ContentLength > 0 {
+		return true, newViolation(CodeInvalidHandshake, "handshake request must not have body")
+	}
+	return true, nil
+}
+
+// verifyWebSocketResponse checks that 101 response accepts opening handshake,
+// handled return parameter reports if the response was verified as handshake
+func (a *apiVerifier) verifyWebSocketResponse(req *http.Request, res *http.Response) (handled bool, err error) {
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		return false, nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil || !isWebSocket(operation) {
+		return false, nil
+	}
+	if !headerContainsToken(res.Header, "Upgrade", "websocket") ||
+		!headerContainsToken(res.Header, "Connection", "upgrade") {
+		return true, newViolation(CodeInvalidHandshake, "response is not a WebSocket upgrade")
+	}
+	if accept := res.Header.Get("Sec-WebSocket-Accept"); accept != websocketAccept(req.Header.Get("Sec-WebSocket-Key")) {
+		return true, newViolation(CodeInvalidHandshake, "Sec-WebSocket-Accept doesn't match Sec-WebSocket-Key")
+	}
+	return true, nil
+}
+
+// websocketAccept returns Sec-WebSocket-Accept of the handshake with the key
+func websocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContainsToken checks if comma separated list of the header contains
+// the token, tokens are case-insensitive
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// websocketKey is the key of opening handshake example of RFC 6455
+const websocketKey = "dGhlIHNhbXBsZSBub25jZQ=="
+
+func TestWebSocket(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		method       string
+		header       map[string]string
+		body         string
+		wantReqCode  string
+		status       int
+		resHeader    map[string]string
+		resBody      string
+		wantRespCode string
+		wantRespErr  string
+	}{
+		{
+			name:   "handshake",
+			path:   "/v1/chat",
+			status: 101,
+		},
+		{
+			name:   "documented switching protocols",
+			path:   "/v1/feed",
+			status: 101,
+		},
+		{
+			name:   "token lists",
+			path:   "/v1/chat",
+			header: map[string]string{"Connection": "keep-alive, Upgrade", "Upgrade": "WebSocket"},
+			status: 101,
+		},
+		{
+			name:        "not an upgrade",
+			path:        "/v1/chat",
+			header:      map[string]string{"Upgrade": ""},
+			wantReqCode: CodeInvalidHandshake,
+		},
+		{
+			name:        "invalid key",
+			path:        "/v1/chat",
+			header:      map[string]string{"Sec-WebSocket-Key": "c2hvcnQ="},
+			wantReqCode: CodeInvalidHandshake,
+		},
+		{
+			name:        "unsupported version",
+			path:        "/v1/chat",
+			header:      map[string]string{"Sec-WebSocket-Version": "8"},
+			wantReqCode: CodeInvalidHandshake,
+		},
+		{
+			name:        "body",
+			path:        "/v1/chat",
+			body:        `{}`,
+			wantReqCode: CodeInvalidHandshake,
+		},
+		{
+			name:        "method",
+			path:        "/v1/chat",
+			method:      "POST",
+			wantReqCode: CodeUndocumentedMethod,
+		},
+		{
+			name:         "accept doesn't match",
+			path:         "/v1/chat",
+			status:       101,
+			resHeader:    map[string]string{"Sec-WebSocket-Accept": "invalid"},
+			wantRespCode: CodeInvalidHandshake,
+		},
+		{
+			name:         "response is not an upgrade",
+			path:         "/v1/chat",
+			status:       101,
+			resHeader:    map[string]string{"Upgrade": "h2c"},
+			wantRespCode: CodeInvalidHandshake,
+		},
+		{
+			name:        "rejected handshake is validated",
+			path:        "/v1/chat",
+			status:      400,
+			resHeader:   map[string]string{"Content-Type": "application/json"},
+			resBody:     `{}`,
+			wantRespErr: "message in body is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata + "websocket.yaml")
+			require.NoError(t, err)
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+
+			req := httptest.NewRequest(method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Sec-WebSocket-Key", websocketKey)
+			req.Header.Set("Sec-WebSocket-Version", "13")
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			err = v.VerifyRequest(req)
+			if tt.wantReqCode != "" {
+				assertVerifyErr(t, err, "", tt.wantReqCode)
+				return
+			}
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Connection", "Upgrade")
+			rec.Header().Set("Upgrade", "websocket")
+			rec.Header().Set("Sec-WebSocket-Accept", "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+			for k, v := range tt.resHeader {
+				rec.Header().Set(k, v)
+			}
+			rec.WriteHeader(tt.status)
+			rec.Body = bytes.NewBufferString(tt.resBody)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), tt.wantRespErr, tt.wantRespCode)
+		})
+	}
+}
+
+func TestWebSocketAccept(t *testing.T) {
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", websocketAccept(websocketKey))
+}
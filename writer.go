@@ -0,0 +1,174 @@
+package revisor
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DevelopmentMode makes ValidatedResponseWriter replace responses which violate
+// OpenAPI definition with 500 Internal Server Error and log the violation,
+// so that contract bugs are noticed early.
+func DevelopmentMode(a *apiVerifier) {
+	a.opts.developmentMode = true
+}
+
+// WithLogger sets a function used to log violations, log.Printf is used by default.
+// Warnings about the definition found at load time are only logged with the logger
+// set, see WithWarningHandler.
+func WithLogger(logf func(format string, args ...interface{})) option {
+	return func(a *apiVerifier) {
+		a.opts.logf = logf
+		a.opts.loggerSet = true
+	}
+}
+
+// NewResponseWriterFactory returns a function that wraps http.ResponseWriter of the
+// request into ValidatedResponseWriter, which verifies the response against OpenAPI
+// definition before it is written. Writers should be created when handling of the
+// request starts, so that they measure handling time checked against latency budget.
+func NewResponseWriterFactory(definitionPath string, options ...option) (func(http.ResponseWriter, *http.Request) *ValidatedResponseWriter, error) {
+	a, err := newInitializedVerifier(definitionPath, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create response writer factory")
+	}
+	return func(w http.ResponseWriter, req *http.Request) *ValidatedResponseWriter {
+		return newValidatedResponseWriter(w, req, a)
+	}, nil
+}
+
+// ValidatedResponseWriter is http.ResponseWriter which buffers the response and
+// verifies its status code, content type and body against OpenAPI definition
+// before the bytes are written to the underlying writer with Commit.
+// Responses which aren't sampled, see WithSampleRate, are written to the
+// underlying writer as they are, without buffering.
+type ValidatedResponseWriter struct {
+	w         http.ResponseWriter
+	req       *http.Request
+	a         *apiVerifier
+	header    http.Header
+	status    int
+	body      bytes.Buffer
+	committed bool
+	started   time.Time
+	// sampled reports if the response is verified
+	sampled bool
+	// logged reports if Commit logged the error it returned
+	logged bool
+}
+
+func newValidatedResponseWriter(w http.ResponseWriter, req *http.Request, a *apiVerifier) *ValidatedResponseWriter {
+	v := &ValidatedResponseWriter{w: w, req: req, a: a, started: a.opts.clock.Now(), sampled: a.sampled()}
+	v.header = make(http.Header)
+	if !v.sampled {
+		v.header = w.Header()
+	}
+	return v
+}
+
+// Header returns the header map of buffered response
+func (v *ValidatedResponseWriter) Header() http.Header {
+	return v.header
+}
+
+// WriteHeader sets status code of buffered response
+func (v *ValidatedResponseWriter) WriteHeader(status int) {
+	if v.status != 0 {
+		return
+	}
+	v.status = status
+	if !v.sampled {
+		v.w.WriteHeader(status)
+	}
+}
+
+// Write appends data to the body of buffered response
+func (v *ValidatedResponseWriter) Write(b []byte) (int, error) {
+	if v.committed {
+		return 0, errors.New("response is already committed")
+	}
+	v.WriteHeader(http.StatusOK)
+	if !v.sampled {
+		return v.w.Write(b)
+	}
+	return v.body.Write(b)
+}
+
+// Flush flushes the underlying writer if it implements http.Flusher.
+// Buffered responses aren't written before Commit, so that flushing
+// doesn't send them earlier.
+func (v *ValidatedResponseWriter) Flush() {
+	if f, ok := v.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack takes over the connection of the underlying writer, if it implements
+// http.Hijacker. Buffered response is discarded and isn't verified.
+func (v *ValidatedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := v.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("underlying response writer doesn't support hijacking")
+	}
+	conn, rw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	v.committed = true
+	return conn, rw, nil
+}
+
+// Commit verifies buffered response and writes it to the underlying writer.
+// Verification error is returned, in development mode it is also logged and
+// 500 Internal Server Error is written instead of the response. If the response
+// is valid, but exceeds latency budget of the operation, it is written and
+// Violation with CodeSLOExceeded code is returned.
+func (v *ValidatedResponseWriter) Commit() error {
+	if v.committed {
+		return errors.New("response is already committed")
+	}
+	v.committed = true
+	v.WriteHeader(http.StatusOK)
+	// handling time excludes verification, which isn't a part of the budget
+	handled := v.a.opts.clock.Now().Sub(v.started)
+	if !v.sampled {
+		return v.a.verifyLatency(v.req, handled)
+	}
+
+	res := &http.Response{
+		StatusCode:    v.status,
+		Header:        v.header,
+		Body:          ioutil.NopCloser(bytes.NewReader(v.body.Bytes())),
+		ContentLength: int64(v.body.Len()),
+		Request:       v.req,
+	}
+	err := v.a.verifyMeasuredResponse(res, v.req)
+	if err != nil && v.a.opts.developmentMode {
+		v.a.opts.logf("revisor: %s %s: invalid response: %v", v.req.Method, v.req.URL.Path, err)
+		v.logged = true
+		http.Error(v.w, "response violates API definition: "+err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	for k, values := range v.header {
+		v.w.Header()[k] = values
+	}
+	if v.w.Header().Get("Content-Length") == "" {
+		v.w.Header().Set("Content-Length", strconv.Itoa(v.body.Len()))
+	}
+	v.w.WriteHeader(v.status)
+	_, writeErr := v.w.Write(v.body.Bytes())
+	if err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return errors.Wrap(writeErr, "failed to write response")
+	}
+	return v.a.verifyLatency(v.req, handled)
+}
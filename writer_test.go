@@ -0,0 +1,134 @@
+package revisor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatedResponseWriter(t *testing.T) {
+	var logged []string
+	logf := func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+
+	tests := []struct {
+		name    string
+		options []option
+		payload string
+		status  int
+		body    string
+		err     string
+	}{
+		{
+			"valid response",
+			nil,
+			`{"id":1}`,
+			http.StatusOK,
+			`{"id":1}`,
+			"",
+		},
+		{
+			"invalid response is written",
+			nil,
+			`{"username":"test-user"}`,
+			http.StatusOK,
+			`{"username":"test-user"}`,
+			".id in body is required",
+		},
+		{
+			"invalid response is replaced in development mode",
+			[]option{DevelopmentMode, WithLogger(logf)},
+			`{"username":"test-user"}`,
+			http.StatusInternalServerError,
+			"response violates API definition",
+			".id in body is required",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newWriter, err := NewResponseWriterFactory(testdata+sampleV2YAML, test.options...)
+			require.NoError(t, err)
+			logged = nil
+
+			rec := httptest.NewRecorder()
+			w := newWriter(rec, httptest.NewRequest("GET", "/v2/user/testuser", nil))
+			w.Header().Set("Content-Type", "application/json")
+			_, err = w.Write([]byte(test.payload))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Empty(t, rec.Body.String())
+
+			err = w.Commit()
+			if test.err != "" {
+				assert.Regexp(t, test.err, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), test.body)
+			if test.status == http.StatusInternalServerError {
+				require.Len(t, logged, 1)
+				assert.Contains(t, logged[0], "GET /v2/user/testuser: invalid response")
+			}
+		})
+	}
+
+	t.Run("response is committed once", func(t *testing.T) {
+		newWriter, err := NewResponseWriterFactory(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		w := newWriter(httptest.NewRecorder(), httptest.NewRequest("GET", "/v2/user/testuser", nil))
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(`{"id":1}`))
+		require.NoError(t, err)
+		assert.NoError(t, w.Commit())
+		assert.Regexp(t, "response is already committed", w.Commit())
+		_, err = w.Write([]byte("{}"))
+		assert.Regexp(t, "response is already committed", err)
+	})
+
+	t.Run("unsampled response is written as it is", func(t *testing.T) {
+		newWriter, err := NewResponseWriterFactory(testdata+sampleV2YAML, WithSampleRate(0))
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		w := newWriter(rec, httptest.NewRequest("GET", "/v2/user/testuser", nil))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, err = w.Write([]byte(`{"username":"test-user"}`))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, `{"username":"test-user"}`, rec.Body.String())
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.NoError(t, w.Commit())
+	})
+
+	t.Run("flush and hijack are forwarded", func(t *testing.T) {
+		newWriter, err := NewResponseWriterFactory(testdata + sampleV2YAML)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		w := newWriter(rec, httptest.NewRequest("GET", "/v2/user/testuser", nil))
+		w.Flush()
+		assert.True(t, rec.Flushed)
+		_, _, err = w.Hijack()
+		assert.Regexp(t, "doesn't support hijacking", err)
+	})
+
+	t.Run("verification is measured", func(t *testing.T) {
+		sink := &recordingSink{}
+		newWriter, err := NewResponseWriterFactory(testdata+sampleV2YAML, WithMetrics(sink))
+		require.NoError(t, err)
+		w := newWriter(httptest.NewRecorder(), httptest.NewRequest("GET", "/v2/user/testuser", nil))
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(`{"username":"test-user"}`))
+		require.NoError(t, err)
+		assert.Error(t, w.Commit())
+		assert.Equal(t, []string{
+			"verifications kind=response operation=getUserByName",
+			"violations code=invalid kind=response operation=getUserByName",
+		}, sink.counters)
+	})
+}
@@ -0,0 +1,244 @@
+package revisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/pkg/errors"
+)
+
+// xmlToleranceExt is an operation extension which enables tolerance mode for
+// XML bodies, e.g. SOAP envelopes which can't be described by Swagger 2 schemas.
+// Instead of validating against schema, XML bodies are checked to be well-formed
+// and fields addressed with XPath are validated against simple constraints,
+// which are the same as for non-body parameters, e.g.
+//
+//	x-xml-tolerance:
+//	  request:
+//	    - xpath: /Envelope/Body/GetPrice/Item
+//	      required: true
+//	      type: string
+//	      minLength: 1
+//	  response:
+//	    - xpath: //Price
+//	      type: number
+//	      minimum: 0
+//
+// Only a subset of XPath is supported: child (/) and descendant (//) steps
+// matching elements by local name or any element (*), optionally followed
+// by attribute step (@name). Namespaces are ignored, so that prefixes
+// of names, e.g. soap:Body, are optional.
+const xmlToleranceExt = "x-xml-tolerance"
+
+type xmlTolerance struct {
+	Request  []xmlField `json:"request"`
+	Response []xmlField `json:"response"`
+}
+
+type xmlField struct {
+	XPath    string `json:"xpath"`
+	Required bool   `json:"required"`
+	spec.SimpleSchema
+	spec.CommonValidations
+}
+
+// xmlNode is an element of parsed XML document
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	text     bytes.Buffer
+	children []*xmlNode
+}
+
+// verifyXMLTolerance verifies XML body of the request, or the response if it
+// is not nil, in tolerance mode configured with x-xml-tolerance extension.
+// handled return parameter reports if body was verified in tolerance mode
+func (a *apiVerifier) verifyXMLTolerance(req *http.Request, res *http.Response, body []byte) (handled bool, err error) {
+	contentType := req.Header.Get("Content-Type")
+	if res != nil {
+		contentType = res.Header.Get("Content-Type")
+	}
+	if !strings.Contains(contentType, "xml") {
+		return false, nil
+	}
+	_, operation, err := a.getOperationDef(req)
+	if err != nil {
+		return false, nil
+	}
+	tolerance, ok, err := operationXMLTolerance(operation)
+	if err != nil || !ok {
+		return ok, err
+	}
+	root, err := parseXML(body)
+	if err != nil {
+		return true, err
+	}
+	fields := tolerance.Request
+	if res != nil {
+		fields = tolerance.Response
+	}
+	for _, field := range fields {
+		err = field.verify(root)
+		if err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func operationXMLTolerance(operation *spec.Operation) (*xmlTolerance, bool, error) {
+	ext, ok := operation.Extensions[xmlToleranceExt]
+	if !ok {
+		return nil, false, nil
+	}
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		return nil, true, errors.Wrap(err, "failed to read "+xmlToleranceExt)
+	}
+	var tolerance xmlTolerance
+	err = json.Unmarshal(raw, &tolerance)
+	if err != nil {
+		return nil, true, errors.Wrap(err, "failed to parse "+xmlToleranceExt)
+	}
+	return &tolerance, true, nil
+}
+
+// verify validates values found at XPath of the field
+func (f *xmlField) verify(root *xmlNode) error {
+	values, err := selectXPath(root, f.XPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse "+xmlToleranceExt)
+	}
+	if len(values) == 0 && f.Required {
+		return errors.New(f.XPath + " in body is required")
+	}
+	schema := parameterSchema(&f.SimpleSchema, &f.CommonValidations)
+	for _, value := range values {
+		converted, err := convertSimple(f.Type, value)
+		if err != nil {
+			return errors.Wrap(err, f.XPath+" in body is not valid")
+		}
+		err = validate.AgainstSchema(schema, converted, strfmt.Default)
+		if err != nil {
+			return errors.Wrap(err, f.XPath+" in body is not valid")
+		}
+	}
+	return nil
+}
+
+// parseXML checks that body is well-formed XML document and returns its root element
+func parseXML(body []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	document := &xmlNode{}
+	stack := []*xmlNode{document}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "body is not well-formed XML")
+		}
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			if parent == document && len(document.children) > 0 {
+				return nil, errors.New("body is not well-formed XML: more than one root element")
+			}
+			node := &xmlNode{name: t.Name.Local, attrs: t.Attr}
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.text.Write(t)
+		}
+	}
+	if len(stack) > 1 {
+		return nil, errors.New("body is not well-formed XML: unclosed element " + stack[len(stack)-1].name)
+	}
+	if len(document.children) == 0 {
+		return nil, errors.New("body is not well-formed XML: root element is missing")
+	}
+	return document, nil
+}
+
+// selectXPath returns text of elements or values of attributes found at XPath
+func selectXPath(document *xmlNode, xpath string) ([]string, error) {
+	if !strings.HasPrefix(xpath, "/") {
+		return nil, errors.New("XPath must be absolute: " + xpath)
+	}
+	nodes := []*xmlNode{document}
+	rest := xpath
+	for rest != "" {
+		descendant := strings.HasPrefix(rest, "//")
+		rest = strings.TrimLeft(rest, "/")
+		step := rest
+		if i := strings.Index(rest, "/"); i >= 0 {
+			step, rest = rest[:i], rest[i:]
+		} else {
+			rest = ""
+		}
+		if step == "" || strings.ContainsAny(step, "[]()|") {
+			return nil, errors.New("XPath is not supported: " + xpath)
+		}
+		attribute := strings.HasPrefix(step, "@")
+		name := strings.TrimPrefix(step, "@")
+		if i := strings.Index(name, ":"); i >= 0 {
+			name = name[i+1:]
+		}
+		if attribute {
+			if rest != "" {
+				return nil, errors.New("XPath is not supported: " + xpath)
+			}
+			return attributeValues(nodes, name, descendant), nil
+		}
+		var matched []*xmlNode
+		for _, node := range nodes {
+			matched = append(matched, matchChildren(node, name, descendant)...)
+		}
+		nodes = matched
+	}
+	values := make([]string, len(nodes))
+	for i, node := range nodes {
+		values[i] = strings.TrimSpace(node.text.String())
+	}
+	return values, nil
+}
+
+// matchChildren returns children, or all descendants, of the node with the name
+func matchChildren(node *xmlNode, name string, descendant bool) []*xmlNode {
+	var matched []*xmlNode
+	for _, child := range node.children {
+		if name == "*" || child.name == name {
+			matched = append(matched, child)
+		}
+		if descendant {
+			matched = append(matched, matchChildren(child, name, true)...)
+		}
+	}
+	return matched
+}
+
+// attributeValues returns values of the attribute of nodes, or their descendants
+func attributeValues(nodes []*xmlNode, name string, descendant bool) []string {
+	var values []string
+	for _, node := range nodes {
+		for _, attr := range node.attrs {
+			if attr.Name.Local == name {
+				values = append(values, attr.Value)
+			}
+		}
+		if descendant {
+			values = append(values, attributeValues(node.children, name, true)...)
+		}
+	}
+	return values
+}
@@ -0,0 +1,114 @@
+package revisor
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const soapEnvelope = `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">
+  <soap:Body>%s</soap:Body>
+</soap:Envelope>`
+
+func soapBody(body string) string {
+	return strings.Replace(soapEnvelope, "%s", body, 1)
+}
+
+func TestXMLTolerance(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     string
+		response    string
+		wantReqErr  string
+		wantRespErr string
+	}{
+		{
+			name:     "valid",
+			request:  soapBody(`<GetPrice><Item>Apples</Item></GetPrice>`),
+			response: soapBody(`<GetPriceResponse><Price currency="EUR">1.90</Price></GetPriceResponse>`),
+		},
+		{
+			name:        "not well-formed",
+			request:     soapBody(`<GetPrice><Item>Apples</GetPrice>`),
+			response:    `<Price>1.90</Price><Price>2.00</Price>`,
+			wantReqErr:  "body is not well-formed XML",
+			wantRespErr: "more than one root element",
+		},
+		{
+			name:        "missing fields",
+			request:     soapBody(`<GetPrice/>`),
+			response:    soapBody(`<GetPriceResponse/>`),
+			wantReqErr:  "/soap:Envelope/soap:Body/GetPrice/Item in body is required",
+			wantRespErr: "//Price in body is required",
+		},
+		{
+			name:        "invalid fields",
+			request:     soapBody(`<GetPrice><Item></Item></GetPrice>`),
+			response:    soapBody(`<GetPriceResponse><Price>cheap</Price></GetPriceResponse>`),
+			wantReqErr:  "should be at least 1 chars long",
+			wantRespErr: `//Price in body is not valid: "cheap" is not a number`,
+		},
+		{
+			name:        "invalid attribute",
+			request:     soapBody(`<GetPrice><Item>Apples</Item></GetPrice>`),
+			response:    soapBody(`<GetPriceResponse><Price currency="GBP">1.90</Price></GetPriceResponse>`),
+			wantRespErr: "//Price/@currency in body is not valid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(testdata + "soap.yaml")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "/v1/prices", strings.NewReader(tt.request))
+			req.Header.Set("Content-Type", "text/xml")
+			assertVerifyErr(t, v.VerifyRequest(req), tt.wantReqErr, "")
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", "text/xml; charset=utf-8")
+			rec.Body = bytes.NewBufferString(tt.response)
+			assertVerifyErr(t, v.VerifyResponse(rec.Result(), req), tt.wantRespErr, "")
+		})
+	}
+}
+
+func TestSelectXPath(t *testing.T) {
+	document, err := parseXML([]byte(`<a xmlns:x="urn:x"><x:b id="1">one</x:b><c><b id="2"> two </b></c></a>`))
+	require.NoError(t, err)
+	tests := []struct {
+		xpath   string
+		want    []string
+		wantErr bool
+	}{
+		{xpath: "/a/b", want: []string{"one"}},
+		{xpath: "/a/x:b", want: []string{"one"}},
+		{xpath: "//b", want: []string{"one", "two"}},
+		{xpath: "/a/*/b", want: []string{"two"}},
+		{xpath: "//b/@id", want: []string{"1", "2"}},
+		{xpath: "/a//@id", want: []string{"1", "2"}},
+		{xpath: "/a/d", want: []string{}},
+		{xpath: "a/b", wantErr: true},
+		{xpath: "/a/b[1]", wantErr: true},
+		{xpath: "/a/@id/b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.xpath, func(t *testing.T) {
+			got, err := selectXPath(document, tt.xpath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if len(tt.want) == 0 {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}